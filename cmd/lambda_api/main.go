@@ -1,19 +1,17 @@
 package main
 
 import (
-	"context"
 	"log"
 	"stori-challenge/internal/infra/bootstrap"
 	"stori-challenge/internal/infra/config"
 	"stori-challenge/internal/infra/logger"
+	"stori-challenge/internal/interfaces/in/s3event"
 
-	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
 	"go.uber.org/zap"
-	"golang.org/x/sync/errgroup"
 )
 
-var appCtx *bootstrap.AppContext
+var s3Handler *s3event.Handler
 
 func init() {
 	if err := logger.Init(); err != nil {
@@ -31,68 +29,24 @@ func init() {
 		zap.String("s3_bucket", cfg.S3BucketName),
 		zap.String("s3_region", cfg.S3Region),
 		zap.String("ssl_mode", cfg.DBSSLMode),
+		zap.Int("s3_event_concurrency", cfg.S3EventConcurrency),
 	)
 
-	appCtx, err = bootstrap.InitializeApp(cfg)
+	appCtx, err := bootstrap.InitializeApp(cfg)
 	if err != nil {
 		logger.Logger.Fatal("error inicializando aplicación", zap.Error(err))
 	}
-}
-
-func processS3Record(ctx context.Context, rec events.S3EventRecord) error {
-	bucket := rec.S3.Bucket.Name
-	key := rec.S3.Object.Key
-
-	logger.Logger.Info("procesando objeto S3",
-		zap.String("bucket", bucket),
-		zap.String("key", key),
-	)
 
-	if err := appCtx.SummaryUseCase.ProcessTransactionsFromObject(ctx, bucket, key); err != nil {
-		logger.Logger.Error("error procesando transacciones",
-			zap.String("bucket", bucket),
-			zap.String("key", key),
-			zap.Error(err),
-		)
-		return err
-	}
-	return nil
-}
-
-func handler(ctx context.Context, evt events.S3Event) error {
-	logger.Logger.Info("evento S3 recibido",
-		zap.Int("records", len(evt.Records)),
+	s3Handler = s3event.NewHandler(
+		appCtx.SummaryUseCase,
+		logger.Logger,
+		s3event.WithConcurrency(cfg.S3EventConcurrency),
+		s3event.WithPendingRetries(),
 	)
-
-	if len(evt.Records) == 1 {
-		if err := processS3Record(ctx, evt.Records[0]); err != nil {
-			return err
-		}
-		logger.Logger.Info("evento S3 procesado correctamente")
-		return nil
-	}
-
-	g, ctx := errgroup.WithContext(ctx)
-	g.SetLimit(4)
-
-	for _, rec := range evt.Records {
-		r := rec
-		g.Go(func() error {
-			return processS3Record(ctx, r)
-		})
-	}
-
-	if err := g.Wait(); err != nil {
-		logger.Logger.Error("falló al menos un procesamiento de objeto S3", zap.Error(err))
-		return err
-	}
-
-	logger.Logger.Info("evento S3 procesado correctamente (procesamiento concurrente)")
-	return nil
 }
 
 func main() {
 	defer logger.Sync()
 	log.Println("Lambda S3 de Stori iniciando...")
-	lambda.Start(handler)
+	lambda.Start(s3Handler.Handle)
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"log"
+	"stori-challenge/internal/infra/bootstrap"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/infra/logger"
+	"stori-challenge/internal/interfaces/in/httpapi"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+)
+
+var apiHandler *httpapi.Handler
+
+func init() {
+	if err := logger.Init(); err != nil {
+		log.Fatalf("error iniciando logger: %v", err)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Logger.Fatal("error cargando configuración", zap.Error(err))
+	}
+
+	logger.Logger.Info("configuración cargada",
+		zap.String("db_host", cfg.DBHost),
+		zap.String("db_name", cfg.DBName),
+		zap.String("s3_bucket", cfg.S3BucketName),
+		zap.String("s3_region", cfg.S3Region),
+	)
+
+	appCtx, err := bootstrap.InitializeApp(cfg)
+	if err != nil {
+		logger.Logger.Fatal("error inicializando aplicación", zap.Error(err))
+	}
+
+	apiHandler = httpapi.NewHandler(
+		appCtx.UploadUseCase,
+		appCtx.HistoryUseCase,
+		appCtx.PreviewUseCase,
+		logger.Logger,
+	)
+}
+
+func main() {
+	defer logger.Sync()
+	log.Println("Lambda HTTP de Stori iniciando...")
+	lambda.Start(apiHandler.Handle)
+}
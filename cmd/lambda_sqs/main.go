@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+	"stori-challenge/internal/infra/bootstrap"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/infra/logger"
+	"stori-challenge/internal/interfaces/in/sqsevent"
+
+	"github.com/aws/aws-lambda-go/lambda"
+	"go.uber.org/zap"
+)
+
+var sqsHandler *sqsevent.Handler
+
+func init() {
+	if err := logger.Init(); err != nil {
+		log.Fatalf("error iniciando logger: %v", err)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Logger.Fatal("error cargando configuración", zap.Error(err))
+	}
+
+	logger.Logger.Info("configuración cargada",
+		zap.String("db_host", cfg.DBHost),
+		zap.String("db_name", cfg.DBName),
+		zap.String("s3_bucket", cfg.S3BucketName),
+		zap.String("s3_region", cfg.S3Region),
+		zap.Int("sqs_max_receive_count", cfg.SQSMaxReceiveCount),
+		zap.Int("sqs_concurrency", cfg.SQSConcurrency),
+	)
+
+	appCtx, err := bootstrap.InitializeApp(cfg)
+	if err != nil {
+		logger.Logger.Fatal("error inicializando aplicación", zap.Error(err))
+	}
+
+	sqsHandler = sqsevent.NewHandler(
+		appCtx.SummaryUseCase,
+		appCtx.DeadLetterUseCase,
+		logger.Logger,
+		sqsevent.WithMaxReceiveCount(cfg.SQSMaxReceiveCount),
+		sqsevent.WithConcurrency(cfg.SQSConcurrency),
+	)
+}
+
+func main() {
+	defer logger.Sync()
+	log.Println("Lambda SQS de Stori iniciando...")
+	lambda.Start(sqsHandler.Handle)
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"stori-challenge/internal/infra/bootstrap"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/infra/logger"
+	"stori-challenge/internal/interfaces/in/httpapi"
+	"stori-challenge/internal/interfaces/in/s3event"
+
+	"go.uber.org/zap"
+)
+
+const shutdownTimeout = 15 * time.Second
+
+func main() {
+	if err := logger.Init(); err != nil {
+		log.Fatalf("error iniciando logger: %v", err)
+	}
+	defer logger.Sync()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logger.Logger.Fatal("error cargando configuración", zap.Error(err))
+	}
+
+	appCtx, err := bootstrap.InitializeApp(cfg)
+	if err != nil {
+		logger.Logger.Fatal("error inicializando aplicación", zap.Error(err))
+	}
+
+	api := httpapi.NewHandler(
+		appCtx.UploadUseCase,
+		appCtx.HistoryUseCase,
+		appCtx.PreviewUseCase,
+		logger.Logger,
+		// Sin notificaciones de S3, lo subido se procesa en la misma petición.
+		httpapi.WithUploadProcessing(appCtx.SummaryUseCase),
+	)
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /events/s3", s3event.NewHandler(
+		appCtx.SummaryUseCase,
+		logger.Logger,
+		s3event.WithConcurrency(cfg.S3EventConcurrency),
+	))
+	mux.Handle("/", api)
+
+	srv := &http.Server{
+		Addr:              cfg.ServerAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Logger.Info("servidor HTTP escuchando",
+			zap.String("addr", cfg.ServerAddr),
+			zap.String("db_driver", cfg.DBDriver),
+		)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, http.ErrServerClosed) {
+			logger.Logger.Fatal("error en el servidor HTTP", zap.Error(err))
+		}
+	case <-ctx.Done():
+		logger.Logger.Info("señal recibida, cerrando servidor")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Logger.Error("error cerrando servidor HTTP", zap.Error(err))
+		return
+	}
+	logger.Logger.Info("servidor detenido")
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `storictl procesa estados de cuenta a mano.
+
+Uso:
+  storictl process     [flags] <archivo|s3://bucket/key>   procesa y persiste (como la Lambda)
+  storictl summarize   [flags] <archivo|s3://bucket/key>   imprime el resumen sin persistir
+  storictl validate    [flags] <archivo|s3://bucket/key>   valida fila por fila
+  storictl send-email  [flags] <archivo|s3://bucket/key>   envía (o muestra con -dry-run) el correo
+  storictl replay      [flags] <s3://bucket/prefijo>       reprocesa todos los objetos de un prefijo
+
+Use "storictl <comando> -h" para ver los flags de cada comando.
+`
+
+type command func(ctx context.Context, args []string, stdout io.Writer) error
+
+var commands = map[string]command{
+	"process":    runProcess,
+	"summarize":  runSummarize,
+	"validate":   runValidate,
+	"send-email": runSendEmail,
+	"replay":     runReplay,
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "comando desconocido %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmd(ctx, os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		stop()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"stori-challenge/internal/infra/bootstrap"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/infra/logger"
+	"stori-challenge/internal/interfaces/out/csvreader"
+	"stori-challenge/internal/interfaces/out/email"
+)
+
+func runProcess(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("process", flag.ContinueOnError)
+	db := addDBFlags(fs)
+	emailMode := fs.String("email", "auto", "auto|ses|log: auto usa SES salvo con AWS_ENDPOINT_URL")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	src, err := sourceArg(fs)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := db.loadConfig()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	var opts []bootstrap.AppOption
+	if !src.isS3() {
+		readerOpts, err := bootstrap.FileReaderOptions(cfg)
+		if err != nil {
+			return err
+		}
+		opts = append(opts, bootstrap.WithFileReader(csvreader.NewLocalFileReader(readerOpts...)))
+		// Los archivos locales no están en el bucket: no hay nada que mover.
+		cfg.ObjectLifecycleEnabled = false
+	}
+	switch *emailMode {
+	case "auto":
+	case "log":
+		opts = append(opts, bootstrap.WithEmailSender(email.NewNoopEmailSender(cfg)))
+	case "ses":
+		awsCfg, err := bootstrap.LoadAWSConfig(ctx, cfg)
+		if err != nil {
+			return err
+		}
+		cfg := *cfg
+		cfg.AWSEndpointURL = ""
+		opts = append(opts, bootstrap.WithEmailSender(bootstrap.NewEmailSender(awsCfg, &cfg)))
+	default:
+		return fmt.Errorf("valor de -email inválido %q (auto|ses|log)", *emailMode)
+	}
+
+	appCtx, err := bootstrap.InitializeApp(cfg, opts...)
+	if err != nil {
+		return err
+	}
+
+	if err := appCtx.SummaryUseCase.ProcessTransactionsFromObject(ctx, src.ref()); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "procesado %s\n", src)
+	return nil
+}
+
+type dbFlags struct {
+	driver     *string
+	sqlitePath *string
+}
+
+func addDBFlags(fs *flag.FlagSet) dbFlags {
+	return dbFlags{
+		driver:     fs.String("db-driver", "", "postgres|sqlite (por defecto DB_DRIVER)"),
+		sqlitePath: fs.String("sqlite-path", "", "archivo SQLite (por defecto DB_SQLITE_PATH)"),
+	}
+}
+
+// loadConfig inicializa el logger y carga la configuración; los flags pisan
+// las variables de entorno antes de validarla.
+func (f dbFlags) loadConfig() (*config.Config, error) {
+	if *f.driver != "" {
+		os.Setenv("DB_DRIVER", *f.driver)
+	}
+	if *f.sqlitePath != "" {
+		os.Setenv("DB_SQLITE_PATH", *f.sqlitePath)
+	}
+
+	if err := logger.Init(); err != nil {
+		return nil, err
+	}
+	return config.LoadConfig()
+}
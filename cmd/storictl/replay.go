@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/infra/bootstrap"
+	"stori-challenge/internal/infra/logger"
+)
+
+var errReplayFailures = errors.New("hubo objetos con error")
+
+func runReplay(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	db := addDBFlags(fs)
+	replayID := fs.String("id", "", "id del replay; repetirlo reanuda desde los checkpoints")
+	glob := fs.String("glob", "", "patrón sobre la key (o el nombre si no tiene \"/\"), por ejemplo \"*.csv\"")
+	since := fs.String("since", "", "solo objetos modificados desde esta fecha (YYYY-MM-DD)")
+	until := fs.String("until", "", "solo objetos modificados hasta esta fecha inclusive (YYYY-MM-DD)")
+	concurrency := fs.Int("concurrency", 4, "objetos procesados en paralelo")
+	emails := fs.String("emails", string(domain.ReplayEmailSuppress), "suppress|force")
+	dryRun := fs.Bool("dry-run", false, "solo lista los objetos que se procesarían")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("se espera exactamente un s3://bucket/prefijo")
+	}
+	rest, ok := strings.CutPrefix(fs.Arg(0), "s3://")
+	if !ok {
+		return fmt.Errorf("ruta S3 inválida %q, se espera s3://bucket/prefijo", fs.Arg(0))
+	}
+	bucket, prefix, _ := strings.Cut(rest, "/")
+
+	req := domain.ReplayRequest{
+		ReplayID:    *replayID,
+		Bucket:      bucket,
+		Prefix:      prefix,
+		Glob:        *glob,
+		Concurrency: *concurrency,
+		Email:       domain.ReplayEmailMode(*emails),
+		DryRun:      *dryRun,
+	}
+	var err error
+	if req.Since, err = parseDay(*since); err != nil {
+		return err
+	}
+	if req.Until, err = parseDay(*until); err != nil {
+		return err
+	}
+	if !req.Until.IsZero() {
+		req.Until = req.Until.AddDate(0, 0, 1)
+	}
+
+	cfg, err := db.loadConfig()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	appCtx, err := bootstrap.InitializeApp(cfg)
+	if err != nil {
+		return err
+	}
+
+	res, err := appCtx.ReplayUseCase.Replay(ctx, req)
+	printReplayResult(stdout, res, req.DryRun)
+	if err != nil {
+		return err
+	}
+	if res.Failed > 0 {
+		return errReplayFailures
+	}
+	return nil
+}
+
+func parseDay(v string) (time.Time, error) {
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("fecha inválida %q, se espera YYYY-MM-DD", v)
+	}
+	return t, nil
+}
+
+func printReplayResult(w io.Writer, res domain.ReplayResult, dryRun bool) {
+	fmt.Fprintf(w, "Replay:      %s\n", res.ReplayID)
+	fmt.Fprintf(w, "Listados:    %d\n", res.Listed)
+	fmt.Fprintf(w, "Filtrados:   %d\n", res.Matched)
+	if dryRun {
+		return
+	}
+	fmt.Fprintf(w, "Omitidos:    %d (ya procesados)\n", res.Skipped)
+	fmt.Fprintf(w, "Procesados:  %d\n", res.Succeeded)
+	fmt.Fprintf(w, "Con error:   %d\n", res.Failed)
+	for _, f := range res.Failures {
+		fmt.Fprintf(w, "  %s: %s\n", f.ObjectKey, f.Error)
+	}
+}
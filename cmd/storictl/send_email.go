@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"stori-challenge/internal/infra/bootstrap"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/infra/logger"
+)
+
+func runSendEmail(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("send-email", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "imprime el correo en vez de enviarlo")
+	format := fs.String("format", "plain", "plain|html (solo con -dry-run)")
+	account := fs.String("account", "", "cuenta a mostrar en el resumen")
+	logoURL := fs.String("logo-url", "", "logo del correo (por defecto STORI_LOGO_URL)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	src, err := sourceArg(fs)
+	if err != nil {
+		return err
+	}
+	if *format != "plain" && *format != "html" {
+		return fmt.Errorf("valor de -format inválido %q (plain|html)", *format)
+	}
+
+	if *dryRun {
+		previews, err := previewSource(ctx, src, *account, *logoURL)
+		if err != nil {
+			return err
+		}
+		for _, p := range previews {
+			if len(previews) > 1 {
+				fmt.Fprintf(stdout, "== %s ==\n", p.name)
+			}
+			fmt.Fprintf(stdout, "Subject: %s\n\n", p.Email.Subject)
+			if *format == "html" {
+				fmt.Fprintln(stdout, p.Email.HTML)
+			} else {
+				fmt.Fprintln(stdout, p.Email.Plain)
+			}
+		}
+		return nil
+	}
+
+	if err := logger.Init(); err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	previews, err := previewSource(ctx, src, *account, cfg.StoriLogoURL)
+	if err != nil {
+		return err
+	}
+	awsCfg, err := bootstrap.LoadAWSConfig(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	// Un correo por archivo, igual que process con las entradas de un zip.
+	sender := bootstrap.NewEmailSender(awsCfg, cfg)
+	for _, p := range previews {
+		if err := sender.SendSummaryEmail(ctx, p.Summary); err != nil {
+			return fmt.Errorf("%s: %w", p.name, err)
+		}
+		fmt.Fprintf(stdout, "correo de %s enviado a %s\n", p.name, cfg.EmailDefault)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/infra/bootstrap"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/interfaces/out/csvreader"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// localBucket identifica en la base los archivos procesados desde disco.
+const localBucket = "local"
+
+type source struct {
+	bucket  string
+	key     string
+	version string
+	path    string
+}
+
+func (s source) isS3() bool { return s.path == "" }
+
+func (s source) ref() domain.ObjectRef {
+	return domain.ObjectRef{Bucket: s.bucket, Key: s.key, VersionID: s.version}
+}
+
+func (s source) String() string {
+	if s.isS3() {
+		return s.ref().String()
+	}
+	return s.path
+}
+
+// sourceArg toma el único argumento posicional que esperan todos los comandos.
+func sourceArg(fs *flag.FlagSet) (source, error) {
+	if fs.NArg() != 1 {
+		return source{}, errors.New("se espera exactamente un archivo o s3://bucket/key")
+	}
+	return parseSource(fs.Arg(0))
+}
+
+func parseSource(arg string) (source, error) {
+	if rest, ok := strings.CutPrefix(arg, "s3://"); ok {
+		rest, version, _ := strings.Cut(rest, "?versionId=")
+		bucket, key, _ := strings.Cut(rest, "/")
+		if bucket == "" || key == "" {
+			return source{}, fmt.Errorf("ruta S3 inválida %q, se espera s3://bucket/key[?versionId=...]", arg)
+		}
+		return source{bucket: bucket, key: key, version: version}, nil
+	}
+	return source{bucket: localBucket, key: filepath.ToSlash(filepath.Clean(arg)), path: arg}, nil
+}
+
+// readSource devuelve el contenido crudo; solo carga configuración si el
+// origen es S3.
+func readSource(ctx context.Context, src source) ([]byte, error) {
+	if !src.isS3() {
+		return os.ReadFile(src.path)
+	}
+
+	client, _, err := newS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	in := &s3.GetObjectInput{
+		Bucket: &src.bucket,
+		Key:    &src.key,
+	}
+	if src.version != "" {
+		in.VersionId = &src.version
+	}
+	resp, err := client.GetObject(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// readFiles lee el origen con el mismo reader que process, así que acepta
+// todos sus formatos y devuelve un archivo por entrada de un zip.
+func readFiles(ctx context.Context, src source) ([]domain.TransactionFile, error) {
+	if !src.isS3() {
+		opts, err := bootstrap.FileReaderOptions(envReaderConfig())
+		if err != nil {
+			return nil, err
+		}
+		return csvreader.NewLocalFileReader(opts...).ReadObjectFiles(ctx, src.ref())
+	}
+
+	client, cfg, err := newS3Client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts, err := bootstrap.FileReaderOptions(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return csvreader.NewS3CSVReader(client, opts...).ReadObjectFiles(ctx, src.ref())
+}
+
+func newS3Client(ctx context.Context) (*s3.Client, *config.Config, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	awsCfg, err := bootstrap.LoadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return bootstrap.NewS3Client(awsCfg, cfg), cfg, nil
+}
+
+// newParser toma FILE_FIELD_MAP y AMOUNT_FORMAT del entorno sin cargar toda
+// la configuración: validate no necesita base ni AWS.
+func newParser() (csvreader.CSVParser, error) {
+	opts, err := bootstrap.FileReaderOptions(envReaderConfig())
+	if err != nil {
+		return csvreader.CSVParser{}, err
+	}
+	return csvreader.NewCSVParser(opts...), nil
+}
+
+func envReaderConfig() *config.Config {
+	return &config.Config{
+		FileFieldMap: os.Getenv("FILE_FIELD_MAP"),
+		AmountFormat: os.Getenv("AMOUNT_FORMAT"),
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"stori-challenge/internal/core/application"
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/email"
+)
+
+type summaryJSON struct {
+	AccountID    string      `json:"account_id"`
+	TotalBalance string      `json:"total_balance"`
+	Months       []monthJSON `json:"months"`
+}
+
+type monthJSON struct {
+	Month               string `json:"month"`
+	TransactionsCount   int    `json:"transactions_count"`
+	AverageDebitAmount  string `json:"average_debit_amount"`
+	AverageCreditAmount string `json:"average_credit_amount"`
+	TotalDebitAmount    string `json:"total_debit_amount"`
+	TotalCreditAmount   string `json:"total_credit_amount"`
+}
+
+func runSummarize(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("summarize", flag.ContinueOnError)
+	format := fs.String("format", "table", "table|json")
+	account := fs.String("account", "", "cuenta a mostrar en el resumen")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	src, err := sourceArg(fs)
+	if err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("valor de -format inválido %q (table|json)", *format)
+	}
+
+	previews, err := previewSource(ctx, src, *account, "")
+	if err != nil {
+		return err
+	}
+
+	for i, p := range previews {
+		if *format == "json" {
+			err = writeSummaryJSON(stdout, p.Summary)
+		} else {
+			if len(previews) > 1 {
+				if i > 0 {
+					fmt.Fprintln(stdout)
+				}
+				fmt.Fprintf(stdout, "== %s ==\n", p.name)
+			}
+			err = writeSummaryTable(stdout, p.Summary)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filePreview es la vista previa de un archivo lógico del origen: el objeto
+// o una entrada de su zip.
+type filePreview struct {
+	name string
+	domain.SummaryPreview
+}
+
+// previewSource reutiliza el caso de uso de vista previa: lee el origen como
+// process, resume y renderiza cada archivo sin tocar la base.
+func previewSource(ctx context.Context, src source, accountID, logoURL string) ([]filePreview, error) {
+	files, err := readFiles(ctx, src)
+	if err != nil {
+		return nil, err
+	}
+	if accountID == "" {
+		accountID = domain.AccountIDFromObjectKey(src.key)
+	}
+	svc := application.NewPreviewService(nil, email.NewSummaryRenderer(logoURL))
+
+	previews := make([]filePreview, 0, len(files))
+	for _, file := range files {
+		name := src.String()
+		if file.Entry != "" {
+			name += "#" + file.Entry
+		}
+		preview, err := svc.PreviewFile(ctx, accountID, file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		previews = append(previews, filePreview{name: name, SummaryPreview: preview})
+	}
+	return previews, nil
+}
+
+func writeSummaryJSON(w io.Writer, s domain.AccountSummary) error {
+	out := summaryJSON{
+		AccountID:    s.AccountID,
+		TotalBalance: s.TotalBalance.StringFixed(2),
+		Months:       make([]monthJSON, 0, len(s.ByMonth)),
+	}
+	for _, m := range s.ByMonth {
+		out.Months = append(out.Months, monthJSON{
+			Month:               m.MonthName,
+			TransactionsCount:   m.TransactionsCount,
+			AverageDebitAmount:  m.AverageDebitAmount.StringFixed(2),
+			AverageCreditAmount: m.AverageCreditAmount.StringFixed(2),
+			TotalDebitAmount:    m.TotalDebitAmount.StringFixed(2),
+			TotalCreditAmount:   m.TotalCreditAmount.StringFixed(2),
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+func writeSummaryTable(w io.Writer, s domain.AccountSummary) error {
+	fmt.Fprintf(w, "Cuenta: %s\nBalance total: %s\n\n", s.AccountID, s.TotalBalance.StringFixed(2))
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "Mes\tTransacciones\tProm. débito\tProm. crédito\tTotal débito\tTotal crédito\t")
+	for _, m := range s.ByMonth {
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\t%s\t\n",
+			m.MonthName,
+			m.TransactionsCount,
+			m.AverageDebitAmount.StringFixed(2),
+			m.AverageCreditAmount.StringFixed(2),
+			m.TotalDebitAmount.StringFixed(2),
+			m.TotalCreditAmount.StringFixed(2),
+		)
+	}
+	return tw.Flush()
+}
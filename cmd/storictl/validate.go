@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/csvreader"
+)
+
+var errInvalidFile = errors.New("el archivo tiene errores")
+
+func runValidate(ctx context.Context, args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	src, err := sourceArg(fs)
+	if err != nil {
+		return err
+	}
+
+	content, err := readSource(ctx, src)
+	if err != nil {
+		return err
+	}
+	// El reporte fila por fila solo existe para CSV; los demás formatos se
+	// revisan con summarize, que los lee como process.
+	if !csvreader.IsPlainCSV(src.key, content) {
+		return fmt.Errorf("%s no es un CSV sin comprimir: validate solo revisa CSV, use summarize para leerlo", src)
+	}
+
+	parser, err := newParser()
+	if err != nil {
+		return err
+	}
+	report, err := parser.Validate(bytes.NewReader(content))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(stdout, "Archivo:   %s\n", src)
+	fmt.Fprintf(stdout, "Formato:   %s\n", describeDialect(report.Dialect))
+	fmt.Fprintf(stdout, "Columnas:  %s\n", strings.Join(report.Columns, ","))
+	fmt.Fprintf(stdout, "Filas:     %d (válidas %d, omitidas %d)\n", report.TotalRows, report.ValidRows, report.SkippedRows)
+	for _, issue := range report.Issues {
+		fmt.Fprintf(stdout, "  línea %d: %s\n", issue.Line, issue.Message)
+	}
+
+	if !report.OK() {
+		return errInvalidFile
+	}
+	fmt.Fprintln(stdout, "OK")
+	return nil
+}
+
+var delimiterNames = map[rune]string{
+	',':  "coma",
+	';':  "punto y coma",
+	'\t': "tabulador",
+	'|':  "barra vertical",
+}
+
+func describeDialect(d domain.Dialect) string {
+	desc := fmt.Sprintf("%s, separador %s", d.Encoding, delimiterNames[d.Delimiter])
+	if d.BOM {
+		desc += ", con BOM"
+	}
+	return desc
+}
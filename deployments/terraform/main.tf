@@ -143,6 +143,12 @@ resource "aws_lambda_function" "s3_processor" {
       AWS_ENDPOINT_URL      = ""
       AWS_S3_USE_PATH_STYLE = "false"
       STORI_LOGO_URL        = var.stori_logo_url
+
+      REWARDS_ENABLED  = tostring(var.enable_rewards)
+      FORECAST_ENABLED = tostring(var.enable_forecast)
+
+      S3_EVENT_CONCURRENCY     = "4"
+      OBJECT_LIFECYCLE_ENABLED = tostring(var.enable_object_lifecycle)
     }
   }
 }
@@ -174,6 +180,9 @@ resource "aws_lambda_function" "api_handler" {
       AWS_ENDPOINT_URL      = ""
       AWS_S3_USE_PATH_STYLE = "false"
       STORI_LOGO_URL        = var.stori_logo_url
+
+      REWARDS_ENABLED  = tostring(var.enable_rewards)
+      FORECAST_ENABLED = tostring(var.enable_forecast)
     }
   }
 }
@@ -186,16 +195,135 @@ resource "aws_lambda_permission" "allow_s3_invoke" {
   source_arn    = aws_s3_bucket.transactions.arn
 }
 
+locals {
+  # Cada formato plano también puede llegar en gzip; ningún sufijo es a la vez
+  # sufijo de otro, como exige S3 para un mismo evento.
+  ingest_suffixes = [
+    ".csv", ".csv.gz",
+    ".zip",
+    ".ofx", ".ofx.gz", ".qfx", ".qfx.gz",
+    ".xml", ".xml.gz",
+    ".jsonl", ".jsonl.gz", ".ndjson", ".ndjson.gz",
+    ".parquet",
+  ]
+}
+
 resource "aws_s3_bucket_notification" "s3_to_lambda" {
   bucket = aws_s3_bucket.transactions.id
 
-  lambda_function {
-    lambda_function_arn = aws_lambda_function.s3_processor.arn
-    events              = ["s3:ObjectCreated:*"]
-    filter_suffix       = ".csv"
+  # Con ingest_via_sqs las notificaciones pasan por la cola en vez de invocar
+  # la Lambda directamente.
+  # Un bloque por sufijo de local.ingest_suffixes: CSV, OFX/QFX, XML (camt.053),
+  # JSON Lines/NDJSON y Parquet, sus variantes gzip y zip.
+  dynamic "lambda_function" {
+    for_each = var.ingest_via_sqs ? [] : local.ingest_suffixes
+    content {
+      lambda_function_arn = aws_lambda_function.s3_processor.arn
+      events              = ["s3:ObjectCreated:*"]
+      filter_suffix       = lambda_function.value
+    }
+  }
+
+  dynamic "queue" {
+    for_each = var.ingest_via_sqs ? local.ingest_suffixes : []
+    content {
+      queue_arn     = aws_sqs_queue.ingest[0].arn
+      events        = ["s3:ObjectCreated:*"]
+      filter_suffix = queue.value
+    }
   }
 
-  depends_on = [aws_lambda_permission.allow_s3_invoke]
+  depends_on = [aws_lambda_permission.allow_s3_invoke, aws_sqs_queue_policy.ingest]
+}
+
+resource "aws_sqs_queue" "ingest_dlq" {
+  count                     = var.ingest_via_sqs ? 1 : 0
+  name                      = "stori-ingest-dlq"
+  message_retention_seconds = 1209600
+}
+
+resource "aws_sqs_queue" "ingest" {
+  count                      = var.ingest_via_sqs ? 1 : 0
+  name                       = "stori-ingest"
+  visibility_timeout_seconds = 180
+
+  # Respaldo por si la Lambda no llega a registrar el dead-letter: se deja
+  # margen sobre SQS_MAX_RECEIVE_COUNT.
+  redrive_policy = jsonencode({
+    deadLetterTargetArn = aws_sqs_queue.ingest_dlq[0].arn
+    maxReceiveCount     = var.sqs_max_receive_count + 2
+  })
+}
+
+resource "aws_sqs_queue_policy" "ingest" {
+  count     = var.ingest_via_sqs ? 1 : 0
+  queue_url = aws_sqs_queue.ingest[0].id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [{
+      Effect    = "Allow"
+      Principal = { Service = "s3.amazonaws.com" }
+      Action    = "sqs:SendMessage"
+      Resource  = aws_sqs_queue.ingest[0].arn
+      Condition = {
+        ArnEquals = { "aws:SourceArn" = aws_s3_bucket.transactions.arn }
+      }
+    }]
+  })
+}
+
+resource "aws_iam_role_policy_attachment" "lambda_sqs" {
+  count      = var.ingest_via_sqs ? 1 : 0
+  role       = aws_iam_role.lambda_exec.name
+  policy_arn = "arn:aws:iam::aws:policy/service-role/AWSLambdaSQSQueueExecutionRole"
+}
+
+resource "aws_lambda_function" "sqs_processor" {
+  count         = var.ingest_via_sqs ? 1 : 0
+  function_name = "stori-sqs-processor"
+  package_type  = "Image"
+  image_uri     = var.ecr_sqs_processor_image
+  role          = aws_iam_role.lambda_exec.arn
+  timeout       = 30
+  memory_size   = 512
+
+  environment {
+    variables = {
+      DB_HOST     = aws_db_instance.stori.address
+      DB_PORT     = "5432"
+      DB_USER     = var.db_username
+      DB_PASSWORD = var.db_password
+      DB_NAME     = var.db_name
+      DB_SCHEMA   = "public"
+      DB_SSL_MODE = "require"
+
+      S3_BUCKET_NAME = aws_s3_bucket.transactions.bucket
+      S3_REGION      = var.aws_region
+
+      SES_FROM      = var.email_from
+      EMAIL_DEFAULT = var.email_default
+
+      AWS_ENDPOINT_URL      = ""
+      AWS_S3_USE_PATH_STYLE = "false"
+      STORI_LOGO_URL        = var.stori_logo_url
+
+      REWARDS_ENABLED  = tostring(var.enable_rewards)
+      FORECAST_ENABLED = tostring(var.enable_forecast)
+
+      SQS_MAX_RECEIVE_COUNT    = tostring(var.sqs_max_receive_count)
+      SQS_CONCURRENCY          = "4"
+      OBJECT_LIFECYCLE_ENABLED = tostring(var.enable_object_lifecycle)
+    }
+  }
+}
+
+resource "aws_lambda_event_source_mapping" "ingest" {
+  count                   = var.ingest_via_sqs ? 1 : 0
+  event_source_arn        = aws_sqs_queue.ingest[0].arn
+  function_name           = aws_lambda_function.sqs_processor[0].arn
+  batch_size              = 10
+  function_response_types = ["ReportBatchItemFailures"]
 }
 
 resource "aws_apigatewayv2_api" "http_api" {
@@ -204,7 +332,7 @@ resource "aws_apigatewayv2_api" "http_api" {
 
   cors_configuration {
     allow_origins = ["*"]
-    allow_methods = ["OPTIONS", "POST"]
+    allow_methods = ["OPTIONS", "GET", "POST"]
     allow_headers = ["content-type", "authorization"]
     expose_headers = [
       "content-type",
@@ -227,12 +355,70 @@ resource "aws_apigatewayv2_integration" "lambda_integration" {
   timeout_milliseconds   = 29000
 }
 
+# Las rutas de lectura exponen datos de todas las cuentas: siempre llevan
+# autorización, con JWT si hay issuer configurado y con IAM (SigV4) si no.
+resource "aws_apigatewayv2_authorizer" "jwt" {
+  count            = var.api_jwt_issuer != "" ? 1 : 0
+  api_id           = aws_apigatewayv2_api.http_api.id
+  name             = "stori-jwt"
+  authorizer_type  = "JWT"
+  identity_sources = ["$request.header.Authorization"]
+
+  jwt_configuration {
+    issuer   = var.api_jwt_issuer
+    audience = var.api_jwt_audience
+  }
+}
+
+locals {
+  read_authorization_type = var.api_jwt_issuer != "" ? "JWT" : "AWS_IAM"
+  read_authorizer_id      = var.api_jwt_issuer != "" ? aws_apigatewayv2_authorizer.jwt[0].id : null
+}
+
 resource "aws_apigatewayv2_route" "upload_route" {
   api_id    = aws_apigatewayv2_api.http_api.id
   route_key = "POST /upload"
   target    = "integrations/${aws_apigatewayv2_integration.lambda_integration.id}"
 }
 
+resource "aws_apigatewayv2_route" "get_summary_route" {
+  api_id             = aws_apigatewayv2_api.http_api.id
+  route_key          = "GET /summaries/{id}"
+  target             = "integrations/${aws_apigatewayv2_integration.lambda_integration.id}"
+  authorization_type = local.read_authorization_type
+  authorizer_id      = local.read_authorizer_id
+}
+
+resource "aws_apigatewayv2_route" "preview_summary_route" {
+  api_id    = aws_apigatewayv2_api.http_api.id
+  route_key = "POST /summaries/preview"
+  target    = "integrations/${aws_apigatewayv2_integration.lambda_integration.id}"
+}
+
+resource "aws_apigatewayv2_route" "latest_summary_route" {
+  api_id             = aws_apigatewayv2_api.http_api.id
+  route_key          = "GET /summaries"
+  target             = "integrations/${aws_apigatewayv2_integration.lambda_integration.id}"
+  authorization_type = local.read_authorization_type
+  authorizer_id      = local.read_authorizer_id
+}
+
+resource "aws_apigatewayv2_route" "list_transactions_route" {
+  api_id             = aws_apigatewayv2_api.http_api.id
+  route_key          = "GET /transactions"
+  target             = "integrations/${aws_apigatewayv2_integration.lambda_integration.id}"
+  authorization_type = local.read_authorization_type
+  authorizer_id      = local.read_authorizer_id
+}
+
+resource "aws_apigatewayv2_route" "get_run_route" {
+  api_id             = aws_apigatewayv2_api.http_api.id
+  route_key          = "GET /runs/{id}"
+  target             = "integrations/${aws_apigatewayv2_integration.lambda_integration.id}"
+  authorization_type = local.read_authorization_type
+  authorizer_id      = local.read_authorizer_id
+}
+
 resource "aws_apigatewayv2_stage" "default" {
   api_id      = aws_apigatewayv2_api.http_api.id
   name        = "$default"
@@ -247,3 +433,59 @@ resource "aws_lambda_permission" "allow_apigw_invoke" {
   source_arn    = "${aws_apigatewayv2_api.http_api.execution_arn}/*/*"
 }
 
+resource "aws_lambda_function" "statements" {
+  count         = var.enable_monthly_statements ? 1 : 0
+  function_name = "stori-monthly-statements"
+  package_type  = "Image"
+  image_uri     = var.ecr_schedule_image
+  role          = aws_iam_role.lambda_exec.arn
+  timeout       = 300
+  memory_size   = 256
+
+  environment {
+    variables = {
+      DB_HOST     = aws_db_instance.stori.address
+      DB_PORT     = "5432"
+      DB_USER     = var.db_username
+      DB_PASSWORD = var.db_password
+      DB_NAME     = var.db_name
+      DB_SCHEMA   = "public"
+      DB_SSL_MODE = "require"
+
+      S3_BUCKET_NAME = aws_s3_bucket.transactions.bucket
+      S3_REGION      = var.aws_region
+
+      SES_FROM      = var.email_from
+      EMAIL_DEFAULT = var.email_default
+
+      AWS_ENDPOINT_URL      = ""
+      AWS_S3_USE_PATH_STYLE = "false"
+      STORI_LOGO_URL        = var.stori_logo_url
+
+      REWARDS_ENABLED  = tostring(var.enable_rewards)
+      FORECAST_ENABLED = tostring(var.enable_forecast)
+    }
+  }
+}
+
+resource "aws_cloudwatch_event_rule" "monthly_statements" {
+  count               = var.enable_monthly_statements ? 1 : 0
+  name                = "stori-monthly-statements"
+  description         = "Envía el estado de cuenta del mes cerrado"
+  schedule_expression = var.statements_schedule
+}
+
+resource "aws_cloudwatch_event_target" "monthly_statements" {
+  count = var.enable_monthly_statements ? 1 : 0
+  rule  = aws_cloudwatch_event_rule.monthly_statements[0].name
+  arn   = aws_lambda_function.statements[0].arn
+}
+
+resource "aws_lambda_permission" "allow_eventbridge_invoke" {
+  count         = var.enable_monthly_statements ? 1 : 0
+  statement_id  = "AllowExecutionFromEventBridge"
+  action        = "lambda:InvokeFunction"
+  function_name = aws_lambda_function.statements[0].function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.monthly_statements[0].arn
+}
@@ -20,3 +20,8 @@ output "api_gateway_url" {
   description = "Invoke URL for the HTTP API"
   value       = aws_apigatewayv2_api.http_api.api_endpoint
 }
+
+output "sqs_ingest_queue_url" {
+  description = "SQS queue that receives S3 notifications (only with ingest_via_sqs)"
+  value       = var.ingest_via_sqs ? aws_sqs_queue.ingest[0].url : null
+}
@@ -58,3 +58,69 @@ variable "ecr_api_handler_image" {
   description = "Full ECR image URI for the API handler Lambda"
   type        = string
 }
+
+variable "ingest_via_sqs" {
+  description = "Route S3 notifications through SQS (cmd/lambda_sqs) instead of invoking the S3 processor directly"
+  type        = bool
+  default     = false
+}
+
+variable "ecr_sqs_processor_image" {
+  description = "Full ECR image URI for the SQS processor Lambda (required when ingest_via_sqs is true)"
+  type        = string
+  default     = ""
+}
+
+variable "sqs_max_receive_count" {
+  description = "Receives after which a failing message is recorded as a dead letter"
+  type        = number
+  default     = 5
+}
+
+variable "enable_object_lifecycle" {
+  description = "Move processed objects to processed/ and failed ones to quarantine/ (with an error report)"
+  type        = bool
+  default     = false
+}
+
+variable "enable_rewards" {
+  description = "Accrue reward points and cashback on credits (REWARDS_ENABLED)"
+  type        = bool
+  default     = true
+}
+
+variable "enable_forecast" {
+  description = "Project month-end spending and balance in summaries (FORECAST_ENABLED)"
+  type        = bool
+  default     = true
+}
+
+variable "enable_monthly_statements" {
+  description = "Deploy the scheduled monthly statements Lambda (cmd/lambda_schedule)"
+  type        = bool
+  default     = false
+}
+
+variable "ecr_schedule_image" {
+  description = "Full ECR image URI for the monthly statements Lambda (required when enable_monthly_statements is true)"
+  type        = string
+  default     = ""
+}
+
+variable "statements_schedule" {
+  description = "EventBridge schedule for monthly statements (UTC)"
+  type        = string
+  default     = "cron(0 6 1 * ? *)"
+}
+
+variable "api_jwt_issuer" {
+  description = "JWT issuer for the read routes (GET /summaries, /transactions, /runs); empty requires IAM (SigV4) auth instead"
+  type        = string
+  default     = ""
+}
+
+variable "api_jwt_audience" {
+  description = "Accepted JWT audiences for the read routes (required when api_jwt_issuer is set)"
+  type        = list(string)
+  default     = []
+}
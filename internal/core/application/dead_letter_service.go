@@ -0,0 +1,34 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"stori-challenge/internal/core/domain"
+	portin "stori-challenge/internal/core/ports/in"
+	"stori-challenge/internal/core/ports/out"
+)
+
+var _ portin.DeadLetterUseCase = (*DeadLetterService)(nil)
+
+type DeadLetterService struct {
+	repo out.DeadLetterRepo
+}
+
+func NewDeadLetterService(repo out.DeadLetterRepo) *DeadLetterService {
+	return &DeadLetterService{repo: repo}
+}
+
+func (s *DeadLetterService) RecordDeadLetter(
+	ctx context.Context,
+	dl domain.DeadLetter,
+) (domain.DeadLetter, error) {
+	if strings.TrimSpace(dl.Source) == "" {
+		return domain.DeadLetter{}, fmt.Errorf("%w: source obligatorio", domain.ErrInvalidQuery)
+	}
+	if strings.TrimSpace(dl.Error) == "" {
+		return domain.DeadLetter{}, fmt.Errorf("%w: error obligatorio", domain.ErrInvalidQuery)
+	}
+	return s.repo.SaveDeadLetter(ctx, dl)
+}
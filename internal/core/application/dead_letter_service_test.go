@@ -0,0 +1,46 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+)
+
+type fakeDeadLetterRepo struct {
+	saved []domain.DeadLetter
+}
+
+func (f *fakeDeadLetterRepo) SaveDeadLetter(_ context.Context, dl domain.DeadLetter) (domain.DeadLetter, error) {
+	dl.ID = uint64(len(f.saved) + 1)
+	f.saved = append(f.saved, dl)
+	return dl, nil
+}
+
+func TestDeadLetterService_RecordDeadLetter(t *testing.T) {
+	repo := &fakeDeadLetterRepo{}
+	svc := NewDeadLetterService(repo)
+
+	dl, err := svc.RecordDeadLetter(context.Background(), domain.DeadLetter{Source: "sqs", MessageID: "m-1", Error: "boom"})
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if dl.ID != 1 || len(repo.saved) != 1 || repo.saved[0].MessageID != "m-1" {
+		t.Errorf("dead-letter inesperado: %+v", dl)
+	}
+}
+
+func TestDeadLetterService_RecordDeadLetter_RequiresSourceAndError(t *testing.T) {
+	repo := &fakeDeadLetterRepo{}
+	svc := NewDeadLetterService(repo)
+
+	for _, dl := range []domain.DeadLetter{{Error: "boom"}, {Source: "sqs"}} {
+		if _, err := svc.RecordDeadLetter(context.Background(), dl); !errors.Is(err, domain.ErrInvalidQuery) {
+			t.Errorf("%+v: se esperaba ErrInvalidQuery, obtenido %v", dl, err)
+		}
+	}
+	if len(repo.saved) != 0 {
+		t.Errorf("no se debía guardar nada, se guardó %+v", repo.saved)
+	}
+}
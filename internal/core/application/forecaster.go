@@ -0,0 +1,233 @@
+package application
+
+import (
+	"sort"
+	"stori-challenge/internal/core/domain"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type Forecaster struct {
+	method domain.ForecastMethod
+	window int
+}
+
+func NewForecaster(method domain.ForecastMethod, windowMonths int) *Forecaster {
+	if method != domain.ForecastLinearTrend {
+		method = domain.ForecastMovingAverage
+	}
+	if windowMonths < 1 {
+		windowMonths = 1
+	}
+	return &Forecaster{method: method, window: windowMonths}
+}
+
+// HistoryRange devuelve el rango [from, to) de meses completos previos a asOf
+// que el forecaster usa como historial.
+func (f *Forecaster) HistoryRange(asOf time.Time) (time.Time, time.Time) {
+	to := startOfMonth(asOf)
+	return to.AddDate(0, -f.window, 0), to
+}
+
+// Forecast proyecta el cierre del mes de la última transacción de current.
+// history debe contener los movimientos de la cuenta en HistoryRange.
+func (f *Forecaster) Forecast(
+	history []domain.Transaction,
+	current []domain.Transaction,
+	balance decimal.Decimal,
+) *domain.Forecast {
+	if len(current) == 0 {
+		return nil
+	}
+
+	asOf := latestDate(current)
+	month := monthKey(asOf)
+	from, to := f.HistoryRange(asOf)
+
+	daysInMonth := daysIn(asOf)
+	elapsed := decimal.NewFromInt(int64(asOf.Day()))
+	remaining := decimal.NewFromInt(int64(daysInMonth - asOf.Day())).
+		Div(decimal.NewFromInt(int64(daysInMonth)))
+
+	spentToDate := map[string]decimal.Decimal{}
+	netToDate := decimal.Zero
+	for _, tx := range current {
+		if monthKey(tx.Date) != month {
+			continue
+		}
+		netToDate = netToDate.Add(tx.Amount)
+		if tx.Amount.LessThan(decimal.Zero) {
+			c := forecastCategory(tx.Category)
+			spentToDate[c] = spentToDate[c].Add(tx.Amount.Neg())
+		}
+	}
+
+	months := historyMonths(history, from, to)
+	spendSeries := map[string][]decimal.Decimal{}
+	netSeries := make([]decimal.Decimal, len(months))
+	index := map[string]int{}
+	for i, m := range months {
+		index[m] = i
+	}
+	for _, tx := range history {
+		i, ok := index[monthKey(tx.Date)]
+		if !ok {
+			continue
+		}
+		netSeries[i] = netSeries[i].Add(tx.Amount)
+		if tx.Amount.LessThan(decimal.Zero) {
+			c := forecastCategory(tx.Category)
+			if spendSeries[c] == nil {
+				spendSeries[c] = make([]decimal.Decimal, len(months))
+			}
+			spendSeries[c][i] = spendSeries[c][i].Add(tx.Amount.Neg())
+		}
+	}
+
+	categories := map[string]bool{}
+	for c := range spentToDate {
+		categories[c] = true
+	}
+	for c := range spendSeries {
+		categories[c] = true
+	}
+
+	fc := &domain.Forecast{
+		Month:  month,
+		AsOf:   asOf,
+		Method: f.method,
+	}
+
+	var names []string
+	for c := range categories {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+
+	for _, c := range names {
+		var expected decimal.Decimal
+		if len(months) == 0 {
+			expected = runRate(spentToDate[c], elapsed, daysInMonth)
+		} else {
+			expected = decimal.Max(f.expectedMonthly(spendSeries[c], len(months)), decimal.Zero)
+		}
+		projected := spentToDate[c].Add(expected.Mul(remaining)).Round(2)
+
+		fc.ByCategory = append(fc.ByCategory, domain.CategoryForecast{
+			Category:       c,
+			SpentToDate:    spentToDate[c].Round(2),
+			ProjectedSpend: projected,
+		})
+		fc.SpentToDate = fc.SpentToDate.Add(spentToDate[c])
+		fc.ProjectedSpend = fc.ProjectedSpend.Add(projected)
+	}
+	fc.SpentToDate = fc.SpentToDate.Round(2)
+
+	var expectedNet decimal.Decimal
+	if len(months) == 0 {
+		expectedNet = runRate(netToDate, elapsed, daysInMonth)
+	} else {
+		expectedNet = f.expectedMonthly(netSeries, len(months))
+	}
+	fc.ProjectedBalance = balance.Add(expectedNet.Mul(remaining)).Round(2)
+
+	return fc
+}
+
+func (f *Forecaster) expectedMonthly(series []decimal.Decimal, n int) decimal.Decimal {
+	if series == nil {
+		series = make([]decimal.Decimal, n)
+	}
+	if f.method == domain.ForecastLinearTrend && len(series) >= 2 {
+		return linearTrendNext(series)
+	}
+	return movingAverage(series)
+}
+
+func movingAverage(series []decimal.Decimal) decimal.Decimal {
+	if len(series) == 0 {
+		return decimal.Zero
+	}
+	sum := decimal.Zero
+	for _, v := range series {
+		sum = sum.Add(v)
+	}
+	return sum.Div(decimal.NewFromInt(int64(len(series))))
+}
+
+// linearTrendNext ajusta y = a + b·x por mínimos cuadrados sobre x = 0..n-1 y
+// devuelve la predicción para x = n.
+func linearTrendNext(series []decimal.Decimal) decimal.Decimal {
+	n := decimal.NewFromInt(int64(len(series)))
+	var sumX, sumY, sumXY, sumXX decimal.Decimal
+	for i, y := range series {
+		x := decimal.NewFromInt(int64(i))
+		sumX = sumX.Add(x)
+		sumY = sumY.Add(y)
+		sumXY = sumXY.Add(x.Mul(y))
+		sumXX = sumXX.Add(x.Mul(x))
+	}
+	denom := n.Mul(sumXX).Sub(sumX.Mul(sumX))
+	if denom.IsZero() {
+		return sumY.Div(n)
+	}
+	b := n.Mul(sumXY).Sub(sumX.Mul(sumY)).Div(denom)
+	a := sumY.Sub(b.Mul(sumX)).Div(n)
+	return a.Add(b.Mul(n))
+}
+
+func runRate(toDate, elapsedDays decimal.Decimal, daysInMonth int) decimal.Decimal {
+	if elapsedDays.IsZero() {
+		return decimal.Zero
+	}
+	return toDate.Div(elapsedDays).Mul(decimal.NewFromInt(int64(daysInMonth)))
+}
+
+// historyMonths lista los meses desde el primer mes con movimientos dentro de
+// [from, to) hasta el mes anterior a to, incluyendo meses sin movimientos.
+func historyMonths(history []domain.Transaction, from, to time.Time) []string {
+	var first time.Time
+	for _, tx := range history {
+		if tx.Date.Before(from) || !tx.Date.Before(to) {
+			continue
+		}
+		if first.IsZero() || tx.Date.Before(first) {
+			first = tx.Date
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	var months []string
+	for m := startOfMonth(first); m.Before(to); m = m.AddDate(0, 1, 0) {
+		months = append(months, monthKey(m))
+	}
+	return months
+}
+
+func latestDate(txs []domain.Transaction) time.Time {
+	var latest time.Time
+	for _, tx := range txs {
+		if tx.Date.After(latest) {
+			latest = tx.Date
+		}
+	}
+	return latest
+}
+
+func forecastCategory(c string) string {
+	if c == "" {
+		return domain.UncategorizedCategory
+	}
+	return c
+}
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+}
+
+func daysIn(t time.Time) int {
+	return startOfMonth(t).AddDate(0, 1, -1).Day()
+}
@@ -0,0 +1,142 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func monthlyHistory(category string, spends map[time.Month]int64, credit int64) []domain.Transaction {
+	var txs []domain.Transaction
+	for m, spend := range spends {
+		txs = append(txs,
+			domain.Transaction{Date: day(2021, m, 5), Amount: dFromInt(-spend), Category: category},
+			domain.Transaction{Date: day(2021, m, 20), Amount: dFromInt(credit)},
+		)
+	}
+	return txs
+}
+
+func findCategory(t *testing.T, fc *domain.Forecast, category string) domain.CategoryForecast {
+	t.Helper()
+	for _, c := range fc.ByCategory {
+		if c.Category == category {
+			return c
+		}
+	}
+	t.Fatalf("no se encontró la categoría %q en %v", category, fc.ByCategory)
+	return domain.CategoryForecast{}
+}
+
+func TestForecaster_HistoryRange(t *testing.T) {
+	f := NewForecaster(domain.ForecastMovingAverage, 3)
+
+	from, to := f.HistoryRange(day(2021, 9, 15))
+
+	if !from.Equal(day(2021, 6, 1)) || !to.Equal(day(2021, 9, 1)) {
+		t.Fatalf("HistoryRange = [%v, %v), esperado [2021-06-01, 2021-09-01)", from, to)
+	}
+}
+
+func TestForecaster_MovingAverage(t *testing.T) {
+	f := NewForecaster(domain.ForecastMovingAverage, 3)
+
+	history := monthlyHistory("groceries", map[time.Month]int64{
+		time.June: 300, time.July: 300, time.August: 300,
+	}, 1000)
+	current := []domain.Transaction{
+		{Date: day(2021, 9, 5), Amount: dFromInt(-100), Category: "groceries"},
+		{Date: day(2021, 9, 15), Amount: dFromInt(500)},
+	}
+
+	fc := f.Forecast(history, current, dFromInt(400))
+
+	if fc == nil {
+		t.Fatalf("se esperaba un forecast")
+	}
+	if fc.Month != "2021-09" || !fc.AsOf.Equal(day(2021, 9, 15)) {
+		t.Errorf("Month/AsOf = %s/%v, esperado 2021-09/2021-09-15", fc.Month, fc.AsOf)
+	}
+	if fc.Method != domain.ForecastMovingAverage {
+		t.Errorf("Method = %s, esperado %s", fc.Method, domain.ForecastMovingAverage)
+	}
+
+	g := findCategory(t, fc, "groceries")
+	assertDecEqual(t, g.SpentToDate, dFromInt(100), "SpentToDate groceries")
+	assertDecEqual(t, g.ProjectedSpend, dFromInt(250), "ProjectedSpend groceries")
+	assertDecEqual(t, fc.ProjectedSpend, dFromInt(250), "ProjectedSpend total")
+	assertDecEqual(t, fc.ProjectedBalance, dFromInt(750), "ProjectedBalance")
+}
+
+func TestForecaster_LinearTrend(t *testing.T) {
+	history := monthlyHistory("travel", map[time.Month]int64{
+		time.June: 100, time.July: 200, time.August: 300,
+	}, 0)
+	current := []domain.Transaction{
+		{Date: day(2021, 9, 15), Amount: dFromInt(-50), Category: "travel"},
+	}
+
+	lt := NewForecaster(domain.ForecastLinearTrend, 3).Forecast(history, current, dFromInt(1000))
+	ma := NewForecaster(domain.ForecastMovingAverage, 3).Forecast(history, current, dFromInt(1000))
+
+	assertDecEqual(t, findCategory(t, lt, "travel").ProjectedSpend, dFromInt(250), "ProjectedSpend tendencia lineal")
+	assertDecEqual(t, lt.ProjectedBalance, dFromInt(800), "ProjectedBalance tendencia lineal")
+
+	assertDecEqual(t, findCategory(t, ma, "travel").ProjectedSpend, dFromInt(150), "ProjectedSpend promedio móvil")
+	assertDecEqual(t, ma.ProjectedBalance, dFromInt(900), "ProjectedBalance promedio móvil")
+}
+
+func TestForecaster_LinearTrend_NeverProjectsNegativeSpend(t *testing.T) {
+	history := monthlyHistory("travel", map[time.Month]int64{
+		time.June: 300, time.July: 100, time.August: 1,
+	}, 0)
+	current := []domain.Transaction{
+		{Date: day(2021, 9, 15), Amount: dFromInt(-20), Category: "travel"},
+	}
+
+	fc := NewForecaster(domain.ForecastLinearTrend, 3).Forecast(history, current, dFromInt(0))
+
+	assertDecEqual(t, findCategory(t, fc, "travel").ProjectedSpend, dFromInt(20), "ProjectedSpend con tendencia negativa")
+}
+
+func TestForecaster_NoHistory_UsesRunRate(t *testing.T) {
+	f := NewForecaster(domain.ForecastMovingAverage, 3)
+
+	current := []domain.Transaction{
+		{Date: day(2021, 9, 10), Amount: dFromInt(-100)},
+	}
+
+	fc := f.Forecast(nil, current, dFromInt(-100))
+
+	u := findCategory(t, fc, domain.UncategorizedCategory)
+	assertDecEqual(t, u.SpentToDate, dFromInt(100), "SpentToDate")
+	assertDecEqual(t, u.ProjectedSpend, dFromInt(300), "ProjectedSpend por run-rate")
+	assertDecEqual(t, fc.ProjectedBalance, dFromInt(-300), "ProjectedBalance por run-rate")
+}
+
+func TestForecaster_MissingMonthsCountAsZero(t *testing.T) {
+	f := NewForecaster(domain.ForecastMovingAverage, 3)
+
+	history := monthlyHistory("groceries", map[time.Month]int64{time.June: 300}, 0)
+	history = append(history, domain.Transaction{Date: day(2021, 1, 5), Amount: dFromInt(-9999), Category: "groceries"})
+	current := []domain.Transaction{
+		{Date: day(2021, 9, 15), Amount: dFromInt(-10), Category: "groceries"},
+	}
+
+	fc := f.Forecast(history, current, dFromInt(0))
+
+	assertDecEqual(t, findCategory(t, fc, "groceries").ProjectedSpend, dFromInt(60), "ProjectedSpend con meses vacíos")
+}
+
+func TestForecaster_EmptyCurrent(t *testing.T) {
+	f := NewForecaster(domain.ForecastMovingAverage, 3)
+
+	if fc := f.Forecast(nil, nil, dFromInt(0)); fc != nil {
+		t.Fatalf("se esperaba nil sin transacciones actuales, obtenido %+v", fc)
+	}
+}
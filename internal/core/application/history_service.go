@@ -0,0 +1,114 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"stori-challenge/internal/core/domain"
+	portin "stori-challenge/internal/core/ports/in"
+	"stori-challenge/internal/core/ports/out"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+)
+
+var _ portin.HistoryQueryUseCase = (*HistoryService)(nil)
+
+type HistoryService struct {
+	txRepo out.TransactionRepo
+}
+
+func NewHistoryService(txRepo out.TransactionRepo) *HistoryService {
+	return &HistoryService{txRepo: txRepo}
+}
+
+func (s *HistoryService) ListTransactions(
+	ctx context.Context,
+	q domain.TransactionQuery,
+) (domain.TransactionPage, error) {
+	if strings.TrimSpace(q.AccountID) == "" {
+		return domain.TransactionPage{}, fmt.Errorf("%w: la cuenta es obligatoria", domain.ErrInvalidQuery)
+	}
+	if err := validateRange(q.From, q.To); err != nil {
+		return domain.TransactionPage{}, err
+	}
+	q.Limit = pageSize(q.Limit)
+	return s.txRepo.ListTransactions(ctx, q)
+}
+
+func (s *HistoryService) GetSummary(ctx context.Context, id uint64) (domain.StoredSummary, error) {
+	if id == 0 {
+		return domain.StoredSummary{}, fmt.Errorf("%w: id obligatorio", domain.ErrInvalidQuery)
+	}
+	return s.txRepo.GetSummaryByID(ctx, id)
+}
+
+func (s *HistoryService) GetLatestSummaryByObject(
+	ctx context.Context,
+	bucket, key string,
+) (domain.StoredSummary, error) {
+	if strings.TrimSpace(bucket) == "" || strings.TrimSpace(key) == "" {
+		return domain.StoredSummary{}, fmt.Errorf("%w: bucket y key son obligatorios", domain.ErrInvalidQuery)
+	}
+	return s.txRepo.GetLatestSummaryByObject(ctx, bucket, key)
+}
+
+func (s *HistoryService) GetLatestSummaryByAccount(
+	ctx context.Context,
+	accountID string,
+) (domain.StoredSummary, error) {
+	if strings.TrimSpace(accountID) == "" {
+		return domain.StoredSummary{}, fmt.Errorf("%w: la cuenta es obligatoria", domain.ErrInvalidQuery)
+	}
+	return s.txRepo.GetLatestSummaryByAccount(ctx, accountID)
+}
+
+func (s *HistoryService) GetProcessingRun(ctx context.Context, id uint64) (domain.ProcessingRun, error) {
+	if id == 0 {
+		return domain.ProcessingRun{}, fmt.Errorf("%w: id obligatorio", domain.ErrInvalidQuery)
+	}
+	return s.txRepo.GetProcessingRun(ctx, id)
+}
+
+func (s *HistoryService) ListProcessingRuns(
+	ctx context.Context,
+	q domain.RunQuery,
+) ([]domain.ProcessingRun, error) {
+	q.Limit = pageSize(q.Limit)
+	return s.txRepo.ListProcessingRuns(ctx, q)
+}
+
+func (s *HistoryService) AggregateByMonth(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+) ([]domain.MonthlySummary, error) {
+	if strings.TrimSpace(accountID) == "" {
+		return nil, fmt.Errorf("%w: la cuenta es obligatoria", domain.ErrInvalidQuery)
+	}
+	if err := validateRange(from, to); err != nil {
+		return nil, err
+	}
+	return s.txRepo.AggregateByMonth(ctx, accountID, from, to)
+}
+
+func pageSize(limit int) int {
+	switch {
+	case limit <= 0:
+		return defaultPageSize
+	case limit > maxPageSize:
+		return maxPageSize
+	default:
+		return limit
+	}
+}
+
+func validateRange(from, to time.Time) error {
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		return fmt.Errorf("%w: from debe ser anterior a to", domain.ErrInvalidQuery)
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func TestHistoryService_ListTransactions_DefaultsAndCapsLimit(t *testing.T) {
+	ctx := context.Background()
+
+	cases := []struct {
+		limit int
+		want  int
+	}{
+		{limit: 0, want: defaultPageSize},
+		{limit: -3, want: defaultPageSize},
+		{limit: 10, want: 10},
+		{limit: maxPageSize + 1, want: maxPageSize},
+	}
+
+	for _, tc := range cases {
+		repo := &fakeTxRepo{}
+		svc := NewHistoryService(repo)
+
+		if _, err := svc.ListTransactions(ctx, domain.TransactionQuery{AccountID: "acc-1", Limit: tc.limit}); err != nil {
+			t.Fatalf("no se esperaba error, obtenido: %v", err)
+		}
+		if repo.gotTxQuery.Limit != tc.want {
+			t.Errorf("limit %d: Limit = %d, se esperaba %d", tc.limit, repo.gotTxQuery.Limit, tc.want)
+		}
+	}
+}
+
+func TestHistoryService_ListTransactions_InvalidRange(t *testing.T) {
+	svc := NewHistoryService(&fakeTxRepo{})
+
+	day := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	_, err := svc.ListTransactions(context.Background(), domain.TransactionQuery{AccountID: "acc-1", From: day, To: day})
+	if !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Fatalf("se esperaba ErrInvalidQuery, obtenido %v", err)
+	}
+}
+
+func TestHistoryService_ListTransactions_RequiresAccount(t *testing.T) {
+	repo := &fakeTxRepo{}
+	svc := NewHistoryService(repo)
+
+	for _, account := range []string{"", "  "} {
+		_, err := svc.ListTransactions(context.Background(), domain.TransactionQuery{AccountID: account})
+		if !errors.Is(err, domain.ErrInvalidQuery) {
+			t.Errorf("cuenta %q: se esperaba ErrInvalidQuery, obtenido %v", account, err)
+		}
+	}
+	if repo.gotTxQuery.Limit != 0 {
+		t.Error("no se debía consultar el repositorio sin cuenta")
+	}
+}
+
+func TestHistoryService_GetLatestSummary_RequiresIdentifiers(t *testing.T) {
+	svc := NewHistoryService(&fakeTxRepo{})
+	ctx := context.Background()
+
+	if _, err := svc.GetLatestSummaryByObject(ctx, "bucket", " "); !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Errorf("por objeto: se esperaba ErrInvalidQuery, obtenido %v", err)
+	}
+	if _, err := svc.GetLatestSummaryByAccount(ctx, ""); !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Errorf("por cuenta: se esperaba ErrInvalidQuery, obtenido %v", err)
+	}
+}
+
+func TestHistoryService_GetLatestSummary_PropagatesNotFound(t *testing.T) {
+	svc := NewHistoryService(&fakeTxRepo{storedErr: domain.ErrNotFound})
+
+	_, err := svc.GetLatestSummaryByAccount(context.Background(), "acc-1")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("se esperaba ErrNotFound, obtenido %v", err)
+	}
+}
+
+func TestHistoryService_AggregateByMonth(t *testing.T) {
+	from := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeTxRepo{aggregated: []domain.MonthlySummary{{MonthName: "2021-07", TransactionsCount: 2}}}
+	svc := NewHistoryService(repo)
+
+	got, err := svc.AggregateByMonth(context.Background(), "acc-1", from, to)
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if len(got) != 1 || got[0].MonthName != "2021-07" {
+		t.Errorf("resultado inesperado: %+v", got)
+	}
+	if repo.gotHistAccount != "acc-1" || !repo.gotHistFrom.Equal(from) || !repo.gotHistTo.Equal(to) {
+		t.Errorf("repo llamado con %s %v %v", repo.gotHistAccount, repo.gotHistFrom, repo.gotHistTo)
+	}
+
+	if _, err := svc.AggregateByMonth(context.Background(), "", from, to); !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Errorf("sin cuenta: se esperaba ErrInvalidQuery, obtenido %v", err)
+	}
+}
+
+func TestHistoryService_GetByID_RequiresID(t *testing.T) {
+	svc := NewHistoryService(&fakeTxRepo{})
+	ctx := context.Background()
+
+	if _, err := svc.GetSummary(ctx, 0); !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Errorf("resumen: se esperaba ErrInvalidQuery, obtenido %v", err)
+	}
+	if _, err := svc.GetProcessingRun(ctx, 0); !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Errorf("corrida: se esperaba ErrInvalidQuery, obtenido %v", err)
+	}
+	if _, err := svc.GetProcessingRun(ctx, 7); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("corrida inexistente: se esperaba ErrNotFound, obtenido %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"stori-challenge/internal/core/domain"
+	portin "stori-challenge/internal/core/ports/in"
+	"stori-challenge/internal/core/ports/out"
+	"strings"
+)
+
+var _ portin.SummaryPreviewUseCase = (*PreviewService)(nil)
+
+type PreviewService struct {
+	parser   out.TransactionParser
+	renderer out.SummaryRenderer
+}
+
+func NewPreviewService(parser out.TransactionParser, renderer out.SummaryRenderer) *PreviewService {
+	return &PreviewService{parser: parser, renderer: renderer}
+}
+
+// PreviewSummary no toca la base ni envía correo: solo parsea, resume y
+// renderiza, así que no incluye rewards, proyección ni comparativos.
+func (s *PreviewService) PreviewSummary(
+	ctx context.Context,
+	accountID string,
+	content []byte,
+) (domain.SummaryPreview, error) {
+	txs, err := s.parser.ParseTransactions(ctx, bytes.NewReader(content))
+	return s.PreviewFile(ctx, accountID, domain.TransactionFile{Transactions: txs, Err: err})
+}
+
+// PreviewFile resume un archivo ya leído, como los que devuelve
+// TransactionFileReader.ReadObjectFiles; conserva los saldos del extracto.
+func (s *PreviewService) PreviewFile(
+	ctx context.Context,
+	accountID string,
+	file domain.TransactionFile,
+) (domain.SummaryPreview, error) {
+	accountID = strings.TrimSpace(accountID)
+	if accountID != "" && !accountIDPattern.MatchString(accountID) {
+		return domain.SummaryPreview{}, fmt.Errorf("%w: cuenta %q no válida", domain.ErrInvalidUpload, accountID)
+	}
+
+	if file.Err != nil {
+		return domain.SummaryPreview{}, fmt.Errorf("%w: %v", domain.ErrInvalidUpload, file.Err)
+	}
+	if len(file.Transactions) == 0 {
+		return domain.SummaryPreview{}, fmt.Errorf("%w: el archivo no contiene transacciones", domain.ErrInvalidUpload)
+	}
+
+	summary := buildAccountSummary(file.Transactions)
+	summary.OpeningBalance = file.OpeningBalance
+	summary.ClosingBalance = file.ClosingBalance
+	summary.AccountID = accountID
+	if summary.AccountID == "" {
+		summary.AccountID = domain.DefaultAccountID
+	}
+
+	rendered, err := s.renderer.RenderSummary(ctx, summary)
+	if err != nil {
+		return domain.SummaryPreview{}, err
+	}
+
+	return domain.SummaryPreview{Summary: summary, Email: rendered}, nil
+}
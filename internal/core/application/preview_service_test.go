@@ -0,0 +1,112 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+type fakeRenderer struct {
+	err error
+
+	called bool
+	gotSum domain.AccountSummary
+}
+
+func (f *fakeRenderer) RenderSummary(_ context.Context, summary domain.AccountSummary) (domain.RenderedEmail, error) {
+	f.called = true
+	f.gotSum = summary
+	return domain.RenderedEmail{Subject: "asunto", HTML: "<p>html</p>", Plain: "texto"}, f.err
+}
+
+func TestPreviewService_PreviewSummary_HappyPath(t *testing.T) {
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: dFromInt(60)},
+		{Date: time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-20)},
+	}
+	renderer := &fakeRenderer{}
+	svc := NewPreviewService(&fakeParser{txs: txs}, renderer)
+
+	got, err := svc.PreviewSummary(context.Background(), "acc-1", []byte("csv"))
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	expected := buildAccountSummary(txs)
+	assertDecEqual(t, got.Summary.TotalBalance, expected.TotalBalance, "TotalBalance")
+	if got.Summary.AccountID != "acc-1" || len(got.Summary.ByMonth) != 2 {
+		t.Errorf("resumen = %+v", got.Summary)
+	}
+	if !renderer.called || renderer.gotSum.AccountID != "acc-1" {
+		t.Errorf("renderer no recibió el resumen")
+	}
+	if got.Email.HTML != "<p>html</p>" || got.Email.Plain != "texto" || got.Email.Subject != "asunto" {
+		t.Errorf("correo = %+v", got.Email)
+	}
+}
+
+func TestPreviewService_PreviewSummary_DefaultAccount(t *testing.T) {
+	svc := NewPreviewService(&fakeParser{txs: []domain.Transaction{{Amount: dFromInt(1)}}}, &fakeRenderer{})
+
+	got, err := svc.PreviewSummary(context.Background(), " ", []byte("csv"))
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if got.Summary.AccountID != domain.DefaultAccountID {
+		t.Errorf("AccountID = %q, se esperaba %q", got.Summary.AccountID, domain.DefaultAccountID)
+	}
+}
+
+func TestPreviewService_PreviewSummary_Errors(t *testing.T) {
+	renderErr := errors.New("falló render")
+
+	cases := []struct {
+		name     string
+		parser   *fakeParser
+		renderer *fakeRenderer
+		account  string
+		want     error
+	}{
+		{"error de parseo", &fakeParser{err: errors.New("fecha")}, &fakeRenderer{}, "", domain.ErrInvalidUpload},
+		{"sin transacciones", &fakeParser{}, &fakeRenderer{}, "", domain.ErrInvalidUpload},
+		{"cuenta inválida", &fakeParser{txs: []domain.Transaction{{}}}, &fakeRenderer{}, "a/b", domain.ErrInvalidUpload},
+		{"error de render", &fakeParser{txs: []domain.Transaction{{}}}, &fakeRenderer{err: renderErr}, "", renderErr},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := NewPreviewService(tc.parser, tc.renderer)
+			_, err := svc.PreviewSummary(context.Background(), tc.account, []byte("x"))
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("se esperaba %v, obtenido %v", tc.want, err)
+			}
+		})
+	}
+}
+
+func TestPreviewService_PreviewFile_KeepsStatementBalances(t *testing.T) {
+	opening, closing := dFromInt(100), dFromInt(140)
+	file := domain.TransactionFile{
+		Transactions:   []domain.Transaction{{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: dFromInt(40)}},
+		OpeningBalance: &opening,
+		ClosingBalance: &closing,
+	}
+	svc := NewPreviewService(&fakeParser{}, &fakeRenderer{})
+
+	got, err := svc.PreviewFile(context.Background(), "acc-1", file)
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if got.Summary.OpeningBalance == nil || got.Summary.ClosingBalance == nil {
+		t.Fatalf("el resumen debe conservar los saldos, obtenido %+v", got.Summary)
+	}
+	assertDecEqual(t, *got.Summary.ClosingBalance, closing, "ClosingBalance")
+
+	file.Err = errors.New("Ntry 2")
+	if _, err := svc.PreviewFile(context.Background(), "acc-1", file); !errors.Is(err, domain.ErrInvalidUpload) {
+		t.Fatalf("se esperaba ErrInvalidUpload, obtenido %v", err)
+	}
+}
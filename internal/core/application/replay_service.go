@@ -0,0 +1,187 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"stori-challenge/internal/core/domain"
+	portin "stori-challenge/internal/core/ports/in"
+	"stori-challenge/internal/core/ports/out"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultReplayConcurrency = 4
+	maxReplayConcurrency     = 32
+)
+
+var _ portin.ReplayUseCase = (*ReplayService)(nil)
+
+// ReplayService reprocesa los objetos de un prefijo. Recibe dos casos de uso
+// de resumen: uno que envía correos y otro silencioso, y elige según
+// ReplayRequest.Email.
+type ReplayService struct {
+	lister      out.ObjectLister
+	checkpoints out.ReplayCheckpointStore
+	notifying   portin.SummaryUseCase
+	quiet       portin.SummaryUseCase
+
+	newID func() string
+}
+
+func NewReplayService(
+	lister out.ObjectLister,
+	checkpoints out.ReplayCheckpointStore,
+	notifying portin.SummaryUseCase,
+	quiet portin.SummaryUseCase,
+) *ReplayService {
+	return &ReplayService{
+		lister:      lister,
+		checkpoints: checkpoints,
+		notifying:   notifying,
+		quiet:       quiet,
+		newID:       uuid.NewString,
+	}
+}
+
+func (s *ReplayService) Replay(ctx context.Context, req domain.ReplayRequest) (domain.ReplayResult, error) {
+	if err := validateReplay(&req); err != nil {
+		return domain.ReplayResult{}, err
+	}
+	if req.ReplayID == "" {
+		req.ReplayID = s.newID()
+	}
+	result := domain.ReplayResult{ReplayID: req.ReplayID}
+
+	objects, err := s.lister.ListObjects(ctx, req.Bucket, req.Prefix)
+	if err != nil {
+		return result, err
+	}
+	result.Listed = len(objects)
+
+	objects = filterObjects(objects, req)
+	result.Matched = len(objects)
+	if req.DryRun {
+		return result, nil
+	}
+
+	done, err := s.checkpoints.CompletedReplayObjects(ctx, req.ReplayID, req.Bucket)
+	if err != nil {
+		return result, err
+	}
+
+	processor := s.quiet
+	if req.Email == domain.ReplayEmailForce {
+		processor = s.notifying
+	}
+
+	var mu sync.Mutex
+	var g errgroup.Group
+	g.SetLimit(req.Concurrency)
+
+	for _, obj := range objects {
+		if done[obj.Key] {
+			result.Skipped++
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		g.Go(func() error {
+			procErr := processor.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: req.Bucket, Key: obj.Key})
+
+			cp := domain.ReplayCheckpoint{
+				ReplayID:  req.ReplayID,
+				Bucket:    req.Bucket,
+				ObjectKey: obj.Key,
+				Status:    domain.RunStatusSucceeded,
+			}
+			if procErr != nil {
+				cp.Status = domain.RunStatusFailed
+				cp.Error = procErr.Error()
+			}
+
+			mu.Lock()
+			if procErr != nil {
+				result.Failed++
+				result.Failures = append(result.Failures, domain.ReplayFailure{ObjectKey: obj.Key, Error: cp.Error})
+			} else {
+				result.Succeeded++
+			}
+			mu.Unlock()
+
+			// Sin checkpoint no se puede reanudar con seguridad: se corta el replay.
+			return s.checkpoints.SaveReplayCheckpoint(ctx, cp)
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return result, err
+	}
+	return result, ctx.Err()
+}
+
+func validateReplay(req *domain.ReplayRequest) error {
+	if strings.TrimSpace(req.Bucket) == "" {
+		return fmt.Errorf("%w: bucket obligatorio", domain.ErrInvalidQuery)
+	}
+	if req.Glob != "" {
+		if _, err := path.Match(req.Glob, ""); err != nil {
+			return fmt.Errorf("%w: glob %q inválido", domain.ErrInvalidQuery, req.Glob)
+		}
+	}
+	if err := validateRange(req.Since, req.Until); err != nil {
+		return err
+	}
+
+	switch req.Email {
+	case "":
+		req.Email = domain.ReplayEmailSuppress
+	case domain.ReplayEmailSuppress, domain.ReplayEmailForce:
+	default:
+		return fmt.Errorf("%w: modo de email %q inválido", domain.ErrInvalidQuery, req.Email)
+	}
+
+	switch {
+	case req.Concurrency <= 0:
+		req.Concurrency = defaultReplayConcurrency
+	case req.Concurrency > maxReplayConcurrency:
+		req.Concurrency = maxReplayConcurrency
+	}
+	return nil
+}
+
+func filterObjects(objects []domain.ObjectInfo, req domain.ReplayRequest) []domain.ObjectInfo {
+	var matched []domain.ObjectInfo
+	for _, obj := range objects {
+		if strings.HasSuffix(obj.Key, "/") {
+			continue
+		}
+		if !req.Since.IsZero() && obj.LastModified.Before(req.Since) {
+			continue
+		}
+		if !req.Until.IsZero() && !obj.LastModified.Before(req.Until) {
+			continue
+		}
+		if req.Glob != "" && !matchGlob(req.Glob, obj.Key) {
+			continue
+		}
+		matched = append(matched, obj)
+	}
+	return matched
+}
+
+func matchGlob(glob, key string) bool {
+	name := key
+	if !strings.Contains(glob, "/") {
+		name = path.Base(key)
+	}
+	ok, _ := path.Match(glob, name)
+	return ok
+}
@@ -0,0 +1,246 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+type fakeLister struct {
+	objects []domain.ObjectInfo
+	err     error
+
+	gotBucket string
+	gotPrefix string
+}
+
+func (f *fakeLister) ListObjects(_ context.Context, bucket, prefix string) ([]domain.ObjectInfo, error) {
+	f.gotBucket = bucket
+	f.gotPrefix = prefix
+	return f.objects, f.err
+}
+
+type fakeCheckpoints struct {
+	mu sync.Mutex
+
+	done    map[string]bool
+	saveErr error
+	saved   []domain.ReplayCheckpoint
+}
+
+func (f *fakeCheckpoints) CompletedReplayObjects(_ context.Context, _, _ string) (map[string]bool, error) {
+	return f.done, nil
+}
+
+func (f *fakeCheckpoints) SaveReplayCheckpoint(_ context.Context, cp domain.ReplayCheckpoint) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.saved = append(f.saved, cp)
+	return f.saveErr
+}
+
+type fakeProcessor struct {
+	mu sync.Mutex
+
+	errs      map[string]error
+	delay     time.Duration
+	processed []string
+	running   int
+	maxActive int
+}
+
+func (f *fakeProcessor) ProcessTransactionsFromObject(_ context.Context, obj domain.ObjectRef) error {
+	f.mu.Lock()
+	f.running++
+	f.maxActive = max(f.maxActive, f.running)
+	f.processed = append(f.processed, obj.Key)
+	f.mu.Unlock()
+
+	time.Sleep(f.delay)
+
+	f.mu.Lock()
+	f.running--
+	f.mu.Unlock()
+	return f.errs[obj.Key]
+}
+
+func objectsAt(keys ...string) []domain.ObjectInfo {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	objects := make([]domain.ObjectInfo, len(keys))
+	for i, k := range keys {
+		objects[i] = domain.ObjectInfo{Bucket: "bucket", Key: k, LastModified: base.AddDate(0, 0, i)}
+	}
+	return objects
+}
+
+func TestReplayService_Replay_FiltersByGlobAndDate(t *testing.T) {
+	lister := &fakeLister{objects: objectsAt(
+		"input/acc-1/a.csv", // 1 mar
+		"input/acc-1/b.csv", // 2 mar
+		"input/acc-1/c.txt", // 3 mar
+		"input/acc-2/d.csv", // 4 mar
+		"input/acc-2/",      // "carpeta"
+	)}
+	quiet := &fakeProcessor{}
+	svc := NewReplayService(lister, &fakeCheckpoints{}, &fakeProcessor{}, quiet)
+
+	res, err := svc.Replay(context.Background(), domain.ReplayRequest{
+		ReplayID: "r-1",
+		Bucket:   "bucket",
+		Prefix:   "input/",
+		Glob:     "*.csv",
+		Since:    time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC),
+		Until:    time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if lister.gotBucket != "bucket" || lister.gotPrefix != "input/" {
+		t.Errorf("listado con %s/%s", lister.gotBucket, lister.gotPrefix)
+	}
+	sort.Strings(quiet.processed)
+	if len(quiet.processed) != 2 || quiet.processed[0] != "input/acc-1/b.csv" || quiet.processed[1] != "input/acc-2/d.csv" {
+		t.Errorf("procesados = %v", quiet.processed)
+	}
+	if res.Listed != 5 || res.Matched != 2 || res.Succeeded != 2 || res.Failed != 0 {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+}
+
+func TestReplayService_Replay_GlobWithSlashMatchesFullKey(t *testing.T) {
+	quiet := &fakeProcessor{}
+	svc := NewReplayService(
+		&fakeLister{objects: objectsAt("input/acc-1/a.csv", "input/acc-2/a.csv")},
+		&fakeCheckpoints{}, &fakeProcessor{}, quiet,
+	)
+
+	if _, err := svc.Replay(context.Background(), domain.ReplayRequest{Bucket: "bucket", Glob: "input/acc-2/*"}); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if len(quiet.processed) != 1 || quiet.processed[0] != "input/acc-2/a.csv" {
+		t.Errorf("procesados = %v", quiet.processed)
+	}
+}
+
+func TestReplayService_Replay_ResumesFromCheckpoints(t *testing.T) {
+	checkpoints := &fakeCheckpoints{done: map[string]bool{"a.csv": true}}
+	quiet := &fakeProcessor{errs: map[string]error{"c.csv": errors.New("boom")}}
+	svc := NewReplayService(&fakeLister{objects: objectsAt("a.csv", "b.csv", "c.csv")}, checkpoints, &fakeProcessor{}, quiet)
+
+	res, err := svc.Replay(context.Background(), domain.ReplayRequest{ReplayID: "r-1", Bucket: "bucket"})
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if res.Skipped != 1 || res.Succeeded != 1 || res.Failed != 1 {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+	if len(res.Failures) != 1 || res.Failures[0].ObjectKey != "c.csv" || res.Failures[0].Error != "boom" {
+		t.Errorf("fallos inesperados: %+v", res.Failures)
+	}
+
+	status := map[string]domain.RunStatus{}
+	for _, cp := range checkpoints.saved {
+		if cp.ReplayID != "r-1" || cp.Bucket != "bucket" {
+			t.Errorf("checkpoint inesperado: %+v", cp)
+		}
+		status[cp.ObjectKey] = cp.Status
+	}
+	if len(status) != 2 || status["b.csv"] != domain.RunStatusSucceeded || status["c.csv"] != domain.RunStatusFailed {
+		t.Errorf("checkpoints = %v", status)
+	}
+}
+
+func TestReplayService_Replay_EmailMode(t *testing.T) {
+	cases := []struct {
+		mode       domain.ReplayEmailMode
+		wantNotify bool
+	}{
+		{mode: "", wantNotify: false},
+		{mode: domain.ReplayEmailSuppress, wantNotify: false},
+		{mode: domain.ReplayEmailForce, wantNotify: true},
+	}
+
+	for _, tc := range cases {
+		notifying, quiet := &fakeProcessor{}, &fakeProcessor{}
+		svc := NewReplayService(&fakeLister{objects: objectsAt("a.csv")}, &fakeCheckpoints{}, notifying, quiet)
+
+		if _, err := svc.Replay(context.Background(), domain.ReplayRequest{Bucket: "bucket", Email: tc.mode}); err != nil {
+			t.Fatalf("modo %q: no se esperaba error, obtenido: %v", tc.mode, err)
+		}
+		if got := len(notifying.processed) == 1; got != tc.wantNotify || len(quiet.processed) == len(notifying.processed) {
+			t.Errorf("modo %q: notifying=%v quiet=%v", tc.mode, notifying.processed, quiet.processed)
+		}
+	}
+}
+
+func TestReplayService_Replay_BoundsConcurrency(t *testing.T) {
+	quiet := &fakeProcessor{delay: 10 * time.Millisecond}
+	svc := NewReplayService(
+		&fakeLister{objects: objectsAt("a", "b", "c", "d", "e", "f", "g", "h")},
+		&fakeCheckpoints{}, &fakeProcessor{}, quiet,
+	)
+
+	res, err := svc.Replay(context.Background(), domain.ReplayRequest{Bucket: "bucket", Concurrency: 3})
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if res.Succeeded != 8 {
+		t.Errorf("Succeeded = %d, se esperaba 8", res.Succeeded)
+	}
+	if quiet.maxActive > 3 {
+		t.Errorf("hubo %d procesamientos simultáneos, el máximo era 3", quiet.maxActive)
+	}
+}
+
+func TestReplayService_Replay_DryRunDoesNotProcess(t *testing.T) {
+	checkpoints := &fakeCheckpoints{}
+	quiet := &fakeProcessor{}
+	svc := NewReplayService(&fakeLister{objects: objectsAt("a.csv", "b.csv")}, checkpoints, &fakeProcessor{}, quiet)
+	svc.newID = func() string { return "fixed-id" }
+
+	res, err := svc.Replay(context.Background(), domain.ReplayRequest{Bucket: "bucket", DryRun: true})
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if res.ReplayID != "fixed-id" || res.Matched != 2 {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+	if len(quiet.processed) != 0 || len(checkpoints.saved) != 0 {
+		t.Errorf("dry-run no debe procesar ni guardar checkpoints")
+	}
+}
+
+func TestReplayService_Replay_CheckpointErrorAbortsReplay(t *testing.T) {
+	saveErr := errors.New("db caída")
+	svc := NewReplayService(
+		&fakeLister{objects: objectsAt("a.csv")},
+		&fakeCheckpoints{saveErr: saveErr}, &fakeProcessor{}, &fakeProcessor{},
+	)
+
+	if _, err := svc.Replay(context.Background(), domain.ReplayRequest{Bucket: "bucket"}); !errors.Is(err, saveErr) {
+		t.Fatalf("se esperaba %v, obtenido %v", saveErr, err)
+	}
+}
+
+func TestReplayService_Replay_InvalidRequest(t *testing.T) {
+	svc := NewReplayService(&fakeLister{}, &fakeCheckpoints{}, &fakeProcessor{}, &fakeProcessor{})
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	for name, req := range map[string]domain.ReplayRequest{
+		"sin bucket":    {},
+		"glob inválido": {Bucket: "bucket", Glob: "[a-"},
+		"rango vacío":   {Bucket: "bucket", Since: day, Until: day},
+		"modo de email": {Bucket: "bucket", Email: "always"},
+	} {
+		if _, err := svc.Replay(context.Background(), req); !errors.Is(err, domain.ErrInvalidQuery) {
+			t.Errorf("%s: se esperaba ErrInvalidQuery, obtenido %v", name, err)
+		}
+	}
+}
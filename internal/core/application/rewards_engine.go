@@ -0,0 +1,90 @@
+package application
+
+import (
+	"sort"
+	"stori-challenge/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+type RewardsEngine struct {
+	rules domain.RewardRules
+}
+
+func NewRewardsEngine(rules domain.RewardRules) *RewardsEngine {
+	return &RewardsEngine{rules: rules}
+}
+
+// Accrue aplica las reglas a los créditos de txs. accrued trae lo ya acumulado
+// por ciclo (monthKey) para respetar los caps entre distintos archivos.
+func (e *RewardsEngine) Accrue(
+	accountID string,
+	txs []domain.Transaction,
+	accrued map[string]domain.RewardsBalance,
+) []domain.RewardEntry {
+	credits := make([]domain.Transaction, 0, len(txs))
+	for _, tx := range txs {
+		if tx.Amount.GreaterThan(decimal.Zero) {
+			credits = append(credits, tx)
+		}
+	}
+	sort.SliceStable(credits, func(i, j int) bool {
+		return credits[i].Date.Before(credits[j].Date)
+	})
+
+	perCycle := map[string]domain.RewardsBalance{}
+	for k, v := range accrued {
+		perCycle[k] = v
+	}
+
+	var entries []domain.RewardEntry
+	for _, tx := range credits {
+		cycle := monthKey(tx.Date)
+		mult := e.rules.Multiplier(tx.Category)
+		soFar := perCycle[cycle]
+
+		points := capAt(tx.Amount.Mul(e.rules.BaseRate).Mul(mult), e.rules.PointsCapPerCycle, soFar.Points).Round(2)
+		cashback := capAt(tx.Amount.Mul(e.rules.CashbackRate).Mul(mult), e.rules.CashbackCapPerCycle, soFar.Cashback).Round(2)
+
+		if points.IsZero() && cashback.IsZero() {
+			continue
+		}
+
+		perCycle[cycle] = soFar.Add(domain.RewardsBalance{Points: points, Cashback: cashback})
+		entries = append(entries, domain.RewardEntry{
+			AccountID: accountID,
+			Cycle:     cycle,
+			Date:      tx.Date,
+			Category:  tx.Category,
+			Amount:    tx.Amount,
+			Points:    points,
+			Cashback:  cashback,
+		})
+	}
+	return entries
+}
+
+func capAt(v, limit, used decimal.Decimal) decimal.Decimal {
+	if limit.LessThanOrEqual(decimal.Zero) {
+		return v
+	}
+	remaining := limit.Sub(used)
+	if remaining.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	return decimal.Min(v, remaining)
+}
+
+func rewardCycles(txs []domain.Transaction) []string {
+	seen := map[string]bool{}
+	var cycles []string
+	for _, tx := range txs {
+		c := monthKey(tx.Date)
+		if !seen[c] {
+			seen[c] = true
+			cycles = append(cycles, c)
+		}
+	}
+	sort.Strings(cycles)
+	return cycles
+}
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+func testRewardRules() domain.RewardRules {
+	return domain.RewardRules{
+		BaseRate:     dFromInt(1),
+		CashbackRate: dFromStr("0.01"),
+		CategoryMultipliers: map[string]decimal.Decimal{
+			"groceries": dFromInt(2),
+		},
+	}
+}
+
+func TestRewardsEngine_Accrue_OnlyCredits(t *testing.T) {
+	engine := NewRewardsEngine(testRewardRules())
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+		{Date: time.Date(2021, 7, 11, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-40)},
+	}
+
+	entries := engine.Accrue("acc-1", txs, nil)
+
+	if len(entries) != 1 {
+		t.Fatalf("se esperaba 1 entrada, obtenido %d", len(entries))
+	}
+	e := entries[0]
+	if e.AccountID != "acc-1" || e.Cycle != "2021-07" {
+		t.Errorf("entrada con cuenta/ciclo incorrectos: %s/%s", e.AccountID, e.Cycle)
+	}
+	assertDecEqual(t, e.Points, dFromInt(100), "Points")
+	assertDecEqual(t, e.Cashback, dFromInt(1), "Cashback")
+}
+
+func TestRewardsEngine_Accrue_CategoryMultiplier(t *testing.T) {
+	engine := NewRewardsEngine(testRewardRules())
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 10, 0, 0, 0, 0, time.UTC), Amount: dFromStr("50.5"), Category: "Groceries"},
+	}
+
+	entries := engine.Accrue("acc-1", txs, nil)
+
+	if len(entries) != 1 {
+		t.Fatalf("se esperaba 1 entrada, obtenido %d", len(entries))
+	}
+	assertDecEqual(t, entries[0].Points, dFromInt(101), "Points")
+	assertDecEqual(t, entries[0].Cashback, dFromStr("1.01"), "Cashback")
+}
+
+func TestRewardsEngine_Accrue_CapPerCycle(t *testing.T) {
+	rules := testRewardRules()
+	rules.PointsCapPerCycle = dFromInt(150)
+	rules.CashbackCapPerCycle = dFromStr("1.50")
+	engine := NewRewardsEngine(rules)
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 20, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+		{Date: time.Date(2021, 7, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+		{Date: time.Date(2021, 7, 25, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+		{Date: time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+	}
+
+	entries := engine.Accrue("acc-1", txs, nil)
+
+	if len(entries) != 3 {
+		t.Fatalf("se esperaban 3 entradas (la tercera de julio queda en cero), obtenido %d", len(entries))
+	}
+	if !entries[0].Date.Equal(txs[1].Date) {
+		t.Errorf("las entradas deben procesarse en orden de fecha, primera = %v", entries[0].Date)
+	}
+	assertDecEqual(t, entries[0].Points, dFromInt(100), "Points jul #1")
+	assertDecEqual(t, entries[1].Points, dFromInt(50), "Points jul #2 (cap)")
+	assertDecEqual(t, entries[1].Cashback, dFromStr("0.5"), "Cashback jul #2 (cap)")
+	if entries[2].Cycle != "2021-08" {
+		t.Errorf("el cap debe reiniciarse en el siguiente ciclo, obtenido %s", entries[2].Cycle)
+	}
+	assertDecEqual(t, entries[2].Points, dFromInt(100), "Points ago")
+}
+
+func TestRewardsEngine_Accrue_CapConsidersPriorAccrual(t *testing.T) {
+	rules := testRewardRules()
+	rules.PointsCapPerCycle = dFromInt(150)
+	engine := NewRewardsEngine(rules)
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+	}
+	accrued := map[string]domain.RewardsBalance{
+		"2021-07": {Points: dFromInt(120), Cashback: dFromInt(0)},
+	}
+
+	entries := engine.Accrue("acc-1", txs, accrued)
+
+	if len(entries) != 1 {
+		t.Fatalf("se esperaba 1 entrada, obtenido %d", len(entries))
+	}
+	assertDecEqual(t, entries[0].Points, dFromInt(30), "Points con acumulado previo")
+	assertDecEqual(t, entries[0].Cashback, dFromInt(1), "Cashback sin cap")
+}
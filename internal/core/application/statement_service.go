@@ -0,0 +1,110 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	portin "stori-challenge/internal/core/ports/in"
+	"stori-challenge/internal/core/ports/out"
+)
+
+var _ portin.StatementUseCase = (*StatementService)(nil)
+
+// StatementService arma los estados de cuenta mensuales con las transacciones
+// ya guardadas, sin releer los archivos de S3.
+type StatementService struct {
+	txRepo      out.TransactionRepo
+	ledger      out.StatementLedger
+	emailSender out.EmailSender
+}
+
+func NewStatementService(
+	txRepo out.TransactionRepo,
+	ledger out.StatementLedger,
+	emailSender out.EmailSender,
+) *StatementService {
+	return &StatementService{
+		txRepo:      txRepo,
+		ledger:      ledger,
+		emailSender: emailSender,
+	}
+}
+
+func (s *StatementService) SendMonthlyStatements(
+	ctx context.Context,
+	period string,
+) (domain.StatementRunResult, error) {
+	from, err := time.Parse("2006-01", period)
+	if err != nil {
+		return domain.StatementRunResult{}, fmt.Errorf("%w: periodo %q inválido, se espera YYYY-MM", domain.ErrInvalidQuery, period)
+	}
+	to := from.AddDate(0, 1, 0)
+
+	result := domain.StatementRunResult{Period: period}
+
+	accounts, err := s.txRepo.ListAccountsWithTransactions(ctx, from, to)
+	if err != nil {
+		return result, err
+	}
+	result.Accounts = len(accounts)
+
+	for _, accountID := range accounts {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		st, claimed, err := s.ledger.ClaimStatement(ctx, accountID, period)
+		if err != nil {
+			return result, err
+		}
+		if !claimed {
+			result.Skipped++
+			continue
+		}
+
+		sendErr := s.sendStatement(ctx, &st, from, to)
+
+		now := time.Now().UTC()
+		st.Status = domain.StatementStatusSent
+		st.SentAt = &now
+		if sendErr != nil {
+			st.Status = domain.StatementStatusFailed
+			st.Error = sendErr.Error()
+			st.SentAt = nil
+		}
+		if err := s.ledger.FinishStatement(ctx, st); err != nil {
+			return result, errors.Join(sendErr, err)
+		}
+
+		if sendErr != nil {
+			result.Failed++
+			result.Failures = append(result.Failures, domain.StatementFailure{AccountID: accountID, Error: sendErr.Error()})
+			continue
+		}
+		result.Sent++
+	}
+	return result, nil
+}
+
+func (s *StatementService) sendStatement(
+	ctx context.Context,
+	st *domain.Statement,
+	from, to time.Time,
+) error {
+	txs, err := s.txRepo.ListTransactionsByAccount(ctx, st.AccountID, from, to)
+	if err != nil {
+		return err
+	}
+	st.TransactionsCount = len(txs)
+
+	summary := buildAccountSummary(txs)
+	summary.AccountID = st.AccountID
+	if err := attachComparisons(ctx, s.txRepo, &summary); err != nil {
+		return err
+	}
+
+	return s.emailSender.SendSummaryEmail(ctx, summary)
+}
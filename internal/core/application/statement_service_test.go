@@ -0,0 +1,155 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+type fakeLedger struct {
+	status   map[string]domain.StatementStatus
+	claimErr error
+
+	finished []domain.Statement
+}
+
+func (f *fakeLedger) ClaimStatement(_ context.Context, accountID, period string) (domain.Statement, bool, error) {
+	if f.claimErr != nil {
+		return domain.Statement{}, false, f.claimErr
+	}
+	if st, ok := f.status[accountID]; ok && st != domain.StatementStatusFailed {
+		return domain.Statement{}, false, nil
+	}
+	return domain.Statement{
+		ID:        uint64(len(f.finished) + 1),
+		AccountID: accountID,
+		Period:    period,
+		Status:    domain.StatementStatusSending,
+	}, true, nil
+}
+
+func (f *fakeLedger) FinishStatement(_ context.Context, st domain.Statement) error {
+	f.finished = append(f.finished, st)
+	return nil
+}
+
+type fakeStatementSender struct {
+	errs map[string]error
+	sent []domain.AccountSummary
+}
+
+func (f *fakeStatementSender) SendSummaryEmail(_ context.Context, summary domain.AccountSummary) error {
+	f.sent = append(f.sent, summary)
+	return f.errs[summary.AccountID]
+}
+
+func TestStatementService_SendMonthlyStatements_SendsOnePerAccount(t *testing.T) {
+	repo := &fakeTxRepo{
+		accounts: []string{"acc-1", "acc-2"},
+		historyByAccount: map[string][]domain.Transaction{
+			"acc-1": {
+				{Date: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+				{Date: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-40)},
+			},
+			"acc-2": {
+				{Date: time.Date(2024, 3, 20, 0, 0, 0, 0, time.UTC), Amount: dFromInt(15)},
+			},
+		},
+	}
+	ledger := &fakeLedger{}
+	sender := &fakeStatementSender{}
+	svc := NewStatementService(repo, ledger, sender)
+
+	res, err := svc.SendMonthlyStatements(context.Background(), "2024-03")
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	wantFrom := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	if !repo.gotAccountFrom.Equal(wantFrom) || !repo.gotAccountTo.Equal(wantFrom.AddDate(0, 1, 0)) {
+		t.Errorf("rango consultado [%s, %s)", repo.gotAccountFrom, repo.gotAccountTo)
+	}
+	if res.Period != "2024-03" || res.Accounts != 2 || res.Sent != 2 || res.Skipped != 0 || res.Failed != 0 {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+
+	if len(sender.sent) != 2 || sender.sent[0].AccountID != "acc-1" || sender.sent[1].AccountID != "acc-2" {
+		t.Fatalf("correos enviados: %+v", sender.sent)
+	}
+	assertDecEqual(t, sender.sent[0].TotalBalance, dFromInt(60), "balance acc-1")
+
+	if len(ledger.finished) != 2 {
+		t.Fatalf("se esperaban 2 entradas en el ledger, hay %d", len(ledger.finished))
+	}
+	for _, st := range ledger.finished {
+		if st.Status != domain.StatementStatusSent || st.SentAt == nil {
+			t.Errorf("entrada inesperada: %+v", st)
+		}
+	}
+	if ledger.finished[0].TransactionsCount != 2 {
+		t.Errorf("TransactionsCount = %d, se esperaba 2", ledger.finished[0].TransactionsCount)
+	}
+}
+
+func TestStatementService_SendMonthlyStatements_SkipsAlreadySent(t *testing.T) {
+	repo := &fakeTxRepo{accounts: []string{"acc-1", "acc-2", "acc-3"}}
+	ledger := &fakeLedger{status: map[string]domain.StatementStatus{
+		"acc-1": domain.StatementStatusSent,
+		"acc-2": domain.StatementStatusFailed,
+	}}
+	sender := &fakeStatementSender{}
+
+	res, err := NewStatementService(repo, ledger, sender).SendMonthlyStatements(context.Background(), "2024-03")
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if res.Skipped != 1 || res.Sent != 2 {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+	for _, s := range sender.sent {
+		if s.AccountID == "acc-1" {
+			t.Errorf("acc-1 ya tenía el estado enviado")
+		}
+	}
+}
+
+func TestStatementService_SendMonthlyStatements_RecordsFailures(t *testing.T) {
+	repo := &fakeTxRepo{accounts: []string{"acc-1", "acc-2"}}
+	ledger := &fakeLedger{}
+	sender := &fakeStatementSender{errs: map[string]error{"acc-1": errors.New("ses caído")}}
+
+	res, err := NewStatementService(repo, ledger, sender).SendMonthlyStatements(context.Background(), "2024-03")
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if res.Sent != 1 || res.Failed != 1 || len(res.Failures) != 1 || res.Failures[0].AccountID != "acc-1" {
+		t.Errorf("resultado inesperado: %+v", res)
+	}
+	if st := ledger.finished[0]; st.Status != domain.StatementStatusFailed || st.Error != "ses caído" || st.SentAt != nil {
+		t.Errorf("entrada inesperada: %+v", st)
+	}
+}
+
+func TestStatementService_SendMonthlyStatements_InvalidPeriod(t *testing.T) {
+	svc := NewStatementService(&fakeTxRepo{}, &fakeLedger{}, &fakeStatementSender{})
+
+	if _, err := svc.SendMonthlyStatements(context.Background(), "marzo"); !errors.Is(err, domain.ErrInvalidQuery) {
+		t.Fatalf("se esperaba ErrInvalidQuery, obtenido %v", err)
+	}
+}
+
+func TestStatementService_SendMonthlyStatements_LedgerErrorStops(t *testing.T) {
+	claimErr := errors.New("db caída")
+	sender := &fakeStatementSender{}
+	svc := NewStatementService(&fakeTxRepo{accounts: []string{"acc-1"}}, &fakeLedger{claimErr: claimErr}, sender)
+
+	if _, err := svc.SendMonthlyStatements(context.Background(), "2024-03"); !errors.Is(err, claimErr) {
+		t.Fatalf("se esperaba %v, obtenido %v", claimErr, err)
+	}
+	if len(sender.sent) != 0 {
+		t.Errorf("no se debía enviar sin reclamar el ledger")
+	}
+}
@@ -2,9 +2,14 @@ package application
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
 	"stori-challenge/internal/core/domain"
 	portin "stori-challenge/internal/core/ports/in"
 	"stori-challenge/internal/core/ports/out"
+	"strconv"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -16,35 +21,177 @@ type SummaryService struct {
 	txReader    out.TransactionFileReader
 	emailSender out.EmailSender
 	txRepo      out.TransactionRepo
+
+	rewards       *RewardsEngine
+	rewardsLedger out.RewardsLedger
+	forecaster    *Forecaster
+	lifecycle     out.ObjectLifecycle
+}
+
+type SummaryServiceOption func(*SummaryService)
+
+func WithRewards(engine *RewardsEngine, ledger out.RewardsLedger) SummaryServiceOption {
+	return func(s *SummaryService) {
+		s.rewards = engine
+		s.rewardsLedger = ledger
+	}
+}
+
+func WithForecaster(f *Forecaster) SummaryServiceOption {
+	return func(s *SummaryService) {
+		s.forecaster = f
+	}
+}
+
+// WithObjectLifecycle mueve cada objeto al terminar: a processed/ si la corrida
+// termina bien y a quarantine/, con un reporte de error, si falla.
+func WithObjectLifecycle(lc out.ObjectLifecycle) SummaryServiceOption {
+	return func(s *SummaryService) {
+		s.lifecycle = lc
+	}
 }
 
 func NewSummaryService(
 	txReader out.TransactionFileReader,
 	emailSender out.EmailSender,
 	txRepo out.TransactionRepo,
+	opts ...SummaryServiceOption,
 ) *SummaryService {
-	return &SummaryService{
+	s := &SummaryService{
 		txReader:    txReader,
 		emailSender: emailSender,
 		txRepo:      txRepo,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *SummaryService) ProcessTransactionsFromObject(
 	ctx context.Context,
-	bucket string,
-	key string,
+	obj domain.ObjectRef,
+) error {
+	run, err := s.txRepo.StartProcessingRun(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	err = s.processObject(ctx, obj, &run)
+	markRun(&run, err)
+	// El objeto ya se procesó: reintentar por un fallo al moverlo duplicaría
+	// transacciones y correos, así que el fallo solo queda en la corrida.
+	if settleErr := s.settleObject(ctx, obj, run, err); settleErr != nil {
+		msg := "ciclo de vida del objeto: " + settleErr.Error()
+		if run.Error != "" {
+			msg = run.Error + "; " + msg
+		}
+		run.Error = msg
+	}
+	if finishErr := s.txRepo.FinishProcessingRun(ctx, run); finishErr != nil {
+		return errors.Join(err, finishErr)
+	}
+	return err
+}
+
+func markRun(run *domain.ProcessingRun, err error) {
+	run.Status = domain.RunStatusSucceeded
+	if err != nil {
+		run.Status = domain.RunStatusFailed
+		run.Error = err.Error()
+	}
+}
+
+func (s *SummaryService) processObject(
+	ctx context.Context,
+	obj domain.ObjectRef,
+	run *domain.ProcessingRun,
+) error {
+	files, err := s.txReader.ReadObjectFiles(ctx, obj)
+	if err != nil {
+		return err
+	}
+
+	if len(files) == 1 && files[0].Entry == "" {
+		run.TransactionsCount = len(files[0].Transactions)
+		run.Dialect = files[0].Dialect
+		if files[0].Err != nil {
+			return fileError{files[0].Err}
+		}
+		return s.processTransactions(ctx, obj, files[0])
+	}
+
+	// Un zip: cada entrada tiene su propia corrida y su resumen; la corrida del
+	// objeto suma las transacciones y falla si falla alguna entrada.
+	var errs []error
+	for _, f := range files {
+		run.TransactionsCount += len(f.Transactions)
+		if err := s.processEntry(ctx, obj.WithEntry(f.Entry), f); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.Entry, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *SummaryService) processEntry(
+	ctx context.Context,
+	entry domain.ObjectRef,
+	file domain.TransactionFile,
 ) error {
-	transactions, err := s.txReader.ReadTransactionsFromObjectParallel(ctx, bucket, key)
+	run, err := s.txRepo.StartProcessingRun(ctx, entry)
 	if err != nil {
 		return err
 	}
 
+	if file.Err != nil {
+		err = fileError{file.Err}
+	} else {
+		err = s.processTransactions(ctx, entry, file)
+	}
+	run.TransactionsCount = len(file.Transactions)
+	run.Dialect = file.Dialect
+	markRun(&run, err)
+
+	if finishErr := s.txRepo.FinishProcessingRun(ctx, run); finishErr != nil {
+		return errors.Join(err, finishErr)
+	}
+	return err
+}
+
+// processTransactions arma, guarda y envía el resumen de un archivo lógico.
+func (s *SummaryService) processTransactions(
+	ctx context.Context,
+	obj domain.ObjectRef,
+	file domain.TransactionFile,
+) error {
+	bucket, key := obj.Bucket, obj.FileKey()
+	transactions := file.Transactions
+
+	// Un replay o un reintento vuelve a procesar el archivo: se descarta lo
+	// anterior antes de calcular comparativos y topes de recompensas, que si
+	// no lo contarían dos veces.
+	if err := s.txRepo.DeleteObjectData(ctx, bucket, key); err != nil {
+		return err
+	}
+
 	summary := buildAccountSummary(transactions)
+	summary.AccountID = domain.AccountIDFromObjectKey(key)
+	summary.OpeningBalance = file.OpeningBalance
+	summary.ClosingBalance = file.ClosingBalance
+
+	if err := attachComparisons(ctx, s.txRepo, &summary); err != nil {
+		return err
+	}
 
 	if err := s.txRepo.SaveTransactions(ctx, bucket, key, transactions); err != nil {
 		return err
 	}
+	if err := s.accrueRewards(ctx, bucket, key, transactions, &summary); err != nil {
+		return err
+	}
+	if err := s.attachForecast(ctx, key, transactions, &summary); err != nil {
+		return err
+	}
 	if err := s.txRepo.SaveSummary(ctx, bucket, key, summary); err != nil {
 		return err
 	}
@@ -56,6 +203,185 @@ func (s *SummaryService) ProcessTransactionsFromObject(
 	return nil
 }
 
+// settleObject archiva el objeto según el resultado de la corrida. Un fallo
+// transitorio con reintentos pendientes lo deja en su lugar; a cuarentena van
+// los archivos inválidos y los fallos del último intento.
+func (s *SummaryService) settleObject(
+	ctx context.Context,
+	obj domain.ObjectRef,
+	run domain.ProcessingRun,
+	runErr error,
+) error {
+	if s.lifecycle == nil {
+		return nil
+	}
+	if run.Status != domain.RunStatusSucceeded && !isFileError(runErr) && domain.HasPendingRetry(ctx) {
+		return nil
+	}
+
+	exists, err := s.lifecycle.ObjectExists(ctx, obj)
+	if err != nil || !exists {
+		return err
+	}
+
+	tags := map[string]string{
+		domain.ObjectTagRunID:  strconv.FormatUint(run.ID, 10),
+		domain.ObjectTagStatus: string(run.Status),
+	}
+	if run.Status == domain.RunStatusSucceeded {
+		return s.lifecycle.MoveObject(ctx, obj, domain.SettledKey(domain.ProcessedPrefix, obj.Key), tags)
+	}
+
+	dstKey := domain.SettledKey(domain.QuarantinePrefix, obj.Key)
+	report := domain.ErrorReport{
+		RunID:     run.ID,
+		Bucket:    obj.Bucket,
+		Key:       obj.Key,
+		VersionID: obj.VersionID,
+		Error:     run.Error,
+		FailedAt:  time.Now().UTC(),
+	}
+	err = s.lifecycle.PutErrorReport(ctx, obj.Bucket, domain.ErrorReportKey(dstKey), report, tags)
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		err = s.lifecycle.PutErrorReport(ctx, obj.Bucket, domain.RunErrorReportKey(dstKey, run.ID), report, tags)
+	}
+	if err != nil {
+		return err
+	}
+	return s.lifecycle.MoveObject(ctx, obj, dstKey, tags)
+}
+
+// fileError marca los errores del contenido del archivo (formato, filas
+// inválidas): reintentar no los corrige.
+type fileError struct {
+	err error
+}
+
+func (e fileError) Error() string { return e.err.Error() }
+func (e fileError) Unwrap() error { return e.err }
+
+// isFileError es true si todos los errores de err son del archivo; en un zip,
+// basta una entrada con un fallo transitorio para esperar al reintento.
+func isFileError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			if !isFileError(e) {
+				return false
+			}
+		}
+		return true
+	}
+	var fe fileError
+	return errors.As(err, &fe)
+}
+
+func (s *SummaryService) accrueRewards(
+	ctx context.Context,
+	bucket, key string,
+	txs []domain.Transaction,
+	summary *domain.AccountSummary,
+) error {
+	if s.rewards == nil || s.rewardsLedger == nil {
+		return nil
+	}
+
+	accountID := domain.AccountIDFromObjectKey(key)
+
+	accrued, err := s.rewardsLedger.GetCycleBalances(ctx, accountID, rewardCycles(txs))
+	if err != nil {
+		return err
+	}
+
+	entries := s.rewards.Accrue(accountID, txs, accrued)
+	if err := s.rewardsLedger.SaveRewardEntries(ctx, bucket, key, entries); err != nil {
+		return err
+	}
+
+	lifetime, err := s.rewardsLedger.GetLifetimeBalance(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	var earned domain.RewardsBalance
+	for _, e := range entries {
+		earned = earned.Add(domain.RewardsBalance{Points: e.Points, Cashback: e.Cashback})
+	}
+
+	summary.Rewards = &domain.RewardsSummary{
+		PointsEarned:     earned.Points,
+		CashbackEarned:   earned.Cashback,
+		LifetimePoints:   lifetime.Points,
+		LifetimeCashback: lifetime.Cashback,
+	}
+	return nil
+}
+
+func (s *SummaryService) attachForecast(
+	ctx context.Context,
+	key string,
+	txs []domain.Transaction,
+	summary *domain.AccountSummary,
+) error {
+	if s.forecaster == nil || len(txs) == 0 {
+		return nil
+	}
+
+	from, to := s.forecaster.HistoryRange(latestDate(txs))
+	history, err := s.txRepo.ListTransactionsByAccount(ctx, domain.AccountIDFromObjectKey(key), from, to)
+	if err != nil {
+		return err
+	}
+
+	summary.Forecast = s.forecaster.Forecast(history, txs, summary.CurrentBalance())
+	return nil
+}
+
+// attachComparisons completa los comparativos mes a mes y año a año, buscando
+// en la base los meses base que no vienen en el resumen.
+func attachComparisons(ctx context.Context, txRepo out.TransactionRepo, summary *domain.AccountSummary) error {
+	if len(summary.ByMonth) == 0 {
+		return nil
+	}
+
+	known := map[string]domain.MonthlySummary{}
+	for _, m := range summary.ByMonth {
+		known[m.MonthName] = m
+	}
+
+	var missing []string
+	for _, m := range summary.ByMonth {
+		for _, baseline := range []string{shiftMonth(m.MonthName, -1), shiftMonth(m.MonthName, -12)} {
+			if _, ok := known[baseline]; !ok && baseline != "" && !slices.Contains(missing, baseline) {
+				missing = append(missing, baseline)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		stored, err := txRepo.FindLatestMonthlySummaries(ctx, summary.AccountID, missing)
+		if err != nil {
+			return err
+		}
+		for k, v := range stored {
+			known[k] = v
+		}
+	}
+
+	for i := range summary.ByMonth {
+		m := &summary.ByMonth[i]
+		if prev, ok := known[shiftMonth(m.MonthName, -1)]; ok {
+			m.VsPreviousMonth = m.DeltaFrom(prev)
+		}
+		if prev, ok := known[shiftMonth(m.MonthName, -12)]; ok {
+			m.VsPreviousYear = m.DeltaFrom(prev)
+		}
+	}
+	return nil
+}
+
 func buildAccountSummary(txs []domain.Transaction) domain.AccountSummary {
 	var total decimal.Decimal
 	for _, tx := range txs {
@@ -95,6 +421,8 @@ func buildAccountSummary(txs []domain.Transaction) domain.AccountSummary {
 			MonthName:         k,
 			TransactionsCount: a.count,
 		}
+		ms.TotalDebitAmount = a.sumDebit
+		ms.TotalCreditAmount = a.sumCredit
 		if a.countDebit > 0 {
 			ms.AverageDebitAmount = a.sumDebit.Div(decimal.NewFromInt(int64(a.countDebit)))
 		}
@@ -103,6 +431,9 @@ func buildAccountSummary(txs []domain.Transaction) domain.AccountSummary {
 		}
 		byMonth = append(byMonth, ms)
 	}
+	sort.Slice(byMonth, func(i, j int) bool {
+		return byMonth[i].MonthName < byMonth[j].MonthName
+	})
 
 	return domain.AccountSummary{
 		TotalBalance: total,
@@ -113,3 +444,13 @@ func buildAccountSummary(txs []domain.Transaction) domain.AccountSummary {
 func monthKey(t time.Time) string {
 	return t.Format("2006-01")
 }
+
+// shiftMonth desplaza una clave "2006-01" n meses; devuelve "" si la clave no
+// es válida.
+func shiftMonth(key string, n int) string {
+	t, err := time.Parse("2006-01", key)
+	if err != nil {
+		return ""
+	}
+	return monthKey(t.AddDate(0, n, 0))
+}
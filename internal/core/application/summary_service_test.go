@@ -3,6 +3,7 @@ package application
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
@@ -34,35 +35,49 @@ type fakeTxReader struct {
 	resultTxs []domain.Transaction
 	err       error
 
-	called       bool
-	calledPar    bool
-	gotBucket    string
-	gotKey       string
-	gotBucketPar string
-	gotKeyPar    string
+	// files, si viene, reemplaza al único archivo armado con resultTxs.
+	files []domain.TransactionFile
+
+	called      bool
+	calledFiles bool
+	gotBucket   string
+	gotKey      string
+	gotObjFiles domain.ObjectRef
 }
 
 func (f *fakeTxReader) ReadTransactionsFromObject(
 	_ context.Context,
-	bucket, key string,
+	obj domain.ObjectRef,
 ) ([]domain.Transaction, error) {
 	f.called = true
-	f.gotBucket = bucket
-	f.gotKey = key
+	f.gotBucket = obj.Bucket
+	f.gotKey = obj.Key
 	return f.resultTxs, f.err
 }
 
-// Necesario porque SummaryService ahora invoca la versión paralela.
 func (f *fakeTxReader) ReadTransactionsFromObjectParallel(
 	_ context.Context,
-	bucket, key string,
+	_ domain.ObjectRef,
 ) ([]domain.Transaction, error) {
-	f.calledPar = true
-	f.gotBucketPar = bucket
-	f.gotKeyPar = key
 	return f.resultTxs, f.err
 }
 
+// Necesario porque SummaryService lee los archivos lógicos del objeto.
+func (f *fakeTxReader) ReadObjectFiles(
+	_ context.Context,
+	obj domain.ObjectRef,
+) ([]domain.TransactionFile, error) {
+	f.calledFiles = true
+	f.gotObjFiles = obj
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.files != nil {
+		return f.files, nil
+	}
+	return []domain.TransactionFile{{Transactions: f.resultTxs}}, nil
+}
+
 type fakeTxRepo struct {
 	saveTxErr      error
 	saveSummaryErr error
@@ -77,6 +92,43 @@ type fakeTxRepo struct {
 	gotBucketSummary string
 	gotKeySummary    string
 	gotSummary       domain.AccountSummary
+	summaryKeys      []string
+
+	history          []domain.Transaction
+	historyByAccount map[string][]domain.Transaction
+	gotHistAccount   string
+	gotHistFrom      time.Time
+	gotHistTo        time.Time
+
+	storedMonths   map[string]domain.MonthlySummary
+	gotMonthsQuery []string
+
+	startRunErr  error
+	finishRunErr error
+	startedRuns  int
+	finishedRun  *domain.ProcessingRun
+	finishedRuns []domain.ProcessingRun
+
+	page        domain.TransactionPage
+	gotTxQuery  domain.TransactionQuery
+	stored      domain.StoredSummary
+	storedErr   error
+	runs        []domain.ProcessingRun
+	gotRunQuery domain.RunQuery
+	aggregated  []domain.MonthlySummary
+
+	accounts       []string
+	gotAccountFrom time.Time
+	gotAccountTo   time.Time
+
+	deleteErr         error
+	deletedKeys       []string
+	deletedBeforeSave bool
+}
+
+func (f *fakeTxRepo) DeleteObjectData(_ context.Context, bucket, key string) error {
+	f.deletedKeys = append(f.deletedKeys, bucket+"/"+key)
+	return f.deleteErr
 }
 
 func (f *fakeTxRepo) SaveTransactions(
@@ -84,6 +136,7 @@ func (f *fakeTxRepo) SaveTransactions(
 	bucket, key string,
 	txs []domain.Transaction,
 ) error {
+	f.deletedBeforeSave = slices.Contains(f.deletedKeys, bucket+"/"+key)
 	f.saveTxCalled = true
 	f.gotBucketTx = bucket
 	f.gotKeyTx = key
@@ -100,9 +153,171 @@ func (f *fakeTxRepo) SaveSummary(
 	f.gotBucketSummary = bucket
 	f.gotKeySummary = key
 	f.gotSummary = summary
+	f.summaryKeys = append(f.summaryKeys, key)
 	return f.saveSummaryErr
 }
 
+func (f *fakeTxRepo) ListTransactionsByAccount(
+	_ context.Context,
+	accountID string,
+	from, to time.Time,
+) ([]domain.Transaction, error) {
+	f.gotHistAccount = accountID
+	f.gotHistFrom = from
+	f.gotHistTo = to
+	if f.historyByAccount != nil {
+		return f.historyByAccount[accountID], nil
+	}
+	return f.history, nil
+}
+
+func (f *fakeTxRepo) ListAccountsWithTransactions(_ context.Context, from, to time.Time) ([]string, error) {
+	f.gotAccountFrom = from
+	f.gotAccountTo = to
+	return f.accounts, nil
+}
+
+func (f *fakeTxRepo) FindLatestMonthlySummaries(
+	_ context.Context,
+	_ string,
+	months []string,
+) (map[string]domain.MonthlySummary, error) {
+	f.gotMonthsQuery = months
+	result := map[string]domain.MonthlySummary{}
+	for _, m := range months {
+		if ms, ok := f.storedMonths[m]; ok {
+			result[m] = ms
+		}
+	}
+	return result, nil
+}
+
+func (f *fakeTxRepo) ListTransactions(
+	_ context.Context,
+	q domain.TransactionQuery,
+) (domain.TransactionPage, error) {
+	f.gotTxQuery = q
+	return f.page, nil
+}
+
+func (f *fakeTxRepo) GetSummaryByID(_ context.Context, _ uint64) (domain.StoredSummary, error) {
+	return f.stored, f.storedErr
+}
+
+func (f *fakeTxRepo) GetLatestSummaryByObject(_ context.Context, _, _ string) (domain.StoredSummary, error) {
+	return f.stored, f.storedErr
+}
+
+func (f *fakeTxRepo) GetLatestSummaryByAccount(_ context.Context, _ string) (domain.StoredSummary, error) {
+	return f.stored, f.storedErr
+}
+
+func (f *fakeTxRepo) AggregateByMonth(
+	_ context.Context,
+	accountID string,
+	from, to time.Time,
+) ([]domain.MonthlySummary, error) {
+	f.gotHistAccount = accountID
+	f.gotHistFrom = from
+	f.gotHistTo = to
+	return f.aggregated, nil
+}
+
+func (f *fakeTxRepo) CreateProcessingRun(
+	_ context.Context,
+	run domain.ProcessingRun,
+) (domain.ProcessingRun, error) {
+	run.ID = uint64(len(f.runs) + 1)
+	f.runs = append(f.runs, run)
+	return run, nil
+}
+
+func (f *fakeTxRepo) StartProcessingRun(
+	_ context.Context,
+	obj domain.ObjectRef,
+) (domain.ProcessingRun, error) {
+	if f.startRunErr != nil {
+		return domain.ProcessingRun{}, f.startRunErr
+	}
+	f.startedRuns++
+	return domain.ProcessingRun{
+		ID:        uint64(f.startedRuns),
+		Bucket:    obj.Bucket,
+		ObjectKey: obj.FileKey(),
+		VersionID: obj.VersionID,
+		Status:    domain.RunStatusProcessing,
+	}, nil
+}
+
+func (f *fakeTxRepo) FinishProcessingRun(_ context.Context, run domain.ProcessingRun) error {
+	f.finishedRun = &run
+	f.finishedRuns = append(f.finishedRuns, run)
+	return f.finishRunErr
+}
+
+func (f *fakeTxRepo) GetProcessingRun(_ context.Context, id uint64) (domain.ProcessingRun, error) {
+	for _, run := range f.runs {
+		if run.ID == id {
+			return run, nil
+		}
+	}
+	return domain.ProcessingRun{}, domain.ErrNotFound
+}
+
+func (f *fakeTxRepo) ListProcessingRuns(
+	_ context.Context,
+	q domain.RunQuery,
+) ([]domain.ProcessingRun, error) {
+	f.gotRunQuery = q
+	return f.runs, nil
+}
+
+type movedObject struct {
+	src    domain.ObjectRef
+	dstKey string
+	tags   map[string]string
+}
+
+type fakeLifecycle struct {
+	moveErr         error
+	missing         bool
+	existingReports []string
+
+	moved      []movedObject
+	reportKey  string
+	report     *domain.ErrorReport
+	reportTags map[string]string
+}
+
+func (f *fakeLifecycle) ObjectExists(_ context.Context, _ domain.ObjectRef) (bool, error) {
+	return !f.missing, nil
+}
+
+func (f *fakeLifecycle) MoveObject(
+	_ context.Context,
+	src domain.ObjectRef,
+	dstKey string,
+	tags map[string]string,
+) error {
+	f.moved = append(f.moved, movedObject{src: src, dstKey: dstKey, tags: tags})
+	return f.moveErr
+}
+
+func (f *fakeLifecycle) PutErrorReport(
+	_ context.Context,
+	_, key string,
+	report domain.ErrorReport,
+	tags map[string]string,
+) error {
+	if slices.Contains(f.existingReports, key) {
+		return domain.ErrAlreadyExists
+	}
+	f.reportKey = key
+	f.report = &report
+	f.reportTags = tags
+	return nil
+}
+
 type fakeEmailSender struct {
 	err error
 
@@ -119,6 +334,40 @@ func (f *fakeEmailSender) SendSummaryEmail(
 	return f.err
 }
 
+type fakeRewardsLedger struct {
+	cycleBalances map[string]domain.RewardsBalance
+	lifetime      domain.RewardsBalance
+	saveErr       error
+
+	gotCycles  []string
+	gotEntries []domain.RewardEntry
+}
+
+func (f *fakeRewardsLedger) SaveRewardEntries(
+	_ context.Context,
+	_, _ string,
+	entries []domain.RewardEntry,
+) error {
+	f.gotEntries = entries
+	return f.saveErr
+}
+
+func (f *fakeRewardsLedger) GetCycleBalances(
+	_ context.Context,
+	_ string,
+	cycles []string,
+) (map[string]domain.RewardsBalance, error) {
+	f.gotCycles = cycles
+	return f.cycleBalances, nil
+}
+
+func (f *fakeRewardsLedger) GetLifetimeBalance(
+	_ context.Context,
+	_ string,
+) (domain.RewardsBalance, error) {
+	return f.lifetime, nil
+}
+
 func TestBuildAccountSummary_SimpleMix(t *testing.T) {
 	txs := []domain.Transaction{
 		{
@@ -213,6 +462,48 @@ func TestBuildAccountSummary_Empty(t *testing.T) {
 	}
 }
 
+func TestBuildAccountSummary_TotalsAndOrder(t *testing.T) {
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-5)},
+		{Date: time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-10)},
+		{Date: time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-20)},
+		{Date: time.Date(2021, 7, 3, 0, 0, 0, 0, time.UTC), Amount: dFromInt(50)},
+		{Date: time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), Amount: dFromInt(7)},
+	}
+
+	sum := buildAccountSummary(txs)
+
+	var names []string
+	for _, m := range sum.ByMonth {
+		names = append(names, m.MonthName)
+	}
+	if len(names) != 3 || names[0] != "2021-07" || names[1] != "2021-08" || names[2] != "2021-09" {
+		t.Fatalf("ByMonth debe estar ordenado por mes, obtenido %v", names)
+	}
+
+	assertDecEqual(t, sum.ByMonth[0].TotalDebitAmount, dFromInt(-30), "TotalDebitAmount (jul)")
+	assertDecEqual(t, sum.ByMonth[0].TotalCreditAmount, dFromInt(50), "TotalCreditAmount (jul)")
+}
+
+func TestShiftMonth(t *testing.T) {
+	cases := map[string]struct {
+		key  string
+		n    int
+		want string
+	}{
+		"mes anterior":      {"2021-08", -1, "2021-07"},
+		"cruce de año":      {"2021-01", -1, "2020-12"},
+		"año anterior":      {"2021-08", -12, "2020-08"},
+		"clave inválida":    {"agosto", -1, ""},
+		"siguiente mes dic": {"2021-12", 1, "2022-01"},
+	}
+	for name, c := range cases {
+		if got := shiftMonth(c.key, c.n); got != c.want {
+			t.Errorf("%s: shiftMonth(%q, %d) = %q, esperado %q", name, c.key, c.n, got, c.want)
+		}
+	}
+}
+
 func TestMonthKey(t *testing.T) {
 	d := time.Date(2023, 12, 25, 0, 0, 0, 0, time.UTC)
 
@@ -247,15 +538,15 @@ func TestSummaryService_ProcessTransactions_HappyPath(t *testing.T) {
 	bucket := "my-bucket"
 	key := "input/txns.csv"
 
-	if err := svc.ProcessTransactionsFromObject(ctx, bucket, key); err != nil {
+	if err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: bucket, Key: key}); err != nil {
 		t.Fatalf("no se esperaba error, obtenido: %v", err)
 	}
 
-	if !reader.calledPar {
-		t.Fatalf("txReader paralelo no fue llamado")
+	if !reader.calledFiles {
+		t.Fatalf("txReader no fue llamado")
 	}
-	if reader.gotBucketPar != bucket || reader.gotKeyPar != key {
-		t.Errorf("txReader llamado con bucket/key incorrectos: %s/%s", reader.gotBucketPar, reader.gotKeyPar)
+	if reader.gotObjFiles.Bucket != bucket || reader.gotObjFiles.Key != key {
+		t.Errorf("txReader llamado con bucket/key incorrectos: %s/%s", reader.gotObjFiles.Bucket, reader.gotObjFiles.Key)
 	}
 
 	if !repo.saveTxCalled {
@@ -283,7 +574,7 @@ func TestSummaryService_ProcessTransactions_ReaderError(t *testing.T) {
 
 	svc := NewSummaryService(reader, emailSender, repo)
 
-	err := svc.ProcessTransactionsFromObject(ctx, "bucket", "key")
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("se esperaba error del reader, pero err == nil")
 	}
@@ -307,7 +598,7 @@ func TestSummaryService_ProcessTransactions_SaveTransactionsError(t *testing.T)
 
 	svc := NewSummaryService(reader, emailSender, repo)
 
-	err := svc.ProcessTransactionsFromObject(ctx, "bucket", "key")
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("se esperaba error de SaveTransactions, pero err == nil")
 	}
@@ -320,6 +611,39 @@ func TestSummaryService_ProcessTransactions_SaveTransactionsError(t *testing.T)
 	}
 }
 
+func TestSummaryService_ProcessTransactions_DeletesPreviousDataFirst(t *testing.T) {
+	txs := []domain.Transaction{{Date: time.Now(), Amount: dFromInt(10)}}
+	repo := &fakeTxRepo{}
+
+	svc := NewSummaryService(&fakeTxReader{resultTxs: txs}, &fakeEmailSender{}, repo)
+
+	if err := svc.ProcessTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/acc/txns.csv"}); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if len(repo.deletedKeys) != 1 || repo.deletedKeys[0] != "bucket/input/acc/txns.csv" {
+		t.Fatalf("DeleteObjectData esperado para bucket/input/acc/txns.csv, obtenido %v", repo.deletedKeys)
+	}
+	if !repo.deletedBeforeSave {
+		t.Fatalf("se esperaba borrar los datos anteriores antes de guardar")
+	}
+}
+
+func TestSummaryService_ProcessTransactions_DeleteError(t *testing.T) {
+	txs := []domain.Transaction{{Date: time.Now(), Amount: dFromInt(10)}}
+	deleteErr := errors.New("falló delete")
+	repo := &fakeTxRepo{deleteErr: deleteErr}
+
+	svc := NewSummaryService(&fakeTxReader{resultTxs: txs}, &fakeEmailSender{}, repo)
+
+	err := svc.ProcessTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "key"})
+	if !errors.Is(err, deleteErr) {
+		t.Fatalf("se esperaba error %v, obtenido %v", deleteErr, err)
+	}
+	if repo.saveTxCalled || repo.saveSummaryCalled {
+		t.Fatalf("no se esperaba guardar cuando falla el borrado")
+	}
+}
+
 func TestSummaryService_ProcessTransactions_SaveSummaryError(t *testing.T) {
 	ctx := context.Background()
 
@@ -331,7 +655,7 @@ func TestSummaryService_ProcessTransactions_SaveSummaryError(t *testing.T) {
 
 	svc := NewSummaryService(reader, emailSender, repo)
 
-	err := svc.ProcessTransactionsFromObject(ctx, "bucket", "key")
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("se esperaba error de SaveSummary, pero err == nil")
 	}
@@ -358,7 +682,7 @@ func TestSummaryService_ProcessTransactions_EmailError(t *testing.T) {
 
 	svc := NewSummaryService(reader, emailSender, repo)
 
-	err := svc.ProcessTransactionsFromObject(ctx, "bucket", "key")
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("se esperaba error de EmailSender, pero err == nil")
 	}
@@ -373,3 +697,532 @@ func TestSummaryService_ProcessTransactions_EmailError(t *testing.T) {
 		t.Fatalf("EmailSender debería haber sido llamado")
 	}
 }
+
+func TestSummaryService_ProcessTransactions_WithRewards(t *testing.T) {
+	ctx := context.Background()
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+		{Date: time.Date(2021, 7, 20, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-40)},
+	}
+
+	reader := &fakeTxReader{resultTxs: txs}
+	repo := &fakeTxRepo{}
+	emailSender := &fakeEmailSender{}
+	ledger := &fakeRewardsLedger{
+		lifetime: domain.RewardsBalance{Points: dFromInt(500), Cashback: dFromInt(5)},
+	}
+
+	svc := NewSummaryService(reader, emailSender, repo,
+		WithRewards(NewRewardsEngine(testRewardRules()), ledger),
+	)
+
+	if err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if len(ledger.gotEntries) != 1 {
+		t.Fatalf("se esperaba 1 entrada en el ledger, obtenido %d", len(ledger.gotEntries))
+	}
+	if ledger.gotEntries[0].AccountID != "acc-1" {
+		t.Errorf("AccountID esperado 'acc-1', obtenido %q", ledger.gotEntries[0].AccountID)
+	}
+	if len(ledger.gotCycles) != 1 || ledger.gotCycles[0] != "2021-07" {
+		t.Errorf("ciclos consultados incorrectos: %v", ledger.gotCycles)
+	}
+
+	r := emailSender.gotSum.Rewards
+	if r == nil {
+		t.Fatalf("el resumen enviado por email debe incluir rewards")
+	}
+	assertDecEqual(t, r.PointsEarned, dFromInt(100), "PointsEarned")
+	assertDecEqual(t, r.CashbackEarned, dFromInt(1), "CashbackEarned")
+	assertDecEqual(t, r.LifetimePoints, dFromInt(500), "LifetimePoints")
+	assertDecEqual(t, r.LifetimeCashback, dFromInt(5), "LifetimeCashback")
+}
+
+func TestSummaryService_ProcessTransactions_RewardsLedgerError(t *testing.T) {
+	ctx := context.Background()
+
+	txs := []domain.Transaction{{Date: time.Now(), Amount: dFromInt(10)}}
+	reader := &fakeTxReader{resultTxs: txs}
+	repo := &fakeTxRepo{}
+	emailSender := &fakeEmailSender{}
+	ledgerErr := errors.New("falló ledger")
+	ledger := &fakeRewardsLedger{saveErr: ledgerErr}
+
+	svc := NewSummaryService(reader, emailSender, repo,
+		WithRewards(NewRewardsEngine(testRewardRules()), ledger),
+	)
+
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
+	if !errors.Is(err, ledgerErr) {
+		t.Fatalf("se esperaba error %v, obtenido %v", ledgerErr, err)
+	}
+	if repo.saveSummaryCalled || emailSender.called {
+		t.Fatalf("no se esperaba guardar resumen ni enviar email cuando falla el ledger")
+	}
+}
+
+func TestSummaryService_ProcessTransactions_WithForecast(t *testing.T) {
+	ctx := context.Background()
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 9, 15, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-50)},
+	}
+	reader := &fakeTxReader{resultTxs: txs}
+	repo := &fakeTxRepo{
+		history: []domain.Transaction{
+			{Date: time.Date(2021, 8, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-100)},
+		},
+	}
+	emailSender := &fakeEmailSender{}
+
+	svc := NewSummaryService(reader, emailSender, repo,
+		WithForecaster(NewForecaster(domain.ForecastMovingAverage, 1)),
+	)
+
+	if err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-9/txns.csv"}); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if repo.gotHistAccount != "acc-9" {
+		t.Errorf("historial consultado para cuenta %q, esperado 'acc-9'", repo.gotHistAccount)
+	}
+	if !repo.gotHistFrom.Equal(time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)) ||
+		!repo.gotHistTo.Equal(time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("rango de historial incorrecto: [%v, %v)", repo.gotHistFrom, repo.gotHistTo)
+	}
+
+	fc := emailSender.gotSum.Forecast
+	if fc == nil {
+		t.Fatalf("el resumen enviado por email debe incluir el forecast")
+	}
+	assertDecEqual(t, fc.ProjectedSpend, dFromInt(100), "ProjectedSpend")
+	assertDecEqual(t, fc.ProjectedBalance, dFromInt(-100), "ProjectedBalance")
+}
+
+func TestSummaryService_ProcessTransactions_Comparisons(t *testing.T) {
+	ctx := context.Background()
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+		{Date: time.Date(2021, 8, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-40)},
+		{Date: time.Date(2021, 8, 11, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-20)},
+	}
+	reader := &fakeTxReader{resultTxs: txs}
+	repo := &fakeTxRepo{
+		storedMonths: map[string]domain.MonthlySummary{
+			"2021-06": {MonthName: "2021-06", TransactionsCount: 4, TotalCreditAmount: dFromInt(60), AverageCreditAmount: dFromInt(15)},
+			"2020-08": {MonthName: "2020-08", TransactionsCount: 1, TotalDebitAmount: dFromInt(-10), AverageDebitAmount: dFromInt(-10)},
+			"2021-07": {MonthName: "2021-07", TransactionsCount: 99},
+		},
+	}
+	emailSender := &fakeEmailSender{}
+
+	svc := NewSummaryService(reader, emailSender, repo)
+
+	if err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	for _, m := range repo.gotMonthsQuery {
+		if m == "2021-07" {
+			t.Errorf("no se debe consultar un mes presente en el archivo actual: %v", repo.gotMonthsQuery)
+		}
+	}
+
+	sum := emailSender.gotSum
+	if sum.AccountID != "acc-1" {
+		t.Errorf("AccountID esperado 'acc-1', obtenido %q", sum.AccountID)
+	}
+	jul, aug := sum.ByMonth[0], sum.ByMonth[1]
+
+	if jul.VsPreviousMonth == nil || jul.VsPreviousMonth.BaselineMonth != "2021-06" {
+		t.Fatalf("julio debe compararse contra 2021-06, obtenido %+v", jul.VsPreviousMonth)
+	}
+	if jul.VsPreviousMonth.TransactionsCount != -3 {
+		t.Errorf("delta de conteo jul = %d, esperado -3", jul.VsPreviousMonth.TransactionsCount)
+	}
+	assertDecEqual(t, jul.VsPreviousMonth.TotalCreditAmount, dFromInt(40), "delta créditos jul")
+	assertDecEqual(t, jul.VsPreviousMonth.AverageCreditAmount, dFromInt(85), "delta promedio créditos jul")
+	if jul.VsPreviousYear != nil {
+		t.Errorf("julio no tiene datos del año anterior, obtenido %+v", jul.VsPreviousYear)
+	}
+
+	if aug.VsPreviousMonth == nil || aug.VsPreviousMonth.TransactionsCount != 1 {
+		t.Fatalf("agosto debe compararse contra julio del archivo actual, obtenido %+v", aug.VsPreviousMonth)
+	}
+	if aug.VsPreviousYear == nil || aug.VsPreviousYear.BaselineMonth != "2020-08" {
+		t.Fatalf("agosto debe compararse contra 2020-08, obtenido %+v", aug.VsPreviousYear)
+	}
+	assertDecEqual(t, aug.VsPreviousYear.TotalDebitAmount, dFromInt(-50), "delta débitos ago vs año anterior")
+	assertDecEqual(t, aug.VsPreviousYear.AverageDebitAmount, dFromInt(-20), "delta promedio débitos ago vs año anterior")
+
+	if repo.gotSummary.ByMonth[1].VsPreviousYear == nil {
+		t.Errorf("las comparaciones deben guardarse junto al resumen")
+	}
+}
+
+func TestSummaryService_ProcessTransactions_RecordsSucceededRun(t *testing.T) {
+	ctx := context.Background()
+
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 10, 0, 0, 0, 0, time.UTC), Amount: dFromInt(100)},
+		{Date: time.Date(2021, 7, 20, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-40)},
+	}
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(&fakeTxReader{resultTxs: txs}, &fakeEmailSender{}, repo)
+
+	if err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if repo.finishedRun == nil {
+		t.Fatalf("FinishProcessingRun no fue llamado")
+	}
+	if repo.finishedRun.Status != domain.RunStatusSucceeded {
+		t.Errorf("Status = %q, se esperaba %q", repo.finishedRun.Status, domain.RunStatusSucceeded)
+	}
+	if repo.finishedRun.TransactionsCount != len(txs) {
+		t.Errorf("TransactionsCount = %d, se esperaba %d", repo.finishedRun.TransactionsCount, len(txs))
+	}
+	if repo.finishedRun.Error != "" {
+		t.Errorf("Error = %q, se esperaba vacío", repo.finishedRun.Error)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_RecordsFailedRun(t *testing.T) {
+	ctx := context.Background()
+
+	readerErr := errors.New("falló reader")
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(&fakeTxReader{err: readerErr}, &fakeEmailSender{}, repo)
+
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
+	if !errors.Is(err, readerErr) {
+		t.Fatalf("se esperaba error %v, obtenido %v", readerErr, err)
+	}
+
+	if repo.finishedRun == nil {
+		t.Fatalf("FinishProcessingRun no fue llamado")
+	}
+	if repo.finishedRun.Status != domain.RunStatusFailed || repo.finishedRun.Error != readerErr.Error() {
+		t.Errorf("corrida = %+v, se esperaba failed con el error del reader", *repo.finishedRun)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_StartRunError(t *testing.T) {
+	ctx := context.Background()
+
+	startErr := errors.New("falló start run")
+	reader := &fakeTxReader{}
+	repo := &fakeTxRepo{startRunErr: startErr}
+	svc := NewSummaryService(reader, &fakeEmailSender{}, repo)
+
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
+	if !errors.Is(err, startErr) {
+		t.Fatalf("se esperaba error %v, obtenido %v", startErr, err)
+	}
+	if reader.calledFiles {
+		t.Fatalf("no se esperaba leer el objeto si no se pudo abrir la corrida")
+	}
+}
+
+func TestSummaryService_ProcessTransactions_FinishRunErrorIsJoined(t *testing.T) {
+	ctx := context.Background()
+
+	readerErr := errors.New("falló reader")
+	finishErr := errors.New("falló finish run")
+	repo := &fakeTxRepo{finishRunErr: finishErr}
+	svc := NewSummaryService(&fakeTxReader{err: readerErr}, &fakeEmailSender{}, repo)
+
+	err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
+	if !errors.Is(err, readerErr) || !errors.Is(err, finishErr) {
+		t.Fatalf("se esperaban ambos errores, obtenido %v", err)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_UsesEventVersion(t *testing.T) {
+	reader := &fakeTxReader{resultTxs: []domain.Transaction{
+		{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: dFromInt(10)},
+	}}
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(reader, &fakeEmailSender{}, repo)
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/estado de cuenta.csv", VersionID: "v-3"}
+	if err := svc.ProcessTransactionsFromObject(context.Background(), obj); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if reader.gotObjFiles != obj {
+		t.Errorf("lector invocado con %+v, se esperaba %+v", reader.gotObjFiles, obj)
+	}
+	if repo.finishedRun == nil || repo.finishedRun.VersionID != "v-3" {
+		t.Errorf("la corrida debe registrar la versión, obtenido %+v", repo.finishedRun)
+	}
+	if repo.gotKeyTx != obj.Key {
+		t.Errorf("transacciones guardadas con key %q, se esperaba %q", repo.gotKeyTx, obj.Key)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_ArchivesProcessedObject(t *testing.T) {
+	lc := &fakeLifecycle{}
+	reader := &fakeTxReader{resultTxs: []domain.Transaction{
+		{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: dFromInt(10)},
+	}}
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(reader, &fakeEmailSender{}, repo, WithObjectLifecycle(lc))
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv", VersionID: "v-1"}
+	if err := svc.ProcessTransactionsFromObject(context.Background(), obj); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if len(lc.moved) != 1 {
+		t.Fatalf("se esperaba mover el objeto una vez, movido %d veces", len(lc.moved))
+	}
+	m := lc.moved[0]
+	if m.src != obj || m.dstKey != "processed/input/acc-1/txns.csv" {
+		t.Errorf("movido %+v a %q, se esperaba processed/input/acc-1/txns.csv", m.src, m.dstKey)
+	}
+	if m.tags[domain.ObjectTagRunID] != "1" || m.tags[domain.ObjectTagStatus] != string(domain.RunStatusSucceeded) {
+		t.Errorf("tags = %v", m.tags)
+	}
+	if lc.report != nil {
+		t.Errorf("no se esperaba reporte de error para una corrida exitosa")
+	}
+}
+
+func TestSummaryService_ProcessTransactions_QuarantinesFailedObject(t *testing.T) {
+	lc := &fakeLifecycle{}
+	readerErr := errors.New("fila 3: monto inválido")
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(&fakeTxReader{err: readerErr}, &fakeEmailSender{}, repo, WithObjectLifecycle(lc))
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}
+	if err := svc.ProcessTransactionsFromObject(context.Background(), obj); !errors.Is(err, readerErr) {
+		t.Fatalf("se esperaba error %v, obtenido %v", readerErr, err)
+	}
+
+	if len(lc.moved) != 1 || lc.moved[0].dstKey != "quarantine/input/acc-1/txns.csv" {
+		t.Fatalf("se esperaba mover a quarantine/, obtenido %+v", lc.moved)
+	}
+	if lc.moved[0].tags[domain.ObjectTagStatus] != string(domain.RunStatusFailed) {
+		t.Errorf("tags = %v", lc.moved[0].tags)
+	}
+	if lc.reportKey != "quarantine/input/acc-1/txns.csv.error.json" {
+		t.Errorf("reporte en %q", lc.reportKey)
+	}
+	if lc.report == nil || lc.report.Error != readerErr.Error() || lc.report.RunID != 1 || lc.report.Key != obj.Key {
+		t.Errorf("reporte = %+v", lc.report)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_TransientFailureWaitsForRetry(t *testing.T) {
+	lc := &fakeLifecycle{}
+	readerErr := errors.New("timeout leyendo S3")
+	svc := NewSummaryService(&fakeTxReader{err: readerErr}, &fakeEmailSender{}, &fakeTxRepo{}, WithObjectLifecycle(lc))
+
+	ctx := domain.WithPendingRetry(context.Background())
+	if err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}); !errors.Is(err, readerErr) {
+		t.Fatalf("se esperaba error %v, obtenido %v", readerErr, err)
+	}
+	if len(lc.moved) != 0 || lc.report != nil {
+		t.Fatalf("un fallo transitorio con reintentos pendientes no debe ir a cuarentena, movido %+v", lc.moved)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_InvalidFileQuarantinedBeforeLastRetry(t *testing.T) {
+	lc := &fakeLifecycle{}
+	parseErr := errors.New("línea 3: monto inválido")
+	reader := &fakeTxReader{files: []domain.TransactionFile{{Err: parseErr}}}
+	svc := NewSummaryService(reader, &fakeEmailSender{}, &fakeTxRepo{}, WithObjectLifecycle(lc))
+
+	ctx := domain.WithPendingRetry(context.Background())
+	if err := svc.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}); !errors.Is(err, parseErr) {
+		t.Fatalf("se esperaba error %v, obtenido %v", parseErr, err)
+	}
+	if len(lc.moved) != 1 || lc.moved[0].dstKey != "quarantine/input/acc-1/txns.csv" {
+		t.Fatalf("un archivo inválido no mejora reintentando, se esperaba cuarentena, obtenido %+v", lc.moved)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_MissingSourceIsNotSettled(t *testing.T) {
+	lc := &fakeLifecycle{missing: true}
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(&fakeTxReader{err: errors.New("falló reader")}, &fakeEmailSender{}, repo, WithObjectLifecycle(lc))
+
+	_ = svc.ProcessTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"})
+	if len(lc.moved) != 0 || lc.report != nil {
+		t.Fatalf("no se esperaba mover ni reportar un objeto que ya no existe")
+	}
+	if repo.finishedRun == nil || repo.finishedRun.Error != "falló reader" {
+		t.Errorf("la corrida no debe registrar fallos del ciclo de vida, obtenido %+v", repo.finishedRun)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_KeepsExistingErrorReport(t *testing.T) {
+	lc := &fakeLifecycle{existingReports: []string{"quarantine/input/acc-1/txns.csv.error.json"}}
+	svc := NewSummaryService(&fakeTxReader{err: errors.New("falló reader")}, &fakeEmailSender{}, &fakeTxRepo{}, WithObjectLifecycle(lc))
+
+	_ = svc.ProcessTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"})
+	if lc.reportKey != "quarantine/input/acc-1/txns.csv.1.error.json" {
+		t.Fatalf("se esperaba guardar el reporte aparte, guardado en %q", lc.reportKey)
+	}
+	if len(lc.moved) != 1 {
+		t.Errorf("se esperaba mover el objeto a cuarentena, obtenido %+v", lc.moved)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_ReplayOfProcessedKeepsKey(t *testing.T) {
+	lc := &fakeLifecycle{}
+	svc := NewSummaryService(&fakeTxReader{}, &fakeEmailSender{}, &fakeTxRepo{}, WithObjectLifecycle(lc))
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "processed/input/acc-1/txns.csv"}
+	if err := svc.ProcessTransactionsFromObject(context.Background(), obj); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if len(lc.moved) != 1 || lc.moved[0].dstKey != obj.Key {
+		t.Fatalf("el objeto ya archivado no debe anidar prefijos, obtenido %+v", lc.moved)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_ReplayOfProcessedUsesOriginalKey(t *testing.T) {
+	reader := &fakeTxReader{resultTxs: []domain.Transaction{
+		{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: dFromInt(10)},
+	}}
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(reader, &fakeEmailSender{}, repo)
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "processed/input/acc-1/txns.csv"}
+	if err := svc.ProcessTransactionsFromObject(context.Background(), obj); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if reader.gotObjFiles.Key != obj.Key {
+		t.Errorf("se debe leer el objeto archivado, leído %q", reader.gotObjFiles.Key)
+	}
+	want := "bucket/input/acc-1/txns.csv"
+	if len(repo.deletedKeys) != 1 || repo.deletedKeys[0] != want || repo.gotKeyTx != "input/acc-1/txns.csv" {
+		t.Errorf("borrado %v y guardado %q, se esperaba la key original %s", repo.deletedKeys, repo.gotKeyTx, want)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_LifecycleErrorOnlyRecorded(t *testing.T) {
+	moveErr := errors.New("access denied")
+	lc := &fakeLifecycle{moveErr: moveErr}
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(&fakeTxReader{}, &fakeEmailSender{}, repo, WithObjectLifecycle(lc))
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}
+	if err := svc.ProcessTransactionsFromObject(context.Background(), obj); err != nil {
+		t.Fatalf("un fallo al mover no debe provocar reintentos, obtenido: %v", err)
+	}
+	if repo.finishedRun == nil || repo.finishedRun.Status != domain.RunStatusSucceeded {
+		t.Fatalf("la corrida debe seguir exitosa, obtenido %+v", repo.finishedRun)
+	}
+	if want := "ciclo de vida del objeto: access denied"; repo.finishedRun.Error != want {
+		t.Errorf("Error = %q, se esperaba %q", repo.finishedRun.Error, want)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_ZipEntriesGetTheirOwnRuns(t *testing.T) {
+	july := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	entryErr := errors.New("fila 2: monto inválido")
+	reader := &fakeTxReader{files: []domain.TransactionFile{
+		{Entry: "acc-1/txns.csv", Transactions: []domain.Transaction{{Date: july, Amount: dFromInt(10)}}},
+		{Entry: "acc-2/txns.csv", Err: entryErr},
+		{Entry: "acc-3/txns.csv", Transactions: []domain.Transaction{
+			{Date: july, Amount: dFromInt(5)},
+			{Date: july, Amount: dFromInt(-2)},
+		}},
+	}}
+	repo := &fakeTxRepo{}
+	emailSender := &fakeEmailSender{}
+	svc := NewSummaryService(reader, emailSender, repo)
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "input/export.zip"}
+	err := svc.ProcessTransactionsFromObject(context.Background(), obj)
+	if !errors.Is(err, entryErr) {
+		t.Fatalf("se esperaba el error de la entrada, obtenido %v", err)
+	}
+
+	wantKeys := []string{"input/export.zip#acc-1/txns.csv", "input/export.zip#acc-3/txns.csv"}
+	if len(repo.summaryKeys) != len(wantKeys) {
+		t.Fatalf("resúmenes guardados = %v, se esperaba %v", repo.summaryKeys, wantKeys)
+	}
+	for i, k := range wantKeys {
+		if repo.summaryKeys[i] != k {
+			t.Errorf("resumen %d con key %q, se esperaba %q", i, repo.summaryKeys[i], k)
+		}
+	}
+
+	// Tres corridas de entrada más la del zip, que termina última.
+	if len(repo.finishedRuns) != 4 {
+		t.Fatalf("se esperaban 4 corridas, obtenidas %+v", repo.finishedRuns)
+	}
+	byKey := map[string]domain.ProcessingRun{}
+	for _, run := range repo.finishedRuns {
+		byKey[run.ObjectKey] = run
+	}
+	if run := byKey["input/export.zip#acc-2/txns.csv"]; run.Status != domain.RunStatusFailed || run.Error != entryErr.Error() {
+		t.Errorf("corrida de la entrada fallida = %+v", run)
+	}
+	if run := byKey["input/export.zip#acc-3/txns.csv"]; run.Status != domain.RunStatusSucceeded || run.TransactionsCount != 2 {
+		t.Errorf("corrida de acc-3 = %+v", run)
+	}
+	container := repo.finishedRuns[3]
+	if container.ObjectKey != obj.Key || container.Status != domain.RunStatusFailed || container.TransactionsCount != 3 {
+		t.Errorf("corrida del zip = %+v", container)
+	}
+	if emailSender.gotSum.AccountID != "acc-3" {
+		t.Errorf("el último correo debe ser de acc-3, obtenido %q", emailSender.gotSum.AccountID)
+	}
+}
+
+func TestSummaryService_ProcessTransactions_StatementBalancesReachSummary(t *testing.T) {
+	july := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	opening := dFromInt(1000)
+	reader := &fakeTxReader{files: []domain.TransactionFile{{
+		Transactions: []domain.Transaction{
+			{Date: july, Amount: dFromInt(60)},
+			{Date: july, Amount: dFromInt(-10)},
+		},
+		OpeningBalance: &opening,
+	}}}
+	emailSender := &fakeEmailSender{}
+	svc := NewSummaryService(reader, emailSender, &fakeTxRepo{})
+
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/statement.xml"}
+	if err := svc.ProcessTransactionsFromObject(context.Background(), obj); err != nil {
+		t.Fatalf("no se esperaba error, obtenido %v", err)
+	}
+
+	got := emailSender.gotSum
+	if got.OpeningBalance == nil || got.ClosingBalance != nil {
+		t.Fatalf("saldos del resumen = %v / %v", got.OpeningBalance, got.ClosingBalance)
+	}
+	assertDecEqual(t, got.TotalBalance, dFromInt(50), "movimientos del archivo")
+	assertDecEqual(t, got.CurrentBalance(), dFromInt(1050), "saldo actual")
+}
+
+func TestSummaryService_ProcessTransactions_RecordsDialect(t *testing.T) {
+	july := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	dialect := domain.Dialect{Encoding: "windows-1252", BOM: true, Delimiter: ';'}
+	reader := &fakeTxReader{files: []domain.TransactionFile{{
+		Transactions: []domain.Transaction{{Date: july, Amount: dFromInt(60)}},
+		Dialect:      dialect,
+	}}}
+	repo := &fakeTxRepo{}
+	svc := NewSummaryService(reader, &fakeEmailSender{}, repo)
+
+	if err := svc.ProcessTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}); err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if repo.finishedRun == nil {
+		t.Fatalf("FinishProcessingRun no fue llamado")
+	}
+	if repo.finishedRun.Dialect != dialect {
+		t.Errorf("Dialect = %+v, se esperaba %+v", repo.finishedRun.Dialect, dialect)
+	}
+}
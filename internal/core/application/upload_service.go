@@ -0,0 +1,102 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"stori-challenge/internal/core/domain"
+	portin "stori-challenge/internal/core/ports/in"
+	"stori-challenge/internal/core/ports/out"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const uploadPrefix = "input/"
+
+var accountIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+var _ portin.UploadUseCase = (*UploadService)(nil)
+
+type UploadService struct {
+	parser  out.TransactionParser
+	storage out.ObjectStorage
+	txRepo  out.TransactionRepo
+	bucket  string
+	newID   func() string
+}
+
+func NewUploadService(
+	parser out.TransactionParser,
+	storage out.ObjectStorage,
+	txRepo out.TransactionRepo,
+	bucket string,
+) *UploadService {
+	return &UploadService{
+		parser:  parser,
+		storage: storage,
+		txRepo:  txRepo,
+		bucket:  bucket,
+		newID:   uuid.NewString,
+	}
+}
+
+// UploadTransactions valida el CSV antes de escribirlo para que un archivo
+// malo nunca llegue al bucket ni dispare el procesamiento. La corrida pendiente
+// se crea antes de escribir el objeto para que el evento de S3 siempre la
+// encuentre; si la escritura falla, queda como fallida.
+func (s *UploadService) UploadTransactions(
+	ctx context.Context,
+	accountID string,
+	content []byte,
+) (domain.UploadResult, error) {
+	accountID = strings.TrimSpace(accountID)
+	if accountID != "" && !accountIDPattern.MatchString(accountID) {
+		return domain.UploadResult{}, fmt.Errorf("%w: cuenta %q no válida", domain.ErrInvalidUpload, accountID)
+	}
+
+	txs, err := s.parser.ParseTransactions(ctx, bytes.NewReader(content))
+	if err != nil {
+		return domain.UploadResult{}, fmt.Errorf("%w: %v", domain.ErrInvalidUpload, err)
+	}
+	if len(txs) == 0 {
+		return domain.UploadResult{}, fmt.Errorf("%w: el archivo no contiene transacciones", domain.ErrInvalidUpload)
+	}
+
+	key := s.objectKey(accountID)
+	run, err := s.txRepo.CreateProcessingRun(ctx, domain.ProcessingRun{
+		Bucket:    s.bucket,
+		ObjectKey: key,
+		Status:    domain.RunStatusPending,
+	})
+	if err != nil {
+		return domain.UploadResult{}, err
+	}
+
+	if err := s.storage.PutObject(ctx, s.bucket, key, content, "text/csv"); err != nil {
+		markRun(&run, err)
+		if finishErr := s.txRepo.FinishProcessingRun(ctx, run); finishErr != nil {
+			return domain.UploadResult{}, errors.Join(err, finishErr)
+		}
+		return domain.UploadResult{}, err
+	}
+
+	return domain.UploadResult{
+		Bucket:            s.bucket,
+		ObjectKey:         key,
+		RunID:             run.ID,
+		TransactionsCount: len(txs),
+	}, nil
+}
+
+// objectKey guarda las subidas sin cuenta en la cuenta por defecto, la misma
+// que usa la vista previa; si no, domain.AccountIDFromObjectKey tomaría
+// "input" como cuenta.
+func (s *UploadService) objectKey(accountID string) string {
+	if accountID == "" {
+		accountID = domain.DefaultAccountID
+	}
+	return uploadPrefix + accountID + "/" + s.newID() + ".csv"
+}
@@ -0,0 +1,178 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+type fakeParser struct {
+	txs []domain.Transaction
+	err error
+
+	gotContent string
+}
+
+func (f *fakeParser) ParseTransactions(_ context.Context, r io.Reader) ([]domain.Transaction, error) {
+	b, _ := io.ReadAll(r)
+	f.gotContent = string(b)
+	return f.txs, f.err
+}
+
+type fakeStorage struct {
+	err   error
+	onPut func()
+
+	called         bool
+	gotBucket      string
+	gotKey         string
+	gotBody        []byte
+	gotContentType string
+}
+
+func (f *fakeStorage) PutObject(_ context.Context, bucket, key string, body []byte, contentType string) error {
+	f.called = true
+	f.gotBucket = bucket
+	f.gotKey = key
+	f.gotBody = body
+	f.gotContentType = contentType
+	if f.onPut != nil {
+		f.onPut()
+	}
+	return f.err
+}
+
+func newTestUploadService(parser *fakeParser, storage *fakeStorage, repo *fakeTxRepo) *UploadService {
+	svc := NewUploadService(parser, storage, repo, "uploads-bucket")
+	svc.newID = func() string { return "fixed-id" }
+	return svc
+}
+
+func TestUploadService_UploadTransactions_HappyPath(t *testing.T) {
+	parser := &fakeParser{txs: []domain.Transaction{
+		{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: dFromInt(60)},
+		{Date: time.Date(2021, 7, 28, 0, 0, 0, 0, time.UTC), Amount: dFromInt(-10)},
+	}}
+	storage := &fakeStorage{}
+	repo := &fakeTxRepo{}
+	svc := newTestUploadService(parser, storage, repo)
+
+	content := []byte("Id,Date,Transaction\n0,7/15,+60\n1,7/28,-10\n")
+	got, err := svc.UploadTransactions(context.Background(), "acc-1", content)
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+
+	if got.Bucket != "uploads-bucket" || got.ObjectKey != "input/acc-1/fixed-id.csv" {
+		t.Errorf("resultado = %+v", got)
+	}
+	if got.RunID == 0 || got.TransactionsCount != 2 {
+		t.Errorf("RunID/TransactionsCount = %d/%d", got.RunID, got.TransactionsCount)
+	}
+	if storage.gotBucket != "uploads-bucket" || storage.gotKey != got.ObjectKey || string(storage.gotBody) != string(content) {
+		t.Errorf("PutObject llamado con %s/%s", storage.gotBucket, storage.gotKey)
+	}
+	if storage.gotContentType != "text/csv" {
+		t.Errorf("ContentType = %q", storage.gotContentType)
+	}
+	if len(repo.runs) != 1 || repo.runs[0].Status != domain.RunStatusPending || repo.runs[0].ObjectKey != got.ObjectKey {
+		t.Errorf("corridas = %+v, se esperaba una pendiente", repo.runs)
+	}
+}
+
+func TestUploadService_UploadTransactions_WithoutAccount(t *testing.T) {
+	parser := &fakeParser{txs: []domain.Transaction{{Amount: dFromInt(1)}}}
+	svc := newTestUploadService(parser, &fakeStorage{}, &fakeTxRepo{})
+
+	got, err := svc.UploadTransactions(context.Background(), "", []byte("x"))
+	if err != nil {
+		t.Fatalf("no se esperaba error, obtenido: %v", err)
+	}
+	if got.ObjectKey != "input/default/fixed-id.csv" {
+		t.Errorf("ObjectKey = %q", got.ObjectKey)
+	}
+	if acc := domain.AccountIDFromObjectKey(got.ObjectKey); acc != domain.DefaultAccountID {
+		t.Errorf("cuenta de %q = %q, se esperaba %q", got.ObjectKey, acc, domain.DefaultAccountID)
+	}
+}
+
+func TestUploadService_UploadTransactions_InvalidContent(t *testing.T) {
+	cases := map[string]*fakeParser{
+		"error de parseo":   {err: errors.New("fecha inválida")},
+		"sin transacciones": {},
+	}
+
+	for name, parser := range cases {
+		t.Run(name, func(t *testing.T) {
+			storage := &fakeStorage{}
+			repo := &fakeTxRepo{}
+			svc := newTestUploadService(parser, storage, repo)
+
+			_, err := svc.UploadTransactions(context.Background(), "", []byte("basura"))
+			if !errors.Is(err, domain.ErrInvalidUpload) {
+				t.Fatalf("se esperaba ErrInvalidUpload, obtenido %v", err)
+			}
+			if storage.called || len(repo.runs) != 0 {
+				t.Fatalf("no se esperaba escribir en S3 ni crear corrida con un archivo inválido")
+			}
+		})
+	}
+}
+
+func TestUploadService_UploadTransactions_InvalidAccount(t *testing.T) {
+	parser := &fakeParser{txs: []domain.Transaction{{Amount: dFromInt(1)}}}
+	svc := newTestUploadService(parser, &fakeStorage{}, &fakeTxRepo{})
+
+	_, err := svc.UploadTransactions(context.Background(), "../otra", []byte("x"))
+	if !errors.Is(err, domain.ErrInvalidUpload) || !strings.Contains(err.Error(), "cuenta") {
+		t.Fatalf("se esperaba ErrInvalidUpload por cuenta, obtenido %v", err)
+	}
+}
+
+func TestUploadService_UploadTransactions_StorageError(t *testing.T) {
+	storageErr := errors.New("falló S3")
+	parser := &fakeParser{txs: []domain.Transaction{{Amount: dFromInt(1)}}}
+	repo := &fakeTxRepo{}
+	svc := newTestUploadService(parser, &fakeStorage{err: storageErr}, repo)
+
+	_, err := svc.UploadTransactions(context.Background(), "", []byte("x"))
+	if !errors.Is(err, storageErr) {
+		t.Fatalf("se esperaba %v, obtenido %v", storageErr, err)
+	}
+	if len(repo.runs) != 1 {
+		t.Fatalf("se esperaba 1 corrida creada, obtenidas %d", len(repo.runs))
+	}
+	if repo.finishedRun == nil || repo.finishedRun.ID != repo.runs[0].ID {
+		t.Fatalf("la corrida pendiente debe cerrarse, obtenido %+v", repo.finishedRun)
+	}
+	if repo.finishedRun.Status != domain.RunStatusFailed || repo.finishedRun.Error != storageErr.Error() {
+		t.Errorf("corrida = %+v, se esperaba failed con el error de S3", *repo.finishedRun)
+	}
+}
+
+func TestUploadService_UploadTransactions_CreatesRunBeforeObject(t *testing.T) {
+	parser := &fakeParser{txs: []domain.Transaction{{Amount: dFromInt(1)}}}
+	repo := &fakeTxRepo{}
+	storage := &fakeStorage{}
+	storage.onPut = func() {
+		if len(repo.runs) != 1 || repo.runs[0].Status != domain.RunStatusPending {
+			t.Errorf("la corrida pendiente debe existir antes de escribir el objeto, corridas = %+v", repo.runs)
+		}
+	}
+	svc := newTestUploadService(parser, storage, repo)
+
+	if _, err := svc.UploadTransactions(context.Background(), "acc-1", []byte("x")); err != nil {
+		t.Fatalf("error inesperado: %v", err)
+	}
+	if !storage.called {
+		t.Fatalf("PutObject no fue llamado")
+	}
+	if repo.finishedRun != nil {
+		t.Errorf("la corrida debe seguir pendiente, obtenido %+v", *repo.finishedRun)
+	}
+}
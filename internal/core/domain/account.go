@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"path"
+	"strings"
+)
+
+const DefaultAccountID = "default"
+
+// AccountIDFromObjectKey deriva la cuenta a partir del último directorio de la
+// key (p. ej. "input/acc-123/txns.csv" → "acc-123"). Si la key no tiene
+// directorio se usa DefaultAccountID. Los prefijos processed/ y quarantine/ no
+// cuentan, así la cuenta no cambia al archivar el objeto. En una entrada de zip
+// manda el directorio de la entrada y, si no tiene, el del zip.
+func AccountIDFromObjectKey(key string) string {
+	if archive, entry, ok := splitFileKey(key); ok {
+		if dir := path.Dir(entry); dir != "." && dir != "/" {
+			return path.Base(dir)
+		}
+		key = archive
+	}
+	dir := path.Dir(trimSettledPrefix(strings.TrimPrefix(key, "/")))
+	if dir == "." || dir == "/" || dir == "" {
+		return DefaultAccountID
+	}
+	return path.Base(dir)
+}
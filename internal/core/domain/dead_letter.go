@@ -0,0 +1,17 @@
+package domain
+
+import "time"
+
+// DeadLetter guarda un mensaje que se dejó de reintentar, con lo necesario
+// para reprocesarlo a mano (storictl process / replay).
+type DeadLetter struct {
+	ID           uint64
+	Source       string
+	MessageID    string
+	Bucket       string
+	ObjectKey    string
+	Payload      string
+	Error        string
+	ReceiveCount int
+	CreatedAt    time.Time
+}
@@ -0,0 +1,13 @@
+package domain
+
+// Dialect describe cómo venía escrito un CSV: la codificación original, si
+// traía BOM y el separador de columnas. Los demás formatos lo dejan vacío.
+type Dialect struct {
+	Encoding  string
+	BOM       bool
+	Delimiter rune
+}
+
+func (d Dialect) IsZero() bool {
+	return d.Encoding == ""
+}
@@ -0,0 +1,11 @@
+package domain
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("recurso no encontrado")
+	ErrAlreadyExists = errors.New("el recurso ya existe")
+	ErrInvalidCursor = errors.New("cursor inválido")
+	ErrInvalidQuery  = errors.New("consulta inválida")
+	ErrInvalidUpload = errors.New("archivo inválido")
+)
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type ForecastMethod string
+
+const (
+	ForecastMovingAverage ForecastMethod = "moving_average"
+	ForecastLinearTrend   ForecastMethod = "linear_trend"
+)
+
+const UncategorizedCategory = "uncategorized"
+
+type CategoryForecast struct {
+	Category       string
+	SpentToDate    decimal.Decimal
+	ProjectedSpend decimal.Decimal
+}
+
+// Forecast proyecta el gasto (débitos en valor absoluto) y el balance al cierre
+// del mes en curso a partir de AsOf.
+type Forecast struct {
+	Month            string
+	AsOf             time.Time
+	Method           ForecastMethod
+	SpentToDate      decimal.Decimal
+	ProjectedSpend   decimal.Decimal
+	ProjectedBalance decimal.Decimal
+	ByCategory       []CategoryForecast
+}
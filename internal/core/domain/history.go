@@ -0,0 +1,36 @@
+package domain
+
+import "time"
+
+type TransactionRecord struct {
+	Transaction
+
+	ID        uint64
+	AccountID string
+	Bucket    string
+	ObjectKey string
+	CreatedAt time.Time
+}
+
+// TransactionQuery filtra por cuenta y rango [From, To); fechas en cero no
+// acotan. Cursor es opaco y viene de TransactionPage.NextCursor.
+type TransactionQuery struct {
+	AccountID string
+	From      time.Time
+	To        time.Time
+	Cursor    string
+	Limit     int
+}
+
+type TransactionPage struct {
+	Items      []TransactionRecord
+	NextCursor string
+}
+
+type StoredSummary struct {
+	ID        uint64
+	Bucket    string
+	ObjectKey string
+	CreatedAt time.Time
+	Summary   AccountSummary
+}
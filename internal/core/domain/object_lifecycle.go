@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Prefijos a los que se mueven los objetos una vez procesados. Los eventos de
+// estos prefijos se ignoran para no reprocesar lo que mueve el propio pipeline.
+const (
+	ProcessedPrefix  = "processed/"
+	QuarantinePrefix = "quarantine/"
+)
+
+// Tags que se aplican al objeto archivado o en cuarentena.
+const (
+	ObjectTagRunID  = "stori-run-id"
+	ObjectTagStatus = "stori-status"
+)
+
+func IsSettledKey(key string) bool {
+	return strings.HasPrefix(key, ProcessedPrefix) || strings.HasPrefix(key, QuarantinePrefix)
+}
+
+// SettledKey devuelve la key bajo prefix, quitando antes el prefijo de ciclo
+// de vida que ya tuviera (un replay de processed/ no anida prefijos).
+func SettledKey(prefix, key string) string {
+	return prefix + trimSettledPrefix(key)
+}
+
+func trimSettledPrefix(key string) string {
+	key = strings.TrimPrefix(key, ProcessedPrefix)
+	return strings.TrimPrefix(key, QuarantinePrefix)
+}
+
+// ErrorReportKey es la key del reporte que acompaña a un objeto en cuarentena.
+func ErrorReportKey(quarantinedKey string) string {
+	return quarantinedKey + ".error.json"
+}
+
+// RunErrorReportKey es la key alternativa del reporte cuando ya hay uno de un
+// fallo anterior del mismo objeto, que no se pisa.
+func RunErrorReportKey(quarantinedKey string, runID uint64) string {
+	return fmt.Sprintf("%s.%d.error.json", quarantinedKey, runID)
+}
+
+type pendingRetryKey struct{}
+
+// WithPendingRetry marca que quien invoca volverá a intentar el objeto si
+// falla (SQS antes de llegar al máximo de recepciones, reintentos de Lambda):
+// un error transitorio no debe mandarlo a cuarentena.
+func WithPendingRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, pendingRetryKey{}, true)
+}
+
+func HasPendingRetry(ctx context.Context) bool {
+	pending, _ := ctx.Value(pendingRetryKey{}).(bool)
+	return pending
+}
+
+// ErrorReport se guarda junto al objeto en cuarentena.
+type ErrorReport struct {
+	RunID     uint64
+	Bucket    string
+	Key       string
+	VersionID string
+	Error     string
+	FailedAt  time.Time
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "strings"
+
+// entrySeparator separa la key de un zip de la entrada dentro de él en la key
+// lógica ("input/export.zip#acc-1/txns.csv").
+const entrySeparator = "#"
+
+// ObjectRef identifica un objeto de S3. VersionID vacío significa la versión
+// actual del objeto; Entry, si viene, es un archivo dentro de un zip.
+type ObjectRef struct {
+	Bucket    string
+	Key       string
+	VersionID string
+	Entry     string
+}
+
+// WithEntry devuelve la referencia a una entrada del zip.
+func (o ObjectRef) WithEntry(entry string) ObjectRef {
+	o.Entry = entry
+	return o
+}
+
+// FileKey es la key con la que se guardan transacciones, resúmenes y
+// corridas: la del objeto o, para una entrada de zip, "<key>#<entrada>". Sin
+// prefijo de ciclo de vida, así un replay de processed/ o quarantine/ reemplaza
+// lo guardado con la key original en vez de duplicarlo.
+func (o ObjectRef) FileKey() string {
+	key := trimSettledPrefix(o.Key)
+	if o.Entry == "" {
+		return key
+	}
+	return key + entrySeparator + o.Entry
+}
+
+func (o ObjectRef) String() string {
+	s := "s3://" + o.Bucket + "/" + o.Key
+	if o.VersionID != "" {
+		s += "?versionId=" + o.VersionID
+	}
+	if o.Entry != "" {
+		s += entrySeparator + o.Entry
+	}
+	return s
+}
+
+// splitFileKey separa una key lógica de zip en archivo y entrada. Solo se
+// reconoce el separador tras ".zip" para no confundir keys que contienen "#".
+func splitFileKey(key string) (archive, entry string, ok bool) {
+	i := strings.Index(strings.ToLower(key), ".zip"+entrySeparator)
+	if i < 0 {
+		return key, "", false
+	}
+	cut := i + len(".zip")
+	return key[:cut], key[cut+len(entrySeparator):], true
+}
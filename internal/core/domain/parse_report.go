@@ -0,0 +1,22 @@
+package domain
+
+type RowIssue struct {
+	Line    int
+	Message string
+}
+
+// ParseReport resume la validación fila por fila de un archivo; a diferencia
+// del parseo normal no se detiene en el primer error.
+type ParseReport struct {
+	HeaderValid bool
+	Dialect     Dialect
+	Columns     []string
+	TotalRows   int
+	ValidRows   int
+	SkippedRows int
+	Issues      []RowIssue
+}
+
+func (r ParseReport) OK() bool {
+	return r.HeaderValid && len(r.Issues) == 0
+}
@@ -0,0 +1,14 @@
+package domain
+
+type RenderedEmail struct {
+	Subject string
+	HTML    string
+	Plain   string
+}
+
+// SummaryPreview es el resumen calculado sobre un archivo sin persistirlo,
+// junto con el correo tal como se enviaría.
+type SummaryPreview struct {
+	Summary AccountSummary
+	Email   RenderedEmail
+}
@@ -0,0 +1,37 @@
+package domain
+
+import "time"
+
+type RunStatus string
+
+const (
+	RunStatusPending    RunStatus = "pending"
+	RunStatusProcessing RunStatus = "processing"
+	RunStatusSucceeded  RunStatus = "succeeded"
+	RunStatusFailed     RunStatus = "failed"
+)
+
+// ProcessingRun guarda en Dialect cómo se leyó el CSV del objeto; queda vacío
+// para otros formatos y para la corrida que agrupa las entradas de un zip.
+type ProcessingRun struct {
+	ID                uint64
+	AccountID         string
+	Bucket            string
+	ObjectKey         string
+	VersionID         string
+	Status            RunStatus
+	Error             string
+	TransactionsCount int
+	Dialect           Dialect
+	CreatedAt         time.Time
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+}
+
+type RunQuery struct {
+	AccountID string
+	Bucket    string
+	ObjectKey string
+	Status    RunStatus
+	Limit     int
+}
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+type ObjectInfo struct {
+	Bucket       string
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ReplayEmailMode decide si un replay vuelve a enviar los correos de resumen.
+type ReplayEmailMode string
+
+const (
+	ReplayEmailSuppress ReplayEmailMode = "suppress"
+	ReplayEmailForce    ReplayEmailMode = "force"
+)
+
+type ReplayRequest struct {
+	// ReplayID identifica los checkpoints; repetirlo reanuda un replay previo.
+	ReplayID string
+	Bucket   string
+	Prefix   string
+	// Glob se compara con la key completa, o solo con el nombre del archivo si
+	// no contiene "/".
+	Glob string
+	// Since es inclusivo y Until exclusivo, sobre LastModified.
+	Since       time.Time
+	Until       time.Time
+	Concurrency int
+	Email       ReplayEmailMode
+	DryRun      bool
+}
+
+type ReplayCheckpoint struct {
+	ReplayID  string
+	Bucket    string
+	ObjectKey string
+	Status    RunStatus
+	Error     string
+	UpdatedAt time.Time
+}
+
+type ReplayFailure struct {
+	ObjectKey string
+	Error     string
+}
+
+type ReplayResult struct {
+	ReplayID  string
+	Listed    int
+	Matched   int
+	Skipped   int
+	Succeeded int
+	Failed    int
+	Failures  []ReplayFailure
+}
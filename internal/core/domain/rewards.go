@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// RewardRules define cómo se acumulan puntos y cashback sobre los créditos.
+// Un cap en cero significa "sin límite" para el ciclo.
+type RewardRules struct {
+	BaseRate            decimal.Decimal
+	CashbackRate        decimal.Decimal
+	CategoryMultipliers map[string]decimal.Decimal
+	PointsCapPerCycle   decimal.Decimal
+	CashbackCapPerCycle decimal.Decimal
+}
+
+func (r RewardRules) Multiplier(category string) decimal.Decimal {
+	if m, ok := r.CategoryMultipliers[strings.ToLower(strings.TrimSpace(category))]; ok {
+		return m
+	}
+	return decimal.NewFromInt(1)
+}
+
+type RewardEntry struct {
+	AccountID string
+	Cycle     string
+	Date      time.Time
+	Category  string
+	Amount    decimal.Decimal
+	Points    decimal.Decimal
+	Cashback  decimal.Decimal
+}
+
+type RewardsBalance struct {
+	Points   decimal.Decimal
+	Cashback decimal.Decimal
+}
+
+func (b RewardsBalance) Add(other RewardsBalance) RewardsBalance {
+	return RewardsBalance{
+		Points:   b.Points.Add(other.Points),
+		Cashback: b.Cashback.Add(other.Cashback),
+	}
+}
+
+type RewardsSummary struct {
+	PointsEarned     decimal.Decimal
+	CashbackEarned   decimal.Decimal
+	LifetimePoints   decimal.Decimal
+	LifetimeCashback decimal.Decimal
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "time"
+
+type StatementStatus string
+
+const (
+	StatementStatusSending StatementStatus = "sending"
+	StatementStatusSent    StatementStatus = "sent"
+	StatementStatusFailed  StatementStatus = "failed"
+)
+
+// Statement es la entrada del ledger de estados de cuenta mensuales: una por
+// cuenta y periodo ("2006-01").
+type Statement struct {
+	ID                uint64
+	AccountID         string
+	Period            string
+	Status            StatementStatus
+	Error             string
+	TransactionsCount int
+	CreatedAt         time.Time
+	SentAt            *time.Time
+}
+
+type StatementFailure struct {
+	AccountID string
+	Error     string
+}
+
+type StatementRunResult struct {
+	Period   string
+	Accounts int
+	Sent     int
+	Skipped  int
+	Failed   int
+	Failures []StatementFailure
+}
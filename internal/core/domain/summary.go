@@ -7,9 +7,55 @@ type MonthlySummary struct {
 	TransactionsCount   int
 	AverageDebitAmount  decimal.Decimal
 	AverageCreditAmount decimal.Decimal
+	TotalDebitAmount    decimal.Decimal
+	TotalCreditAmount   decimal.Decimal
+
+	VsPreviousMonth *MonthlyDelta
+	VsPreviousYear  *MonthlyDelta
+}
+
+// MonthlyDelta es la diferencia (mes actual - BaselineMonth) de cada cifra.
+type MonthlyDelta struct {
+	BaselineMonth       string
+	TransactionsCount   int
+	AverageDebitAmount  decimal.Decimal
+	AverageCreditAmount decimal.Decimal
+	TotalDebitAmount    decimal.Decimal
+	TotalCreditAmount   decimal.Decimal
+}
+
+func (m MonthlySummary) DeltaFrom(baseline MonthlySummary) *MonthlyDelta {
+	return &MonthlyDelta{
+		BaselineMonth:       baseline.MonthName,
+		TransactionsCount:   m.TransactionsCount - baseline.TransactionsCount,
+		AverageDebitAmount:  m.AverageDebitAmount.Sub(baseline.AverageDebitAmount),
+		AverageCreditAmount: m.AverageCreditAmount.Sub(baseline.AverageCreditAmount),
+		TotalDebitAmount:    m.TotalDebitAmount.Sub(baseline.TotalDebitAmount),
+		TotalCreditAmount:   m.TotalCreditAmount.Sub(baseline.TotalCreditAmount),
+	}
 }
 
 type AccountSummary struct {
+	AccountID    string
 	TotalBalance decimal.Decimal
-	ByMonth      []MonthlySummary
+	// OpeningBalance y ClosingBalance son los saldos que declara el extracto;
+	// nil cuando el archivo no los trae.
+	OpeningBalance *decimal.Decimal
+	ClosingBalance *decimal.Decimal
+	ByMonth        []MonthlySummary
+	Rewards        *RewardsSummary
+	Forecast       *Forecast
+}
+
+// CurrentBalance es el saldo al cierre del archivo: el final declarado, el
+// inicial más los movimientos o, sin saldos, solo los movimientos.
+func (s AccountSummary) CurrentBalance() decimal.Decimal {
+	switch {
+	case s.ClosingBalance != nil:
+		return *s.ClosingBalance
+	case s.OpeningBalance != nil:
+		return s.OpeningBalance.Add(s.TotalBalance)
+	default:
+		return s.TotalBalance
+	}
 }
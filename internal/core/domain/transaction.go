@@ -7,6 +7,28 @@ import (
 )
 
 type Transaction struct {
-	Date   time.Time
-	Amount decimal.Decimal
+	Date     time.Time
+	Amount   decimal.Decimal
+	Category string
+	// Reference, Description y Memo vienen de extractos OFX (FITID, NAME y
+	// MEMO); el CSV no los trae y quedan vacíos.
+	Reference   string
+	Description string
+	Memo        string
+	// Currency es el código ISO 4217 cuando el extracto lo declara (camt.053).
+	Currency string
+}
+
+// TransactionFile es un archivo lógico de un objeto: el objeto completo
+// (Entry vacío) o una entrada de un zip. Err guarda el error de parseo de ese
+// archivo, que no impide leer los demás. Los saldos solo vienen en formatos
+// que los declaran (camt.053); si no, quedan en nil. Dialect solo se llena
+// para CSV.
+type TransactionFile struct {
+	Entry          string
+	Transactions   []Transaction
+	OpeningBalance *decimal.Decimal
+	ClosingBalance *decimal.Decimal
+	Dialect        Dialect
+	Err            error
 }
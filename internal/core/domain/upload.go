@@ -0,0 +1,8 @@
+package domain
+
+type UploadResult struct {
+	Bucket            string
+	ObjectKey         string
+	RunID             uint64
+	TransactionsCount int
+}
@@ -0,0 +1,10 @@
+package in
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type DeadLetterUseCase interface {
+	RecordDeadLetter(ctx context.Context, dl domain.DeadLetter) (domain.DeadLetter, error)
+}
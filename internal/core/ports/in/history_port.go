@@ -0,0 +1,29 @@
+package in
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+	"time"
+)
+
+type SummaryQueryUseCase interface {
+	GetSummary(ctx context.Context, id uint64) (domain.StoredSummary, error)
+	GetLatestSummaryByObject(ctx context.Context, bucket, key string) (domain.StoredSummary, error)
+	GetLatestSummaryByAccount(ctx context.Context, accountID string) (domain.StoredSummary, error)
+}
+
+type TransactionQueryUseCase interface {
+	ListTransactions(ctx context.Context, q domain.TransactionQuery) (domain.TransactionPage, error)
+	AggregateByMonth(ctx context.Context, accountID string, from, to time.Time) ([]domain.MonthlySummary, error)
+}
+
+type RunQueryUseCase interface {
+	GetProcessingRun(ctx context.Context, id uint64) (domain.ProcessingRun, error)
+	ListProcessingRuns(ctx context.Context, q domain.RunQuery) ([]domain.ProcessingRun, error)
+}
+
+type HistoryQueryUseCase interface {
+	SummaryQueryUseCase
+	TransactionQueryUseCase
+	RunQueryUseCase
+}
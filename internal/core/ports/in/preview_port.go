@@ -0,0 +1,10 @@
+package in
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type SummaryPreviewUseCase interface {
+	PreviewSummary(ctx context.Context, accountID string, content []byte) (domain.SummaryPreview, error)
+}
@@ -0,0 +1,10 @@
+package in
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type ReplayUseCase interface {
+	Replay(ctx context.Context, req domain.ReplayRequest) (domain.ReplayResult, error)
+}
@@ -0,0 +1,12 @@
+package in
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type StatementUseCase interface {
+	// SendMonthlyStatements envía el estado de cuenta del periodo ("2006-01")
+	// a cada cuenta con movimientos en él.
+	SendMonthlyStatements(ctx context.Context, period string) (domain.StatementRunResult, error)
+}
@@ -2,8 +2,9 @@ package in
 
 import (
 	"context"
+	"stori-challenge/internal/core/domain"
 )
 
 type SummaryUseCase interface {
-	ProcessTransactionsFromObject(ctx context.Context, bucket, key string) error
+	ProcessTransactionsFromObject(ctx context.Context, obj domain.ObjectRef) error
 }
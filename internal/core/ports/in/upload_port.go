@@ -0,0 +1,10 @@
+package in
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type UploadUseCase interface {
+	UploadTransactions(ctx context.Context, accountID string, content []byte) (domain.UploadResult, error)
+}
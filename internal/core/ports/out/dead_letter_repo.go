@@ -0,0 +1,10 @@
+package out
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type DeadLetterRepo interface {
+	SaveDeadLetter(ctx context.Context, dl domain.DeadLetter) (domain.DeadLetter, error)
+}
@@ -0,0 +1,18 @@
+package out
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type ObjectLifecycle interface {
+	// ObjectExists indica si la versión leída sigue en el bucket: si otro
+	// intento ya la movió o la borraron no hay nada que archivar.
+	ObjectExists(ctx context.Context, obj domain.ObjectRef) (bool, error)
+	// MoveObject copia src a dstKey (mismo bucket) con los tags indicados y
+	// borra src. Si dstKey es la key de src solo reemplaza los tags.
+	MoveObject(ctx context.Context, src domain.ObjectRef, dstKey string, tags map[string]string) error
+	// PutErrorReport no pisa un reporte existente: en ese caso devuelve
+	// domain.ErrAlreadyExists.
+	PutErrorReport(ctx context.Context, bucket, key string, report domain.ErrorReport, tags map[string]string) error
+}
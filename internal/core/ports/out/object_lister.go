@@ -0,0 +1,10 @@
+package out
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type ObjectLister interface {
+	ListObjects(ctx context.Context, bucket, prefix string) ([]domain.ObjectInfo, error)
+}
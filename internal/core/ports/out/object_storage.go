@@ -0,0 +1,7 @@
+package out
+
+import "context"
+
+type ObjectStorage interface {
+	PutObject(ctx context.Context, bucket, key string, body []byte, contentType string) error
+}
@@ -0,0 +1,12 @@
+package out
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type ReplayCheckpointStore interface {
+	// CompletedReplayObjects devuelve las keys ya procesadas con éxito en el replay.
+	CompletedReplayObjects(ctx context.Context, replayID, bucket string) (map[string]bool, error)
+	SaveReplayCheckpoint(ctx context.Context, cp domain.ReplayCheckpoint) error
+}
@@ -0,0 +1,12 @@
+package out
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type RewardsLedger interface {
+	SaveRewardEntries(ctx context.Context, bucket, key string, entries []domain.RewardEntry) error
+	GetCycleBalances(ctx context.Context, accountID string, cycles []string) (map[string]domain.RewardsBalance, error)
+	GetLifetimeBalance(ctx context.Context, accountID string) (domain.RewardsBalance, error)
+}
@@ -0,0 +1,14 @@
+package out
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type StatementLedger interface {
+	// ClaimStatement reserva el envío de la cuenta en el periodo. Devuelve
+	// false si ya se envió o hay otro envío en curso; un envío fallido se
+	// puede volver a reclamar.
+	ClaimStatement(ctx context.Context, accountID, period string) (domain.Statement, bool, error)
+	FinishStatement(ctx context.Context, st domain.Statement) error
+}
@@ -0,0 +1,10 @@
+package out
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+)
+
+type SummaryRenderer interface {
+	RenderSummary(ctx context.Context, summary domain.AccountSummary) (domain.RenderedEmail, error)
+}
@@ -6,6 +6,9 @@ import (
 )
 
 type TransactionFileReader interface {
-	ReadTransactionsFromObject(ctx context.Context, bucket, key string) ([]domain.Transaction, error)
-	ReadTransactionsFromObjectParallel(ctx context.Context, bucket, key string) ([]domain.Transaction, error)
+	ReadTransactionsFromObject(ctx context.Context, obj domain.ObjectRef) ([]domain.Transaction, error)
+	ReadTransactionsFromObjectParallel(ctx context.Context, obj domain.ObjectRef) ([]domain.Transaction, error)
+	// ReadObjectFiles devuelve un archivo por CSV del objeto: uno solo para un
+	// CSV (comprimido o no) y uno por entrada para un zip.
+	ReadObjectFiles(ctx context.Context, obj domain.ObjectRef) ([]domain.TransactionFile, error)
 }
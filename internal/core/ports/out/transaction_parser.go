@@ -0,0 +1,11 @@
+package out
+
+import (
+	"context"
+	"io"
+	"stori-challenge/internal/core/domain"
+)
+
+type TransactionParser interface {
+	ParseTransactions(ctx context.Context, r io.Reader) ([]domain.Transaction, error)
+}
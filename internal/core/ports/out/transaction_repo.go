@@ -3,10 +3,32 @@ package out
 import (
 	"context"
 	"stori-challenge/internal/core/domain"
+	"time"
 )
 
 type TransactionRepo interface {
+	// DeleteObjectData borra lo que guardó un procesamiento anterior del
+	// archivo (transacciones, recompensas y resúmenes) para que reprocesarlo
+	// no duplique filas.
+	DeleteObjectData(ctx context.Context, bucket, key string) error
 	SaveTransactions(ctx context.Context, bucket, key string, txs []domain.Transaction) error
 
 	SaveSummary(ctx context.Context, bucket, key string, summary domain.AccountSummary) error
+
+	ListTransactionsByAccount(ctx context.Context, accountID string, from, to time.Time) ([]domain.Transaction, error)
+	ListAccountsWithTransactions(ctx context.Context, from, to time.Time) ([]string, error)
+
+	FindLatestMonthlySummaries(ctx context.Context, accountID string, months []string) (map[string]domain.MonthlySummary, error)
+
+	ListTransactions(ctx context.Context, q domain.TransactionQuery) (domain.TransactionPage, error)
+	GetSummaryByID(ctx context.Context, id uint64) (domain.StoredSummary, error)
+	GetLatestSummaryByObject(ctx context.Context, bucket, key string) (domain.StoredSummary, error)
+	GetLatestSummaryByAccount(ctx context.Context, accountID string) (domain.StoredSummary, error)
+	AggregateByMonth(ctx context.Context, accountID string, from, to time.Time) ([]domain.MonthlySummary, error)
+
+	CreateProcessingRun(ctx context.Context, run domain.ProcessingRun) (domain.ProcessingRun, error)
+	StartProcessingRun(ctx context.Context, obj domain.ObjectRef) (domain.ProcessingRun, error)
+	FinishProcessingRun(ctx context.Context, run domain.ProcessingRun) error
+	GetProcessingRun(ctx context.Context, id uint64) (domain.ProcessingRun, error)
+	ListProcessingRuns(ctx context.Context, q domain.RunQuery) ([]domain.ProcessingRun, error)
 }
@@ -2,80 +2,267 @@ package bootstrap
 
 import (
 	"context"
+	"fmt"
+	"stori-challenge/internal/core/domain"
 	"stori-challenge/internal/core/ports/in"
 	"stori-challenge/internal/core/ports/out"
 	"stori-challenge/internal/infra/database"
 	"stori-challenge/internal/interfaces/out/rds"
+	"stori-challenge/internal/interfaces/out/s3storage"
 
 	"stori-challenge/internal/core/application"
 	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/interfaces/out/amountparse"
 	"stori-challenge/internal/interfaces/out/csvreader"
 	"stori-challenge/internal/interfaces/out/email"
+	"stori-challenge/internal/interfaces/out/fieldmap"
 	"stori-challenge/internal/interfaces/out/rds/models"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awscfg "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"gorm.io/gorm"
 )
 
 type AppContext struct {
 	SummaryUseCase in.SummaryUseCase
+	HistoryUseCase in.HistoryQueryUseCase
+	UploadUseCase  in.UploadUseCase
+	PreviewUseCase in.SummaryPreviewUseCase
+	ReplayUseCase  in.ReplayUseCase
+
+	DeadLetterUseCase in.DeadLetterUseCase
+	StatementUseCase  in.StatementUseCase
+}
+
+type AppOption func(*appOptions)
+
+type appOptions struct {
+	fileReader  out.TransactionFileReader
+	emailSender out.EmailSender
+}
+
+// WithFileReader reemplaza el lector S3 (por ejemplo, archivos locales en la CLI).
+func WithFileReader(r out.TransactionFileReader) AppOption {
+	return func(o *appOptions) { o.fileReader = r }
 }
 
-func InitializeApp(cfg *config.Config) (*AppContext, error) {
+func WithEmailSender(e out.EmailSender) AppOption {
+	return func(o *appOptions) { o.emailSender = e }
+}
+
+func InitializeApp(cfg *config.Config, appOpts ...AppOption) (*AppContext, error) {
 	ctx := context.Background()
 
-	awsCfg, err := awscfg.LoadDefaultConfig(ctx,
-		awscfg.WithRegion(cfg.S3Region),
-	)
-	if err != nil {
-		return nil, err
+	var o appOptions
+	for _, opt := range appOpts {
+		opt(&o)
 	}
 
-	if cfg.AWSEndpointURL != "" {
-		awsCfg.EndpointResolverWithOptions = aws.EndpointResolverWithOptionsFunc(
-			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
-				return aws.Endpoint{
-					URL:               cfg.AWSEndpointURL,
-					HostnameImmutable: true,
-				}, nil
-			},
-		)
+	awsCfg, err := LoadAWSConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
 	}
 
-	s3Client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
-		o.UsePathStyle = cfg.UsePathStyle
-	})
-
-	var emailSender out.EmailSender
+	s3Client := NewS3Client(awsCfg, cfg)
 
-	if cfg.AWSEndpointURL != "" {
-		emailSender = email.NewNoopEmailSender(cfg)
-	} else {
-		sesClient := sesv2.NewFromConfig(awsCfg)
-		emailSender = email.NewSESEmailSender(sesClient, cfg)
+	emailSender := o.emailSender
+	if emailSender == nil {
+		emailSender = NewEmailSender(awsCfg, cfg)
 	}
 
-	db, err := database.NewPostgresDB(cfg)
+	db, err := openDB(cfg)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := db.AutoMigrate(&models.Transaction{}, &models.AccountSummary{}); err != nil {
+	readerOpts, err := FileReaderOptions(cfg)
+	if err != nil {
 		return nil, err
 	}
-
-	txReader := csvreader.NewS3CSVReader(s3Client)
+	txReader := newFileReader(cfg, s3Client, readerOpts)
+	if o.fileReader != nil {
+		txReader = o.fileReader
+	}
 	txRepo := rds.NewTransactionRepo(db)
 
+	var opts []application.SummaryServiceOption
+	if cfg.RewardsEnabled {
+		rules, err := rewardRulesFromConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, application.WithRewards(
+			application.NewRewardsEngine(rules),
+			rds.NewRewardsLedgerRepo(db),
+		))
+	}
+
+	if cfg.ForecastEnabled {
+		method := domain.ForecastMethod(cfg.ForecastMethod)
+		if method != domain.ForecastMovingAverage && method != domain.ForecastLinearTrend {
+			return nil, fmt.Errorf("FORECAST_METHOD inválido: %q", cfg.ForecastMethod)
+		}
+		opts = append(opts, application.WithForecaster(
+			application.NewForecaster(method, cfg.ForecastWindowMonths),
+		))
+	}
+
+	if cfg.ObjectLifecycleEnabled {
+		opts = append(opts, application.WithObjectLifecycle(newObjectLifecycle(cfg, s3Client)))
+	}
+
 	summaryService := application.NewSummaryService(
 		txReader,
 		emailSender,
 		txRepo,
+		opts...,
+	)
+	// El replay suprime correos por defecto: solo se registran en el log.
+	quietSummaryService := application.NewSummaryService(
+		txReader,
+		email.NewNoopEmailSender(cfg),
+		txRepo,
+		opts...,
 	)
 
 	return &AppContext{
 		SummaryUseCase: summaryService,
+		HistoryUseCase: application.NewHistoryService(txRepo),
+		UploadUseCase: application.NewUploadService(
+			csvreader.NewCSVParser(readerOpts...),
+			newObjectStorage(cfg, s3Client),
+			txRepo,
+			cfg.S3BucketName,
+		),
+		PreviewUseCase: application.NewPreviewService(
+			csvreader.NewCSVParser(readerOpts...),
+			email.NewSummaryRenderer(cfg.StoriLogoURL),
+		),
+		ReplayUseCase: application.NewReplayService(
+			s3storage.NewS3ObjectLister(s3Client),
+			rds.NewReplayCheckpointRepo(db),
+			summaryService,
+			quietSummaryService,
+		),
+		DeadLetterUseCase: application.NewDeadLetterService(rds.NewDeadLetterRepo(db)),
+		StatementUseCase: application.NewStatementService(
+			txRepo,
+			rds.NewStatementLedgerRepo(db),
+			emailSender,
+		),
 	}, nil
 }
+
+// newFileReader elige el origen de los objetos según FILE_READER.
+func newFileReader(cfg *config.Config, s3Client *s3.Client, opts []csvreader.ReaderOption) out.TransactionFileReader {
+	if cfg.FileReader == "fs" {
+		return csvreader.NewFSCSVReader(cfg.FileReaderRoot, opts...)
+	}
+	return csvreader.NewS3CSVReader(s3Client, opts...)
+}
+
+// newObjectStorage guarda las subidas donde las lee newFileReader.
+func newObjectStorage(cfg *config.Config, s3Client *s3.Client) out.ObjectStorage {
+	if cfg.FileReader == "fs" {
+		return s3storage.NewFSObjectStorage(cfg.FileReaderRoot)
+	}
+	return s3storage.NewS3ObjectStorage(s3Client)
+}
+
+func newObjectLifecycle(cfg *config.Config, s3Client *s3.Client) out.ObjectLifecycle {
+	if cfg.FileReader == "fs" {
+		return s3storage.NewFSObjectLifecycle(cfg.FileReaderRoot)
+	}
+	return s3storage.NewS3ObjectLifecycle(s3Client)
+}
+
+// FileReaderOptions arma las opciones de los lectores con el mapeo de columnas
+// de FILE_FIELD_MAP y el formato de montos de AMOUNT_FORMAT. Con
+// S3_RANGE_PART_SIZE_MB en 0 los CSV grandes se leen en un solo stream.
+func FileReaderOptions(cfg *config.Config) ([]csvreader.ReaderOption, error) {
+	mapping, err := fieldmap.Parse(cfg.FileFieldMap)
+	if err != nil {
+		return nil, fmt.Errorf("FILE_FIELD_MAP: %w", err)
+	}
+	amounts, err := amountparse.ParseRules(cfg.AmountFormat)
+	if err != nil {
+		return nil, fmt.Errorf("AMOUNT_FORMAT: %w", err)
+	}
+	if cfg.S3RangePartSizeMB < 0 {
+		return nil, fmt.Errorf("S3_RANGE_PART_SIZE_MB inválido: %d", cfg.S3RangePartSizeMB)
+	}
+	opts := []csvreader.ReaderOption{
+		csvreader.WithFieldMapping(mapping),
+		csvreader.WithAmountRules(amounts),
+	}
+	if cfg.S3RangePartSizeMB > 0 {
+		opts = append(opts, csvreader.WithRangedReads(int64(cfg.S3RangePartSizeMB)<<20, cfg.S3RangeConcurrency))
+	}
+	return opts, nil
+}
+
+// openDB elige el motor según DB_DRIVER. En SQLite el esquema lo crea
+// database.NewSQLiteDB; en Postgres se mantiene AutoMigrate.
+func openDB(cfg *config.Config) (*gorm.DB, error) {
+	if cfg.DBDriver == "sqlite" {
+		return database.NewSQLiteDB(cfg)
+	}
+
+	db, err := database.NewPostgresDB(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.AutoMigrate(
+		&models.Transaction{},
+		&models.AccountSummary{},
+		&models.MonthlySummary{},
+		&models.MonthlyComparison{},
+		&models.RewardEntry{},
+		&models.ProcessingRun{},
+		&models.ReplayCheckpoint{},
+		&models.DeadLetter{},
+		&models.Statement{},
+	); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+func LoadAWSConfig(ctx context.Context, cfg *config.Config) (aws.Config, error) {
+	awsCfg, err := awscfg.LoadDefaultConfig(ctx,
+		awscfg.WithRegion(cfg.S3Region),
+	)
+	if err != nil {
+		return aws.Config{}, err
+	}
+
+	if cfg.AWSEndpointURL != "" {
+		awsCfg.EndpointResolverWithOptions = aws.EndpointResolverWithOptionsFunc(
+			func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+				return aws.Endpoint{
+					URL:               cfg.AWSEndpointURL,
+					HostnameImmutable: true,
+				}, nil
+			},
+		)
+	}
+	return awsCfg, nil
+}
+
+func NewS3Client(awsCfg aws.Config, cfg *config.Config) *s3.Client {
+	return s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+}
+
+// NewEmailSender usa SES salvo con AWS_ENDPOINT_URL (LocalStack) o con
+// FILE_READER=fs (sin AWS), donde solo se registra el correo.
+func NewEmailSender(awsCfg aws.Config, cfg *config.Config) out.EmailSender {
+	if cfg.AWSEndpointURL != "" || cfg.FileReader == "fs" {
+		return email.NewNoopEmailSender(cfg)
+	}
+	return email.NewSESEmailSender(sesv2.NewFromConfig(awsCfg), cfg)
+}
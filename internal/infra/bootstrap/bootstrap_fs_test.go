@@ -0,0 +1,196 @@
+package bootstrap
+
+import (
+	"context"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/infra/database"
+	"stori-challenge/internal/infra/logger"
+	"stori-challenge/internal/interfaces/out/rds/models"
+
+	"go.uber.org/zap"
+)
+
+func TestInitializeApp_FileSystemPipelineWithoutAWS(t *testing.T) {
+	appCtx, _ := newFSApp(t)
+
+	ctx := context.Background()
+	obj := domain.ObjectRef{Bucket: "partner", Key: "input/acc-1/txns.csv"}
+	if err := appCtx.SummaryUseCase.ProcessTransactionsFromObject(ctx, obj); err != nil {
+		t.Fatalf("ProcessTransactionsFromObject returned error: %v", err)
+	}
+
+	runs, err := appCtx.HistoryUseCase.ListProcessingRuns(ctx, domain.RunQuery{AccountID: "acc-1"})
+	if err != nil {
+		t.Fatalf("ListProcessingRuns returned error: %v", err)
+	}
+	if len(runs) != 1 || runs[0].Status != domain.RunStatusSucceeded || runs[0].TransactionsCount != 3 {
+		t.Fatalf("expected one succeeded run with 3 transactions, got %+v", runs)
+	}
+}
+
+func TestInitializeApp_ReprocessingKeepsRowCounts(t *testing.T) {
+	appCtx, cfg := newFSApp(t)
+	counts := rowCounter(t, cfg)
+
+	ctx := context.Background()
+	obj := domain.ObjectRef{Bucket: "partner", Key: "input/acc-1/txns.csv"}
+	var first map[string]int64
+	for i := range 2 {
+		if err := appCtx.SummaryUseCase.ProcessTransactionsFromObject(ctx, obj); err != nil {
+			t.Fatalf("run %d: ProcessTransactionsFromObject returned error: %v", i+1, err)
+		}
+		if i == 0 {
+			first = counts()
+			continue
+		}
+		if got := counts(); !maps.Equal(got, first) {
+			t.Fatalf("row counts changed after replay: first %v, second %v", first, got)
+		}
+	}
+	if first["transactions"] != 3 || first["account_summaries"] != 1 || first["reward_entries"] == 0 {
+		t.Fatalf("unexpected row counts after first run: %v", first)
+	}
+}
+
+func TestInitializeApp_ReplayOfProcessedObjectKeepsRowCounts(t *testing.T) {
+	appCtx, cfg := newFSApp(t, func(cfg *config.Config) { cfg.ObjectLifecycleEnabled = true })
+	counts := rowCounter(t, cfg)
+
+	ctx := context.Background()
+	if err := appCtx.SummaryUseCase.ProcessTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "partner", Key: "input/acc-1/txns.csv"}); err != nil {
+		t.Fatalf("ProcessTransactionsFromObject returned error: %v", err)
+	}
+	first := counts()
+
+	replay := domain.ObjectRef{Bucket: "partner", Key: "processed/input/acc-1/txns.csv"}
+	if err := appCtx.SummaryUseCase.ProcessTransactionsFromObject(ctx, replay); err != nil {
+		t.Fatalf("replay of processed/ returned error: %v", err)
+	}
+	if got := counts(); !maps.Equal(got, first) {
+		t.Fatalf("row counts changed after replaying processed/: first %v, second %v", first, got)
+	}
+}
+
+// rowCounter cuenta las filas por tabla abriendo el mismo archivo SQLite.
+func rowCounter(t *testing.T, cfg *config.Config) func() map[string]int64 {
+	t.Helper()
+
+	db, err := database.NewSQLiteDB(cfg)
+	if err != nil {
+		t.Fatalf("NewSQLiteDB returned error: %v", err)
+	}
+	return func() map[string]int64 {
+		got := map[string]int64{}
+		for name, model := range map[string]any{
+			"transactions":        &models.Transaction{},
+			"account_summaries":   &models.AccountSummary{},
+			"monthly_summaries":   &models.MonthlySummary{},
+			"monthly_comparisons": &models.MonthlyComparison{},
+			"reward_entries":      &models.RewardEntry{},
+		} {
+			var n int64
+			if err := db.Model(model).Count(&n).Error; err != nil {
+				t.Fatalf("count %s: %v", name, err)
+			}
+			got[name] = n
+		}
+		return got
+	}
+}
+
+func TestInitializeApp_FileSystemUploadAndLifecycle(t *testing.T) {
+	appCtx, cfg := newFSApp(t, func(cfg *config.Config) { cfg.ObjectLifecycleEnabled = true })
+
+	ctx := context.Background()
+	res, err := appCtx.UploadUseCase.UploadTransactions(ctx, "acc-2", []byte("Id,Date,Transaction\n0,7/15,+60.5\n"))
+	if err != nil {
+		t.Fatalf("UploadTransactions returned error: %v", err)
+	}
+	uploaded := filepath.Join(cfg.FileReaderRoot, res.Bucket, filepath.FromSlash(res.ObjectKey))
+	if _, err := os.Stat(uploaded); err != nil {
+		t.Fatalf("upload not written under FILE_READER_ROOT: %v", err)
+	}
+
+	obj := domain.ObjectRef{Bucket: res.Bucket, Key: res.ObjectKey}
+	if err := appCtx.SummaryUseCase.ProcessTransactionsFromObject(ctx, obj); err != nil {
+		t.Fatalf("ProcessTransactionsFromObject returned error: %v", err)
+	}
+
+	if _, err := os.Stat(uploaded); !os.IsNotExist(err) {
+		t.Errorf("source should be moved after processing, stat err = %v", err)
+	}
+	processed := filepath.Join(cfg.FileReaderRoot, res.Bucket, "processed", filepath.FromSlash(res.ObjectKey))
+	if _, err := os.Stat(processed); err != nil {
+		t.Errorf("expected the object under processed/: %v", err)
+	}
+}
+
+func TestInitializeApp_AmountFormatReachesUploadsAndPreviews(t *testing.T) {
+	appCtx, _ := newFSApp(t, func(cfg *config.Config) { cfg.AmountFormat = "es-ES" })
+
+	ctx := context.Background()
+	content := []byte("Id;Date;Transaction\n0;7/15;1.234,56\n1;7/28;-10,30\n")
+
+	res, err := appCtx.UploadUseCase.UploadTransactions(ctx, "acc-2", content)
+	if err != nil {
+		t.Fatalf("UploadTransactions returned error: %v", err)
+	}
+	if res.TransactionsCount != 2 {
+		t.Errorf("uploaded %d transactions, want 2", res.TransactionsCount)
+	}
+
+	preview, err := appCtx.PreviewUseCase.PreviewSummary(ctx, "acc-2", content)
+	if err != nil {
+		t.Fatalf("PreviewSummary returned error: %v", err)
+	}
+	if got := preview.Summary.TotalBalance.StringFixed(2); got != "1224.26" {
+		t.Errorf("TotalBalance = %s, want 1224.26", got)
+	}
+}
+
+// newFSApp arma la aplicación con FILE_READER=fs, SQLite y recompensas sobre
+// un único CSV en partner/input/acc-1/txns.csv.
+func newFSApp(t *testing.T, opts ...func(*config.Config)) (*AppContext, *config.Config) {
+	t.Helper()
+	prev := logger.Logger
+	logger.Logger = zap.NewNop()
+	t.Cleanup(func() { logger.Logger = prev })
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "partner", "input", "acc-1")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	csv := "Id,Date,Transaction\n0,7/15,+60.5\n1,7/28,-10.3\n2,8/2,-20.46\n"
+	if err := os.WriteFile(filepath.Join(dir, "txns.csv"), []byte(csv), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+
+	cfg := &config.Config{
+		DBDriver:        "sqlite",
+		DBSQLitePath:    filepath.Join(t.TempDir(), "stori.db"),
+		S3Region:        "us-east-1",
+		S3BucketName:    "partner",
+		SESFrom:         "no-reply@stori-local.test",
+		EmailDefault:    "user@example.com",
+		FileReader:      "fs",
+		FileReaderRoot:  root,
+		RewardsEnabled:  true,
+		RewardsBaseRate: "1",
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	appCtx, err := InitializeApp(cfg)
+	if err != nil {
+		t.Fatalf("InitializeApp returned error: %v", err)
+	}
+	return appCtx, cfg
+}
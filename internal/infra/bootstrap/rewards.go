@@ -0,0 +1,65 @@
+package bootstrap
+
+import (
+	"fmt"
+	"strings"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/infra/config"
+
+	"github.com/shopspring/decimal"
+)
+
+func rewardRulesFromConfig(cfg *config.Config) (domain.RewardRules, error) {
+	var rules domain.RewardRules
+	var err error
+
+	if rules.BaseRate, err = parseDecimalSetting("REWARDS_BASE_RATE", cfg.RewardsBaseRate); err != nil {
+		return rules, err
+	}
+	if rules.CashbackRate, err = parseDecimalSetting("REWARDS_CASHBACK_RATE", cfg.RewardsCashbackRate); err != nil {
+		return rules, err
+	}
+	if rules.PointsCapPerCycle, err = parseDecimalSetting("REWARDS_POINTS_CAP_PER_CYCLE", cfg.RewardsPointsCapPerCycle); err != nil {
+		return rules, err
+	}
+	if rules.CashbackCapPerCycle, err = parseDecimalSetting("REWARDS_CASHBACK_CAP_PER_CYCLE", cfg.RewardsCashbackCapPerCycle); err != nil {
+		return rules, err
+	}
+	if rules.CategoryMultipliers, err = parseCategoryMultipliers(cfg.RewardsCategoryMultipliers); err != nil {
+		return rules, err
+	}
+	return rules, nil
+}
+
+func parseDecimalSetting(name, v string) (decimal.Decimal, error) {
+	if strings.TrimSpace(v) == "" {
+		return decimal.Zero, nil
+	}
+	d, err := decimal.NewFromString(strings.TrimSpace(v))
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("%s inválido %q: %w", name, v, err)
+	}
+	return d, nil
+}
+
+// parseCategoryMultipliers interpreta "groceries:2,travel:1.5".
+func parseCategoryMultipliers(v string) (map[string]decimal.Decimal, error) {
+	result := map[string]decimal.Decimal{}
+	for _, pair := range strings.Split(v, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		category, mult, ok := strings.Cut(pair, ":")
+		if !ok || strings.TrimSpace(category) == "" {
+			return nil, fmt.Errorf("REWARDS_CATEGORY_MULTIPLIERS inválido: %q", pair)
+		}
+		d, err := parseDecimalSetting("REWARDS_CATEGORY_MULTIPLIERS", mult)
+		if err != nil {
+			return nil, err
+		}
+		result[strings.ToLower(strings.TrimSpace(category))] = d
+	}
+	return result, nil
+}
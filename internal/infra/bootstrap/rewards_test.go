@@ -0,0 +1,54 @@
+package bootstrap
+
+import (
+	"testing"
+
+	"stori-challenge/internal/infra/config"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRewardRulesFromConfig(t *testing.T) {
+	cfg := &config.Config{
+		RewardsBaseRate:            "1.5",
+		RewardsCashbackRate:        "0.02",
+		RewardsCategoryMultipliers: "Groceries:2, travel:3",
+		RewardsPointsCapPerCycle:   "1000",
+		RewardsCashbackCapPerCycle: "",
+	}
+
+	rules, err := rewardRulesFromConfig(cfg)
+	if err != nil {
+		t.Fatalf("rewardRulesFromConfig returned error: %v", err)
+	}
+
+	if !rules.BaseRate.Equal(decimal.RequireFromString("1.5")) {
+		t.Errorf("BaseRate = %s, want 1.5", rules.BaseRate)
+	}
+	if !rules.CashbackRate.Equal(decimal.RequireFromString("0.02")) {
+		t.Errorf("CashbackRate = %s, want 0.02", rules.CashbackRate)
+	}
+	if !rules.PointsCapPerCycle.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("PointsCapPerCycle = %s, want 1000", rules.PointsCapPerCycle)
+	}
+	if !rules.CashbackCapPerCycle.IsZero() {
+		t.Errorf("CashbackCapPerCycle = %s, want 0", rules.CashbackCapPerCycle)
+	}
+	if got := rules.Multiplier("groceries"); !got.Equal(decimal.NewFromInt(2)) {
+		t.Errorf("Multiplier(groceries) = %s, want 2", got)
+	}
+	if got := rules.Multiplier("TRAVEL"); !got.Equal(decimal.NewFromInt(3)) {
+		t.Errorf("Multiplier(TRAVEL) = %s, want 3", got)
+	}
+	if got := rules.Multiplier("other"); !got.Equal(decimal.NewFromInt(1)) {
+		t.Errorf("Multiplier(other) = %s, want 1", got)
+	}
+}
+
+func TestRewardRulesFromConfig_InvalidMultiplier(t *testing.T) {
+	cfg := &config.Config{RewardsCategoryMultipliers: "groceries"}
+
+	if _, err := rewardRulesFromConfig(cfg); err == nil {
+		t.Fatalf("expected error for malformed multipliers, got nil")
+	}
+}
@@ -23,6 +23,34 @@ type Config struct {
 	UsePathStyle   bool   `mapstructure:"AWS_S3_USE_PATH_STYLE"`
 	StoriLogoURL   string `mapstructure:"STORI_LOGO_URL"`
 	DBSSLMode      string `mapstructure:"DB_SSL_MODE"`
+	DBDriver       string `mapstructure:"DB_DRIVER"`
+	DBSQLitePath   string `mapstructure:"DB_SQLITE_PATH"`
+	ServerAddr     string `mapstructure:"SERVER_ADDR"`
+
+	S3EventConcurrency int `mapstructure:"S3_EVENT_CONCURRENCY"`
+	SQSMaxReceiveCount int `mapstructure:"SQS_MAX_RECEIVE_COUNT"`
+	SQSConcurrency     int `mapstructure:"SQS_CONCURRENCY"`
+
+	ObjectLifecycleEnabled bool `mapstructure:"OBJECT_LIFECYCLE_ENABLED"`
+
+	FileReader     string `mapstructure:"FILE_READER"`
+	FileReaderRoot string `mapstructure:"FILE_READER_ROOT"`
+	FileFieldMap   string `mapstructure:"FILE_FIELD_MAP"`
+	AmountFormat   string `mapstructure:"AMOUNT_FORMAT"`
+
+	S3RangePartSizeMB  int `mapstructure:"S3_RANGE_PART_SIZE_MB"`
+	S3RangeConcurrency int `mapstructure:"S3_RANGE_CONCURRENCY"`
+
+	RewardsEnabled             bool   `mapstructure:"REWARDS_ENABLED"`
+	RewardsBaseRate            string `mapstructure:"REWARDS_BASE_RATE"`
+	RewardsCashbackRate        string `mapstructure:"REWARDS_CASHBACK_RATE"`
+	RewardsCategoryMultipliers string `mapstructure:"REWARDS_CATEGORY_MULTIPLIERS"`
+	RewardsPointsCapPerCycle   string `mapstructure:"REWARDS_POINTS_CAP_PER_CYCLE"`
+	RewardsCashbackCapPerCycle string `mapstructure:"REWARDS_CASHBACK_CAP_PER_CYCLE"`
+
+	ForecastEnabled      bool   `mapstructure:"FORECAST_ENABLED"`
+	ForecastMethod       string `mapstructure:"FORECAST_METHOD"`
+	ForecastWindowMonths int    `mapstructure:"FORECAST_WINDOW_MONTHS"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -32,6 +60,25 @@ func LoadConfig() (*Config, error) {
 	viper.SetDefault("AWS_S3_USE_PATH_STYLE", false)
 	viper.SetDefault("STORI_LOGO_URL", "https://media.licdn.com/dms/image/v2/D4E0BAQHuxJutLmsBFQ/company-logo_200_200/company-logo_200_200/0/1700583469952?e=1764201600&v=beta&t=yAwe1j0mbzSEM19MZSGWYt1RWiD9l7rPcgjSxGZSp_Q")
 	viper.SetDefault("DB_SSL_MODE", "disable")
+	viper.SetDefault("DB_DRIVER", "postgres")
+	viper.SetDefault("DB_SQLITE_PATH", "stori.db")
+	viper.SetDefault("SERVER_ADDR", ":8080")
+	viper.SetDefault("S3_EVENT_CONCURRENCY", 4)
+	viper.SetDefault("SQS_MAX_RECEIVE_COUNT", 5)
+	viper.SetDefault("SQS_CONCURRENCY", 4)
+	viper.SetDefault("OBJECT_LIFECYCLE_ENABLED", false)
+	viper.SetDefault("FILE_READER", "s3")
+	viper.SetDefault("S3_RANGE_PART_SIZE_MB", 16)
+	viper.SetDefault("S3_RANGE_CONCURRENCY", 8)
+	viper.SetDefault("REWARDS_ENABLED", false)
+	viper.SetDefault("REWARDS_BASE_RATE", "1")
+	viper.SetDefault("REWARDS_CASHBACK_RATE", "0.01")
+	viper.SetDefault("REWARDS_CATEGORY_MULTIPLIERS", "")
+	viper.SetDefault("REWARDS_POINTS_CAP_PER_CYCLE", "0")
+	viper.SetDefault("REWARDS_CASHBACK_CAP_PER_CYCLE", "0")
+	viper.SetDefault("FORECAST_ENABLED", false)
+	viper.SetDefault("FORECAST_METHOD", "moving_average")
+	viper.SetDefault("FORECAST_WINDOW_MONTHS", 3)
 
 	for _, k := range []string{
 		"DB_HOST", "DB_PORT", "DB_USER", "DB_PASSWORD",
@@ -40,7 +87,16 @@ func LoadConfig() (*Config, error) {
 		"SES_FROM", "EMAIL_DEFAULT",
 		"AWS_ENDPOINT_URL", "AWS_S3_USE_PATH_STYLE",
 		"STORI_LOGO_URL",
-		"DB_SSL_MODE",
+		"DB_SSL_MODE", "DB_DRIVER", "DB_SQLITE_PATH",
+		"SERVER_ADDR",
+		"S3_EVENT_CONCURRENCY", "SQS_MAX_RECEIVE_COUNT", "SQS_CONCURRENCY",
+		"OBJECT_LIFECYCLE_ENABLED",
+		"FILE_READER", "FILE_READER_ROOT", "FILE_FIELD_MAP", "AMOUNT_FORMAT",
+		"S3_RANGE_PART_SIZE_MB", "S3_RANGE_CONCURRENCY",
+		"REWARDS_ENABLED", "REWARDS_BASE_RATE", "REWARDS_CASHBACK_RATE",
+		"REWARDS_CATEGORY_MULTIPLIERS",
+		"REWARDS_POINTS_CAP_PER_CYCLE", "REWARDS_CASHBACK_CAP_PER_CYCLE",
+		"FORECAST_ENABLED", "FORECAST_METHOD", "FORECAST_WINDOW_MONTHS",
 	} {
 		_ = viper.BindEnv(k)
 	}
@@ -65,11 +121,25 @@ func LoadConfig() (*Config, error) {
 			missing = append(missing, k)
 		}
 	}
-	req("DB_HOST", cfg.DBHost)
-	req("DB_USER", cfg.DBUser)
-	req("DB_PASSWORD", cfg.DBPassword)
-	req("DB_NAME", cfg.DBName)
-	req("DB_PORT", cfg.DBPort)
+	switch cfg.DBDriver {
+	case "postgres":
+		req("DB_HOST", cfg.DBHost)
+		req("DB_USER", cfg.DBUser)
+		req("DB_PASSWORD", cfg.DBPassword)
+		req("DB_NAME", cfg.DBName)
+		req("DB_PORT", cfg.DBPort)
+	case "sqlite":
+		req("DB_SQLITE_PATH", cfg.DBSQLitePath)
+	default:
+		return nil, fmt.Errorf("DB_DRIVER inválido: %q (postgres|sqlite)", cfg.DBDriver)
+	}
+	switch cfg.FileReader {
+	case "s3":
+	case "fs":
+		req("FILE_READER_ROOT", cfg.FileReaderRoot)
+	default:
+		return nil, fmt.Errorf("FILE_READER inválido: %q (s3|fs)", cfg.FileReader)
+	}
 	req("S3_BUCKET_NAME", cfg.S3BucketName)
 	req("S3_REGION", cfg.S3Region)
 	req("SES_FROM", cfg.SESFrom)
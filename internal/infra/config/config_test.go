@@ -103,3 +103,96 @@ func TestLoadConfig_MissingRequiredVariables(t *testing.T) {
 		t.Fatalf("expected error message to mention DB_HOST, got: %v", err)
 	}
 }
+
+func TestLoadConfig_SQLiteDoesNotRequirePostgresSettings(t *testing.T) {
+	resetViper(t)
+
+	t.Setenv("DB_DRIVER", "sqlite")
+	t.Setenv("S3_BUCKET_NAME", "stori-transactions-local")
+	t.Setenv("S3_REGION", "us-east-1")
+	t.Setenv("SES_FROM", "no-reply@stori-local.test")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if cfg.DBDriver != "sqlite" || cfg.DBSQLitePath != "stori.db" {
+		t.Errorf("DBDriver/DBSQLitePath = %q/%q, want sqlite/stori.db", cfg.DBDriver, cfg.DBSQLitePath)
+	}
+	if cfg.ServerAddr != ":8080" {
+		t.Errorf("ServerAddr = %q, want :8080 (default)", cfg.ServerAddr)
+	}
+	if cfg.S3EventConcurrency != 4 {
+		t.Errorf("S3EventConcurrency = %d, want 4 (default)", cfg.S3EventConcurrency)
+	}
+	if cfg.SQSMaxReceiveCount != 5 || cfg.SQSConcurrency != 4 {
+		t.Errorf("SQSMaxReceiveCount/SQSConcurrency = %d/%d, want 5/4 (defaults)", cfg.SQSMaxReceiveCount, cfg.SQSConcurrency)
+	}
+	if cfg.ObjectLifecycleEnabled {
+		t.Errorf("ObjectLifecycleEnabled should default to false")
+	}
+	if cfg.RewardsEnabled || cfg.ForecastEnabled {
+		t.Errorf("RewardsEnabled/ForecastEnabled = %v/%v, want false (defaults)", cfg.RewardsEnabled, cfg.ForecastEnabled)
+	}
+	if cfg.FileReader != "s3" {
+		t.Errorf("FileReader = %q, want s3 (default)", cfg.FileReader)
+	}
+}
+
+func TestLoadConfig_InvalidDriver(t *testing.T) {
+	resetViper(t)
+
+	t.Setenv("DB_DRIVER", "mysql")
+	t.Setenv("S3_BUCKET_NAME", "stori-transactions-local")
+	t.Setenv("S3_REGION", "us-east-1")
+	t.Setenv("SES_FROM", "no-reply@stori-local.test")
+
+	_, err := LoadConfig()
+	if err == nil || !strings.Contains(err.Error(), "DB_DRIVER") {
+		t.Fatalf("expected DB_DRIVER error, got %v", err)
+	}
+}
+
+func TestLoadConfig_FileReader(t *testing.T) {
+	cases := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{name: "fs with root", env: map[string]string{"FILE_READER": "fs", "FILE_READER_ROOT": "/data"}},
+		{name: "fs without root", env: map[string]string{"FILE_READER": "fs"}, wantErr: "FILE_READER_ROOT"},
+		{
+			name: "fs with lifecycle",
+			env:  map[string]string{"FILE_READER": "fs", "FILE_READER_ROOT": "/data", "OBJECT_LIFECYCLE_ENABLED": "true"},
+		},
+		{name: "unknown reader", env: map[string]string{"FILE_READER": "ftp"}, wantErr: "FILE_READER"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resetViper(t)
+
+			t.Setenv("DB_DRIVER", "sqlite")
+			t.Setenv("S3_BUCKET_NAME", "stori-transactions-local")
+			t.Setenv("S3_REGION", "us-east-1")
+			t.Setenv("SES_FROM", "no-reply@stori-local.test")
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := LoadConfig()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig returned error: %v", err)
+				}
+				if cfg.FileReader != "fs" || cfg.FileReaderRoot != "/data" {
+					t.Errorf("FileReader/FileReaderRoot = %q/%q", cfg.FileReader, cfg.FileReaderRoot)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error mentioning %s, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}
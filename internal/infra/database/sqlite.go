@@ -0,0 +1,48 @@
+package database
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"stori-challenge/internal/infra/config"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+)
+
+//go:embed sqlite_schema.sql
+var sqliteSchema string
+
+// NewSQLiteDB abre DB_SQLITE_PATH como el esquema "transactions" para que los
+// modelos (transactions.*) funcionen igual que en Postgres. ATTACH vale por
+// conexión, por eso el pool queda en una sola conexión. El esquema se aplica
+// con DDL propio: AutoMigrate no sabe crear índices en esquemas adjuntos. Las
+// llaves foráneas se activan en el DSN; sin ellas SQLite ignora ON DELETE
+// CASCADE.
+func NewSQLiteDB(cfg *config.Config) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open("file::memory:?_pragma=foreign_keys(1)"), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+	sqlDB.SetMaxIdleConns(1)
+	sqlDB.SetConnMaxLifetime(0)
+	sqlDB.SetConnMaxIdleTime(0)
+
+	path := strings.ReplaceAll(cfg.DBSQLitePath, "'", "''")
+	if err := db.Exec(fmt.Sprintf("ATTACH DATABASE '%s' AS transactions", path)).Error; err != nil {
+		return nil, fmt.Errorf("adjuntando %s: %w", cfg.DBSQLitePath, err)
+	}
+
+	if err := db.Exec(sqliteSchema).Error; err != nil {
+		return nil, fmt.Errorf("creando esquema sqlite: %w", err)
+	}
+
+	return db, nil
+}
@@ -0,0 +1,152 @@
+CREATE TABLE IF NOT EXISTS transactions.transactions
+(
+    id          INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id  TEXT,
+    bucket      TEXT,
+    object_key  TEXT,
+    date        DATETIME,
+    amount      NUMERIC,
+    category    TEXT,
+    reference   TEXT,
+    description TEXT,
+    memo        TEXT,
+    currency    TEXT,
+    created_at  DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS transactions.idx_transactions_account_date_id
+    ON transactions (account_id, date, id);
+
+CREATE INDEX IF NOT EXISTS transactions.idx_transactions_object
+    ON transactions (bucket, object_key);
+
+CREATE TABLE IF NOT EXISTS transactions.account_summaries
+(
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id      TEXT,
+    bucket          TEXT,
+    object_key      TEXT,
+    total_balance   NUMERIC,
+    opening_balance NUMERIC,
+    closing_balance NUMERIC,
+    created_at      DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS transactions.idx_account_summaries_account_id
+    ON account_summaries (account_id);
+
+CREATE INDEX IF NOT EXISTS transactions.idx_account_summaries_object
+    ON account_summaries (bucket, object_key);
+
+CREATE TABLE IF NOT EXISTS transactions.monthly_summaries
+(
+    id                    INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_summary_id    INTEGER NOT NULL REFERENCES account_summaries (id) ON DELETE CASCADE,
+    month                 TEXT    NOT NULL,
+    transactions_count    INTEGER NOT NULL DEFAULT 0,
+    average_debit_amount  NUMERIC NOT NULL DEFAULT 0,
+    average_credit_amount NUMERIC NOT NULL DEFAULT 0,
+    total_debit_amount    NUMERIC NOT NULL DEFAULT 0,
+    total_credit_amount   NUMERIC NOT NULL DEFAULT 0,
+    UNIQUE (account_summary_id, month)
+);
+
+CREATE TABLE IF NOT EXISTS transactions.monthly_comparisons
+(
+    id                       INTEGER PRIMARY KEY AUTOINCREMENT,
+    monthly_summary_id       INTEGER NOT NULL REFERENCES monthly_summaries (id) ON DELETE CASCADE,
+    baseline                 TEXT    NOT NULL,
+    baseline_month           TEXT    NOT NULL,
+    transactions_count_delta INTEGER NOT NULL DEFAULT 0,
+    average_debit_delta      NUMERIC NOT NULL DEFAULT 0,
+    average_credit_delta     NUMERIC NOT NULL DEFAULT 0,
+    total_debit_delta        NUMERIC NOT NULL DEFAULT 0,
+    total_credit_delta       NUMERIC NOT NULL DEFAULT 0,
+    UNIQUE (monthly_summary_id, baseline)
+);
+
+CREATE TABLE IF NOT EXISTS transactions.reward_entries
+(
+    id               INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id       TEXT,
+    cycle            TEXT,
+    bucket           TEXT,
+    object_key       TEXT,
+    category         TEXT,
+    transaction_date DATETIME,
+    amount           NUMERIC,
+    points           NUMERIC,
+    cashback         NUMERIC,
+    created_at       DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS transactions.idx_reward_entries_account_cycle
+    ON reward_entries (account_id, cycle);
+
+CREATE TABLE IF NOT EXISTS transactions.processing_runs
+(
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id         TEXT,
+    bucket             TEXT,
+    object_key         TEXT,
+    version_id         TEXT,
+    status             TEXT    NOT NULL,
+    error              TEXT,
+    transactions_count INTEGER NOT NULL DEFAULT 0,
+    encoding           TEXT,
+    has_bom            BOOLEAN NOT NULL DEFAULT 0,
+    delimiter          TEXT,
+    created_at         DATETIME,
+    started_at         DATETIME,
+    finished_at        DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS transactions.idx_processing_runs_object
+    ON processing_runs (bucket, object_key);
+
+CREATE TABLE IF NOT EXISTS transactions.replay_checkpoints
+(
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    replay_id  TEXT NOT NULL,
+    bucket     TEXT NOT NULL,
+    object_key TEXT NOT NULL,
+    status     TEXT NOT NULL,
+    error      TEXT,
+    created_at DATETIME,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions.idx_replay_checkpoints_object
+    ON replay_checkpoints (replay_id, bucket, object_key);
+
+CREATE TABLE IF NOT EXISTS transactions.dead_letters
+(
+    id            INTEGER PRIMARY KEY AUTOINCREMENT,
+    source        TEXT    NOT NULL,
+    message_id    TEXT,
+    bucket        TEXT,
+    object_key    TEXT,
+    payload       TEXT,
+    error         TEXT    NOT NULL,
+    receive_count INTEGER NOT NULL DEFAULT 0,
+    created_at    DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS transactions.idx_dead_letters_object
+    ON dead_letters (bucket, object_key);
+
+CREATE TABLE IF NOT EXISTS transactions.statements
+(
+    id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+    account_id         TEXT    NOT NULL,
+    period             TEXT    NOT NULL,
+    status             TEXT    NOT NULL,
+    error              TEXT,
+    transactions_count INTEGER NOT NULL DEFAULT 0,
+    created_at         DATETIME,
+    claimed_at         DATETIME NOT NULL,
+    sent_at            DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS transactions.idx_statements_account_period
+    ON statements (account_id, period);
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/infra/config"
+	"stori-challenge/internal/interfaces/out/rds"
+	"stori-challenge/internal/interfaces/out/rds/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+func TestNewSQLiteDB_SchemaSupportsRepositories(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stori.db")
+	cfg := &config.Config{DBSQLitePath: path}
+
+	db, err := NewSQLiteDB(cfg)
+	if err != nil {
+		t.Fatalf("NewSQLiteDB returned error: %v", err)
+	}
+
+	repo := rds.NewTransactionRepo(db)
+	ctx := context.Background()
+	txs := []domain.Transaction{
+		{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: decimal.RequireFromString("60.5")},
+	}
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-1/a.csv", txs); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+	if err := repo.SaveSummary(ctx, "bucket", "input/acc-1/a.csv", domain.AccountSummary{
+		ByMonth: []domain.MonthlySummary{{MonthName: "2021-07", TransactionsCount: 1}},
+	}); err != nil {
+		t.Fatalf("SaveSummary returned error: %v", err)
+	}
+	if _, err := repo.StartProcessingRun(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/a.csv"}); err != nil {
+		t.Fatalf("StartProcessingRun returned error: %v", err)
+	}
+
+	// Reabrir sobre el mismo archivo conserva los datos y no falla por el DDL.
+	reopened, err := NewSQLiteDB(cfg)
+	if err != nil {
+		t.Fatalf("reopening NewSQLiteDB returned error: %v", err)
+	}
+	months, err := rds.NewTransactionRepo(reopened).AggregateByMonth(ctx, "acc-1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("AggregateByMonth returned error: %v", err)
+	}
+	if len(months) != 1 || months[0].TransactionsCount != 1 {
+		t.Errorf("months = %+v", months)
+	}
+}
+
+// El DDL de SQLite se mantiene a mano; este test avisa si una columna de los
+// modelos falta en sqlite_schema.sql o sobra en él.
+func TestNewSQLiteDB_SchemaMatchesModels(t *testing.T) {
+	db, err := NewSQLiteDB(&config.Config{DBSQLitePath: filepath.Join(t.TempDir(), "stori.db")})
+	if err != nil {
+		t.Fatalf("NewSQLiteDB returned error: %v", err)
+	}
+
+	for _, model := range []any{
+		&models.Transaction{},
+		&models.AccountSummary{},
+		&models.MonthlySummary{},
+		&models.MonthlyComparison{},
+		&models.RewardEntry{},
+		&models.ProcessingRun{},
+		&models.ReplayCheckpoint{},
+		&models.DeadLetter{},
+		&models.Statement{},
+	} {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			t.Fatalf("parsing %T: %v", model, err)
+		}
+		want := slices.Sorted(slices.Values(stmt.Schema.DBNames))
+
+		table := strings.TrimPrefix(stmt.Schema.Table, "transactions.")
+		var got []string
+		if err := db.Raw("SELECT name FROM pragma_table_info(?, 'transactions') ORDER BY name", table).
+			Scan(&got).Error; err != nil {
+			t.Fatalf("reading columns of %s: %v", table, err)
+		}
+
+		if !slices.Equal(got, want) {
+			t.Errorf("%s columns = %v, model %T wants %v", table, got, model, want)
+		}
+	}
+}
+
+func TestNewSQLiteDB_DeletingSummaryCascades(t *testing.T) {
+	db, err := NewSQLiteDB(&config.Config{DBSQLitePath: filepath.Join(t.TempDir(), "stori.db")})
+	if err != nil {
+		t.Fatalf("NewSQLiteDB returned error: %v", err)
+	}
+
+	repo := rds.NewTransactionRepo(db)
+	if err := repo.SaveSummary(context.Background(), "bucket", "input/acc-1/a.csv", domain.AccountSummary{
+		ByMonth: []domain.MonthlySummary{{MonthName: "2021-07", TransactionsCount: 1}},
+	}); err != nil {
+		t.Fatalf("SaveSummary returned error: %v", err)
+	}
+
+	if err := db.Exec("DELETE FROM transactions.account_summaries").Error; err != nil {
+		t.Fatalf("deleting summaries: %v", err)
+	}
+	var months int64
+	if err := db.Model(&models.MonthlySummary{}).Count(&months).Error; err != nil {
+		t.Fatalf("counting monthly summaries: %v", err)
+	}
+	if months != 0 {
+		t.Errorf("expected monthly summaries to be deleted in cascade, got %d", months)
+	}
+}
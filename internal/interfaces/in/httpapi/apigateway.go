@@ -0,0 +1,118 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Handle traduce el evento de API Gateway v2 a un *http.Request y devuelve lo
+// que escribió el mux.
+func (h *Handler) Handle(
+	ctx context.Context,
+	req events.APIGatewayV2HTTPRequest,
+) (events.APIGatewayV2HTTPResponse, error) {
+	body := []byte(req.Body)
+	if req.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(req.Body)
+		if err != nil {
+			return errorResponse(http.StatusBadRequest, "cuerpo base64 inválido"), nil
+		}
+		body = decoded
+	}
+
+	method, path := requestLine(req)
+	target := (&url.URL{Path: path, RawQuery: rawQuery(req)}).String()
+
+	r, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(body))
+	if err != nil {
+		return errorResponse(http.StatusBadRequest, "petición inválida"), nil
+	}
+	for k, v := range req.Headers {
+		r.Header.Set(k, v)
+	}
+
+	rec := newRecorder()
+	h.ServeHTTP(rec, r)
+	return rec.response(), nil
+}
+
+// requestLine usa método y path del contexto HTTP; en invocaciones locales
+// que solo traen RouteKey ("POST /upload") los toma de ahí.
+func requestLine(req events.APIGatewayV2HTTPRequest) (string, string) {
+	method := req.RequestContext.HTTP.Method
+	path := req.RawPath
+	if path == "" {
+		path = req.RequestContext.HTTP.Path
+	}
+
+	if routeMethod, routePath, ok := strings.Cut(req.RouteKey, " "); ok {
+		if method == "" {
+			method = routeMethod
+		}
+		if path == "" && !strings.Contains(routePath, "{") {
+			path = routePath
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	return strings.ToUpper(method), path
+}
+
+func rawQuery(req events.APIGatewayV2HTTPRequest) string {
+	if req.RawQueryString != "" {
+		return req.RawQueryString
+	}
+	values := url.Values{}
+	for k, v := range req.QueryStringParameters {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+type recorder struct {
+	status int
+	header http.Header
+	body   bytes.Buffer
+}
+
+func newRecorder() *recorder {
+	return &recorder{header: http.Header{}}
+}
+
+func (r *recorder) Header() http.Header { return r.header }
+
+func (r *recorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+func (r *recorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+}
+
+func (r *recorder) response() events.APIGatewayV2HTTPResponse {
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	headers := make(map[string]string, len(r.header))
+	for k, v := range r.header {
+		headers[k] = strings.Join(v, ",")
+	}
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: status,
+		Headers:    headers,
+		Body:       r.body.String(),
+	}
+}
@@ -0,0 +1,229 @@
+package httpapi
+
+import (
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+type uploadDTO struct {
+	Bucket            string `json:"bucket"`
+	ObjectKey         string `json:"object_key"`
+	RunID             uint64 `json:"run_id"`
+	TransactionsCount int    `json:"transactions_count"`
+}
+
+type summaryDTO struct {
+	ID             uint64            `json:"id"`
+	AccountID      string            `json:"account_id"`
+	Bucket         string            `json:"bucket"`
+	ObjectKey      string            `json:"object_key"`
+	CreatedAt      time.Time         `json:"created_at"`
+	TotalBalance   decimal.Decimal   `json:"total_balance"`
+	OpeningBalance *decimal.Decimal  `json:"opening_balance,omitempty"`
+	ClosingBalance *decimal.Decimal  `json:"closing_balance,omitempty"`
+	Months         []monthSummaryDTO `json:"months"`
+}
+
+type monthSummaryDTO struct {
+	Month               string          `json:"month"`
+	TransactionsCount   int             `json:"transactions_count"`
+	AverageDebitAmount  decimal.Decimal `json:"average_debit_amount"`
+	AverageCreditAmount decimal.Decimal `json:"average_credit_amount"`
+	TotalDebitAmount    decimal.Decimal `json:"total_debit_amount"`
+	TotalCreditAmount   decimal.Decimal `json:"total_credit_amount"`
+	VsPreviousMonth     *monthDeltaDTO  `json:"vs_previous_month,omitempty"`
+	VsPreviousYear      *monthDeltaDTO  `json:"vs_previous_year,omitempty"`
+}
+
+type monthDeltaDTO struct {
+	BaselineMonth       string          `json:"baseline_month"`
+	TransactionsCount   int             `json:"transactions_count"`
+	AverageDebitAmount  decimal.Decimal `json:"average_debit_amount"`
+	AverageCreditAmount decimal.Decimal `json:"average_credit_amount"`
+	TotalDebitAmount    decimal.Decimal `json:"total_debit_amount"`
+	TotalCreditAmount   decimal.Decimal `json:"total_credit_amount"`
+}
+
+type previewDTO struct {
+	Summary previewSummaryDTO `json:"summary"`
+	Email   emailDTO          `json:"email"`
+}
+
+type previewSummaryDTO struct {
+	AccountID    string            `json:"account_id"`
+	TotalBalance decimal.Decimal   `json:"total_balance"`
+	Months       []monthSummaryDTO `json:"months"`
+}
+
+type emailDTO struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+	Plain   string `json:"plain"`
+}
+
+type transactionDTO struct {
+	ID          uint64          `json:"id"`
+	AccountID   string          `json:"account_id"`
+	Date        time.Time       `json:"date"`
+	Amount      decimal.Decimal `json:"amount"`
+	Category    string          `json:"category,omitempty"`
+	Reference   string          `json:"reference,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Memo        string          `json:"memo,omitempty"`
+	Bucket      string          `json:"bucket"`
+	ObjectKey   string          `json:"object_key"`
+}
+
+type transactionPageDTO struct {
+	Items      []transactionDTO `json:"items"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+type runDTO struct {
+	ID                uint64      `json:"id"`
+	AccountID         string      `json:"account_id"`
+	Bucket            string      `json:"bucket"`
+	ObjectKey         string      `json:"object_key"`
+	VersionID         string      `json:"version_id,omitempty"`
+	Status            string      `json:"status"`
+	Error             string      `json:"error,omitempty"`
+	TransactionsCount int         `json:"transactions_count"`
+	Dialect           *dialectDTO `json:"dialect,omitempty"`
+	CreatedAt         time.Time   `json:"created_at"`
+	StartedAt         *time.Time  `json:"started_at,omitempty"`
+	FinishedAt        *time.Time  `json:"finished_at,omitempty"`
+}
+
+func toUploadDTO(r domain.UploadResult) uploadDTO {
+	return uploadDTO{
+		Bucket:            r.Bucket,
+		ObjectKey:         r.ObjectKey,
+		RunID:             r.RunID,
+		TransactionsCount: r.TransactionsCount,
+	}
+}
+
+func toSummaryDTO(s domain.StoredSummary) summaryDTO {
+	months := make([]monthSummaryDTO, 0, len(s.Summary.ByMonth))
+	for _, m := range s.Summary.ByMonth {
+		months = append(months, toMonthSummaryDTO(m))
+	}
+	return summaryDTO{
+		ID:             s.ID,
+		AccountID:      s.Summary.AccountID,
+		Bucket:         s.Bucket,
+		ObjectKey:      s.ObjectKey,
+		CreatedAt:      s.CreatedAt,
+		TotalBalance:   s.Summary.TotalBalance.Round(2),
+		OpeningBalance: roundedBalance(s.Summary.OpeningBalance),
+		ClosingBalance: roundedBalance(s.Summary.ClosingBalance),
+		Months:         months,
+	}
+}
+
+func roundedBalance(d *decimal.Decimal) *decimal.Decimal {
+	if d == nil {
+		return nil
+	}
+	rounded := d.Round(2)
+	return &rounded
+}
+
+func toPreviewDTO(p domain.SummaryPreview) previewDTO {
+	months := make([]monthSummaryDTO, 0, len(p.Summary.ByMonth))
+	for _, m := range p.Summary.ByMonth {
+		months = append(months, toMonthSummaryDTO(m))
+	}
+	return previewDTO{
+		Summary: previewSummaryDTO{
+			AccountID:    p.Summary.AccountID,
+			TotalBalance: p.Summary.TotalBalance.Round(2),
+			Months:       months,
+		},
+		Email: emailDTO{
+			Subject: p.Email.Subject,
+			HTML:    p.Email.HTML,
+			Plain:   p.Email.Plain,
+		},
+	}
+}
+
+func toMonthSummaryDTO(m domain.MonthlySummary) monthSummaryDTO {
+	return monthSummaryDTO{
+		Month:               m.MonthName,
+		TransactionsCount:   m.TransactionsCount,
+		AverageDebitAmount:  m.AverageDebitAmount.Round(2),
+		AverageCreditAmount: m.AverageCreditAmount.Round(2),
+		TotalDebitAmount:    m.TotalDebitAmount.Round(2),
+		TotalCreditAmount:   m.TotalCreditAmount.Round(2),
+		VsPreviousMonth:     toMonthDeltaDTO(m.VsPreviousMonth),
+		VsPreviousYear:      toMonthDeltaDTO(m.VsPreviousYear),
+	}
+}
+
+func toMonthDeltaDTO(d *domain.MonthlyDelta) *monthDeltaDTO {
+	if d == nil {
+		return nil
+	}
+	return &monthDeltaDTO{
+		BaselineMonth:       d.BaselineMonth,
+		TransactionsCount:   d.TransactionsCount,
+		AverageDebitAmount:  d.AverageDebitAmount.Round(2),
+		AverageCreditAmount: d.AverageCreditAmount.Round(2),
+		TotalDebitAmount:    d.TotalDebitAmount.Round(2),
+		TotalCreditAmount:   d.TotalCreditAmount.Round(2),
+	}
+}
+
+func toTransactionPageDTO(p domain.TransactionPage) transactionPageDTO {
+	items := make([]transactionDTO, 0, len(p.Items))
+	for _, tx := range p.Items {
+		items = append(items, transactionDTO{
+			ID:          tx.ID,
+			AccountID:   tx.AccountID,
+			Date:        tx.Date,
+			Amount:      tx.Amount,
+			Category:    tx.Category,
+			Reference:   tx.Reference,
+			Description: tx.Description,
+			Memo:        tx.Memo,
+			Bucket:      tx.Bucket,
+			ObjectKey:   tx.ObjectKey,
+		})
+	}
+	return transactionPageDTO{Items: items, NextCursor: p.NextCursor}
+}
+
+func toRunDTO(r domain.ProcessingRun) runDTO {
+	return runDTO{
+		ID:                r.ID,
+		AccountID:         r.AccountID,
+		Bucket:            r.Bucket,
+		ObjectKey:         r.ObjectKey,
+		VersionID:         r.VersionID,
+		Status:            string(r.Status),
+		Error:             r.Error,
+		TransactionsCount: r.TransactionsCount,
+		Dialect:           toDialectDTO(r.Dialect),
+		CreatedAt:         r.CreatedAt,
+		StartedAt:         r.StartedAt,
+		FinishedAt:        r.FinishedAt,
+	}
+}
+
+// dialectDTO describe cómo se leyó el CSV de una corrida.
+type dialectDTO struct {
+	Encoding  string `json:"encoding"`
+	BOM       bool   `json:"bom"`
+	Delimiter string `json:"delimiter"`
+}
+
+func toDialectDTO(d domain.Dialect) *dialectDTO {
+	if d.IsZero() {
+		return nil
+	}
+	return &dialectDTO{Encoding: d.Encoding, BOM: d.BOM, Delimiter: string(d.Delimiter)}
+}
@@ -0,0 +1,66 @@
+package httpapi
+
+import (
+	"net/http"
+
+	"stori-challenge/internal/core/ports/in"
+
+	"go.uber.org/zap"
+)
+
+// Handler expone la API como http.Handler; Handle la adapta a API Gateway v2
+// para que las mismas rutas sirvan en Lambda y en un servidor net/http.
+type Handler struct {
+	upload  in.UploadUseCase
+	history in.HistoryQueryUseCase
+	preview in.SummaryPreviewUseCase
+	log     *zap.Logger
+	mux     *http.ServeMux
+
+	process in.SummaryUseCase
+}
+
+type Option func(*Handler)
+
+// WithUploadProcessing procesa cada archivo subido en la misma petición. Sirve
+// donde no hay notificaciones de S3 que lo disparen, como cmd/server.
+func WithUploadProcessing(summary in.SummaryUseCase) Option {
+	return func(h *Handler) {
+		h.process = summary
+	}
+}
+
+func NewHandler(
+	upload in.UploadUseCase,
+	history in.HistoryQueryUseCase,
+	preview in.SummaryPreviewUseCase,
+	log *zap.Logger,
+	opts ...Option,
+) *Handler {
+	h := &Handler{
+		upload:  upload,
+		history: history,
+		preview: preview,
+		log:     log,
+		mux:     http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("POST /upload", h.handleUpload)
+	h.mux.HandleFunc("POST /summaries/preview", h.handlePreview)
+	h.mux.HandleFunc("GET /summaries", h.handleLatestSummary)
+	h.mux.HandleFunc("GET /summaries/{id}", h.handleGetSummary)
+	h.mux.HandleFunc("GET /transactions", h.handleListTransactions)
+	h.mux.HandleFunc("GET /runs/{id}", h.handleGetRun)
+	h.mux.HandleFunc("/", func(w http.ResponseWriter, _ *http.Request) {
+		writeError(w, http.StatusNotFound, "ruta no encontrada")
+	})
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
@@ -0,0 +1,238 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+const sampleCSV = "Id,Date,Transaction\n0,7/15,+60.5\n1,7/28,-10.3\n"
+
+type fakeUpload struct {
+	result domain.UploadResult
+	err    error
+
+	called       bool
+	gotAccountID string
+	gotContent   string
+}
+
+func (f *fakeUpload) UploadTransactions(_ context.Context, accountID string, content []byte) (domain.UploadResult, error) {
+	f.called = true
+	f.gotAccountID = accountID
+	f.gotContent = string(content)
+	return f.result, f.err
+}
+
+func newTestHandler(upload *fakeUpload) *Handler {
+	return NewHandler(upload, &fakeHistory{}, &fakePreview{}, zap.NewNop())
+}
+
+func multipartBody(t *testing.T, fields map[string]string, file string) (string, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatalf("WriteField: %v", err)
+		}
+	}
+	fw, err := w.CreateFormFile("file", "txns.csv")
+	if err != nil {
+		t.Fatalf("CreateFormFile: %v", err)
+	}
+	fmt.Fprint(fw, file)
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	return buf.String(), w.FormDataContentType()
+}
+
+func TestHandler_Upload_Multipart(t *testing.T) {
+	upload := &fakeUpload{result: domain.UploadResult{
+		Bucket: "bucket", ObjectKey: "input/acc-1/id.csv", RunID: 7, TransactionsCount: 2,
+	}}
+	h := newTestHandler(upload)
+
+	body, contentType := multipartBody(t, map[string]string{"account_id": "acc-1"}, sampleCSV)
+	resp, err := h.Handle(context.Background(), events.APIGatewayV2HTTPRequest{
+		RouteKey:        "POST /upload",
+		Headers:         map[string]string{"content-type": contentType},
+		Body:            base64.StdEncoding.EncodeToString([]byte(body)),
+		IsBase64Encoded: true,
+	})
+	if err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("StatusCode = %d, body = %s", resp.StatusCode, resp.Body)
+	}
+	if upload.gotContent != sampleCSV || upload.gotAccountID != "acc-1" {
+		t.Errorf("upload llamado con account=%q content=%q", upload.gotAccountID, upload.gotContent)
+	}
+
+	var got uploadDTO
+	if err := json.Unmarshal([]byte(resp.Body), &got); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if got.ObjectKey != "input/acc-1/id.csv" || got.RunID != 7 || got.TransactionsCount != 2 {
+		t.Errorf("response = %+v", got)
+	}
+}
+
+func TestHandler_Upload_RawBodyWithDefaultRoute(t *testing.T) {
+	upload := &fakeUpload{}
+	h := newTestHandler(upload)
+
+	req := events.APIGatewayV2HTTPRequest{
+		RouteKey:              "$default",
+		RawPath:               "/upload",
+		Headers:               map[string]string{"Content-Type": "text/csv"},
+		QueryStringParameters: map[string]string{"account_id": "acc-9"},
+		Body:                  sampleCSV,
+	}
+	req.RequestContext.HTTP.Method = "POST"
+
+	resp, _ := h.Handle(context.Background(), req)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("StatusCode = %d, body = %s", resp.StatusCode, resp.Body)
+	}
+	if upload.gotContent != sampleCSV || upload.gotAccountID != "acc-9" {
+		t.Errorf("upload llamado con account=%q content=%q", upload.gotAccountID, upload.gotContent)
+	}
+}
+
+func TestHandler_Upload_Errors(t *testing.T) {
+	cases := []struct {
+		name       string
+		req        events.APIGatewayV2HTTPRequest
+		uploadErr  error
+		wantStatus int
+		wantCalled bool
+	}{
+		{
+			name:       "cuerpo vacío",
+			req:        events.APIGatewayV2HTTPRequest{RouteKey: "POST /upload"},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "base64 inválido",
+			req:        events.APIGatewayV2HTTPRequest{RouteKey: "POST /upload", Body: "%%%", IsBase64Encoded: true},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name: "multipart sin archivo",
+			req: events.APIGatewayV2HTTPRequest{
+				RouteKey: "POST /upload",
+				Headers:  map[string]string{"content-type": "multipart/form-data; boundary=xyz"},
+				Body:     "--xyz--\r\n",
+			},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "demasiado grande",
+			req:        events.APIGatewayV2HTTPRequest{RouteKey: "POST /upload", Body: strings.Repeat("a", maxUploadBytes+1)},
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "archivo inválido",
+			req:        events.APIGatewayV2HTTPRequest{RouteKey: "POST /upload", Body: "basura"},
+			uploadErr:  fmt.Errorf("%w: sin transacciones", domain.ErrInvalidUpload),
+			wantStatus: http.StatusBadRequest,
+			wantCalled: true,
+		},
+		{
+			name:       "error interno",
+			req:        events.APIGatewayV2HTTPRequest{RouteKey: "POST /upload", Body: sampleCSV},
+			uploadErr:  errors.New("falló S3"),
+			wantStatus: http.StatusInternalServerError,
+			wantCalled: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			upload := &fakeUpload{err: tc.uploadErr}
+			resp, err := newTestHandler(upload).Handle(context.Background(), tc.req)
+			if err != nil {
+				t.Fatalf("Handle returned error: %v", err)
+			}
+			if resp.StatusCode != tc.wantStatus {
+				t.Errorf("StatusCode = %d, want %d (body %s)", resp.StatusCode, tc.wantStatus, resp.Body)
+			}
+			if upload.called != tc.wantCalled {
+				t.Errorf("upload called = %v, want %v", upload.called, tc.wantCalled)
+			}
+			if !strings.Contains(resp.Body, `"error"`) {
+				t.Errorf("body = %s, want JSON error", resp.Body)
+			}
+		})
+	}
+}
+
+type fakeProcessor struct {
+	err  error
+	objs []domain.ObjectRef
+}
+
+func (f *fakeProcessor) ProcessTransactionsFromObject(_ context.Context, obj domain.ObjectRef) error {
+	f.objs = append(f.objs, obj)
+	return f.err
+}
+
+func TestHandler_Upload_ProcessesWhenConfigured(t *testing.T) {
+	result := domain.UploadResult{Bucket: "bucket", ObjectKey: "input/acc-1/id.csv", RunID: 7, TransactionsCount: 2}
+
+	for _, processErr := range []error{nil, errors.New("falló la DB")} {
+		processor := &fakeProcessor{err: processErr}
+		h := NewHandler(&fakeUpload{result: result}, &fakeHistory{}, &fakePreview{}, zap.NewNop(),
+			WithUploadProcessing(processor))
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(sampleCSV)))
+
+		// La subida ya quedó guardada: el resultado del proceso está en la corrida.
+		if rec.Code != http.StatusAccepted {
+			t.Errorf("process err %v: status = %d, want 202 (body %s)", processErr, rec.Code, rec.Body)
+		}
+		want := []domain.ObjectRef{{Bucket: "bucket", Key: "input/acc-1/id.csv"}}
+		if !reflect.DeepEqual(processor.objs, want) {
+			t.Errorf("processed = %+v, want %+v", processor.objs, want)
+		}
+	}
+}
+
+func TestHandler_Upload_FailedUploadIsNotProcessed(t *testing.T) {
+	processor := &fakeProcessor{}
+	h := NewHandler(&fakeUpload{err: fmt.Errorf("%w: vacío", domain.ErrInvalidUpload)}, &fakeHistory{}, &fakePreview{}, zap.NewNop(),
+		WithUploadProcessing(processor))
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader("basura")))
+
+	if rec.Code != http.StatusBadRequest || len(processor.objs) != 0 {
+		t.Errorf("status = %d, processed = %+v; want 400 and nothing processed", rec.Code, processor.objs)
+	}
+}
+
+func TestHandler_UnknownRoute(t *testing.T) {
+	resp, _ := newTestHandler(&fakeUpload{}).Handle(context.Background(), events.APIGatewayV2HTTPRequest{RouteKey: "GET /nope"})
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("StatusCode = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,78 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type fakePreview struct {
+	result domain.SummaryPreview
+	err    error
+
+	gotAccountID string
+	gotContent   string
+}
+
+func (f *fakePreview) PreviewSummary(_ context.Context, accountID string, content []byte) (domain.SummaryPreview, error) {
+	f.gotAccountID = accountID
+	f.gotContent = string(content)
+	return f.result, f.err
+}
+
+func TestHandler_Preview(t *testing.T) {
+	preview := &fakePreview{result: domain.SummaryPreview{
+		Summary: domain.AccountSummary{
+			AccountID:    "acc-1",
+			TotalBalance: decimal.RequireFromString("50.2"),
+			ByMonth:      []domain.MonthlySummary{{MonthName: "2021-07", TransactionsCount: 2}},
+		},
+		Email: domain.RenderedEmail{Subject: "Stori", HTML: "<html></html>", Plain: "Total balance"},
+	}}
+	h := NewHandler(&fakeUpload{}, &fakeHistory{}, preview, zap.NewNop())
+
+	body, contentType := multipartBody(t, map[string]string{"account_id": "acc-1"}, sampleCSV)
+	req := httptest.NewRequest(http.MethodPost, "/summaries/preview", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if preview.gotContent != sampleCSV || preview.gotAccountID != "acc-1" {
+		t.Errorf("preview called with account=%q content=%q", preview.gotAccountID, preview.gotContent)
+	}
+
+	var got previewDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.Summary.AccountID != "acc-1" || len(got.Summary.Months) != 1 || !got.Summary.TotalBalance.Equal(decimal.RequireFromString("50.2")) {
+		t.Errorf("summary = %+v", got.Summary)
+	}
+	if got.Email.HTML != "<html></html>" || got.Email.Plain != "Total balance" || got.Email.Subject != "Stori" {
+		t.Errorf("email = %+v", got.Email)
+	}
+}
+
+func TestHandler_Preview_InvalidFile(t *testing.T) {
+	preview := &fakePreview{err: fmt.Errorf("%w: sin transacciones", domain.ErrInvalidUpload)}
+	h := NewHandler(&fakeUpload{}, &fakeHistory{}, preview, zap.NewNop())
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/summaries/preview", strings.NewReader("basura")))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,125 @@
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func (h *Handler) handleGetSummary(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	summary, err := h.history.GetSummary(r.Context(), id)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSummaryDTO(summary))
+}
+
+// handleLatestSummary busca por objeto (bucket + key) o, si no vienen, por
+// account_id.
+func (h *Handler) handleLatestSummary(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	bucket, key, accountID := q.Get("bucket"), q.Get("key"), q.Get("account_id")
+
+	var (
+		summary domain.StoredSummary
+		err     error
+	)
+	switch {
+	case bucket != "" || key != "":
+		summary, err = h.history.GetLatestSummaryByObject(r.Context(), bucket, key)
+	case accountID != "":
+		summary, err = h.history.GetLatestSummaryByAccount(r.Context(), accountID)
+	default:
+		err = fmt.Errorf("%w: se requiere bucket y key, o account_id", domain.ErrInvalidQuery)
+	}
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toSummaryDTO(summary))
+}
+
+func (h *Handler) handleListTransactions(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+
+	from, err := parseDateParam("from", q.Get("from"))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	to, err := parseDateParam("to", q.Get("to"))
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	limit := 0
+	if raw := q.Get("limit"); raw != "" {
+		limit, err = strconv.Atoi(raw)
+		if err != nil {
+			h.writeDomainError(w, fmt.Errorf("%w: limit %q", domain.ErrInvalidQuery, raw))
+			return
+		}
+	}
+
+	page, err := h.history.ListTransactions(r.Context(), domain.TransactionQuery{
+		AccountID: q.Get("account_id"),
+		From:      from,
+		To:        to,
+		Cursor:    q.Get("cursor"),
+		Limit:     limit,
+	})
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toTransactionPageDTO(page))
+}
+
+func (h *Handler) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	id, err := pathID(r)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	run, err := h.history.GetProcessingRun(r.Context(), id)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toRunDTO(run))
+}
+
+func pathID(r *http.Request) (uint64, error) {
+	raw := r.PathValue("id")
+	id, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%w: id %q", domain.ErrInvalidQuery, raw)
+	}
+	return id, nil
+}
+
+// parseDateParam acepta "2006-01-02" (medianoche UTC) o RFC 3339.
+func parseDateParam(name, raw string) (time.Time, error) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s %q no es una fecha válida", domain.ErrInvalidQuery, name, raw)
+	}
+	return t, nil
+}
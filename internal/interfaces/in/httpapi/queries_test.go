@@ -0,0 +1,239 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/shopspring/decimal"
+	"go.uber.org/zap"
+)
+
+type fakeHistory struct {
+	summary domain.StoredSummary
+	page    domain.TransactionPage
+	run     domain.ProcessingRun
+	err     error
+
+	gotID        uint64
+	gotBucket    string
+	gotKey       string
+	gotAccountID string
+	gotTxQuery   domain.TransactionQuery
+}
+
+func (f *fakeHistory) GetSummary(_ context.Context, id uint64) (domain.StoredSummary, error) {
+	f.gotID = id
+	return f.summary, f.err
+}
+
+func (f *fakeHistory) GetLatestSummaryByObject(_ context.Context, bucket, key string) (domain.StoredSummary, error) {
+	f.gotBucket, f.gotKey = bucket, key
+	return f.summary, f.err
+}
+
+func (f *fakeHistory) GetLatestSummaryByAccount(_ context.Context, accountID string) (domain.StoredSummary, error) {
+	f.gotAccountID = accountID
+	return f.summary, f.err
+}
+
+func (f *fakeHistory) ListTransactions(_ context.Context, q domain.TransactionQuery) (domain.TransactionPage, error) {
+	f.gotTxQuery = q
+	return f.page, f.err
+}
+
+func (f *fakeHistory) AggregateByMonth(context.Context, string, time.Time, time.Time) ([]domain.MonthlySummary, error) {
+	return nil, f.err
+}
+
+func (f *fakeHistory) GetProcessingRun(_ context.Context, id uint64) (domain.ProcessingRun, error) {
+	f.gotID = id
+	return f.run, f.err
+}
+
+func (f *fakeHistory) ListProcessingRuns(context.Context, domain.RunQuery) ([]domain.ProcessingRun, error) {
+	return nil, f.err
+}
+
+func serve(t *testing.T, history *fakeHistory, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	h := NewHandler(&fakeUpload{}, history, &fakePreview{}, zap.NewNop())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestHandler_GetSummary(t *testing.T) {
+	history := &fakeHistory{summary: domain.StoredSummary{
+		ID:        5,
+		Bucket:    "bucket",
+		ObjectKey: "input/acc-1/a.csv",
+		Summary: domain.AccountSummary{
+			AccountID:    "acc-1",
+			TotalBalance: decimal.RequireFromString("39.74"),
+			ByMonth: []domain.MonthlySummary{{
+				MonthName:          "2021-07",
+				TransactionsCount:  2,
+				AverageDebitAmount: decimal.RequireFromString("-15.383333"),
+				VsPreviousMonth:    &domain.MonthlyDelta{BaselineMonth: "2021-06", TransactionsCount: 1},
+			}},
+		},
+	}}
+
+	rec := serve(t, history, "/summaries/5")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	if history.gotID != 5 {
+		t.Errorf("GetSummary called with id %d, want 5", history.gotID)
+	}
+
+	var got summaryDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.AccountID != "acc-1" || len(got.Months) != 1 {
+		t.Fatalf("summary = %+v", got)
+	}
+	m := got.Months[0]
+	if !m.AverageDebitAmount.Equal(decimal.RequireFromString("-15.38")) {
+		t.Errorf("AverageDebitAmount = %s, want rounded -15.38", m.AverageDebitAmount)
+	}
+	if m.VsPreviousMonth == nil || m.VsPreviousMonth.BaselineMonth != "2021-06" || m.VsPreviousYear != nil {
+		t.Errorf("deltas = %+v / %+v", m.VsPreviousMonth, m.VsPreviousYear)
+	}
+}
+
+func TestHandler_LatestSummary_Lookups(t *testing.T) {
+	history := &fakeHistory{}
+	if rec := serve(t, history, "/summaries?bucket=b&key=input%2Fa.csv"); rec.Code != http.StatusOK {
+		t.Fatalf("by object status = %d", rec.Code)
+	}
+	if history.gotBucket != "b" || history.gotKey != "input/a.csv" {
+		t.Errorf("by object called with %q/%q", history.gotBucket, history.gotKey)
+	}
+
+	if rec := serve(t, history, "/summaries?account_id=acc-1"); rec.Code != http.StatusOK {
+		t.Fatalf("by account status = %d", rec.Code)
+	}
+	if history.gotAccountID != "acc-1" {
+		t.Errorf("by account called with %q", history.gotAccountID)
+	}
+
+	if rec := serve(t, history, "/summaries"); rec.Code != http.StatusBadRequest {
+		t.Errorf("without params status = %d, want 400", rec.Code)
+	}
+}
+
+func TestHandler_ListTransactions(t *testing.T) {
+	history := &fakeHistory{page: domain.TransactionPage{
+		Items: []domain.TransactionRecord{{
+			Transaction: domain.Transaction{Amount: decimal.RequireFromString("60.5")},
+			ID:          1,
+			AccountID:   "acc-1",
+		}},
+		NextCursor: "next",
+	}}
+
+	rec := serve(t, history, "/transactions?account_id=acc-1&from=2021-07-01&to=2021-08-01T00:00:00Z&cursor=abc&limit=20")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	q := history.gotTxQuery
+	if q.AccountID != "acc-1" || q.Cursor != "abc" || q.Limit != 20 {
+		t.Errorf("query = %+v", q)
+	}
+	if !q.From.Equal(time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)) || !q.To.Equal(time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("range = %v - %v", q.From, q.To)
+	}
+
+	var got transactionPageDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(got.Items) != 1 || got.NextCursor != "next" || got.Items[0].ID != 1 {
+		t.Errorf("page = %+v", got)
+	}
+}
+
+func TestHandler_ListTransactions_BadParams(t *testing.T) {
+	for _, target := range []string{"/transactions?from=julio", "/transactions?to=2021-13-01", "/transactions?limit=x"} {
+		if rec := serve(t, &fakeHistory{}, target); rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", target, rec.Code)
+		}
+	}
+}
+
+func TestHandler_GetRun(t *testing.T) {
+	history := &fakeHistory{run: domain.ProcessingRun{ID: 9, Status: domain.RunStatusFailed, Error: "boom"}}
+
+	rec := serve(t, history, "/runs/9")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var got runDTO
+	if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if got.ID != 9 || got.Status != "failed" || got.Error != "boom" {
+		t.Errorf("run = %+v", got)
+	}
+}
+
+func TestHandler_ErrorMapping(t *testing.T) {
+	cases := []struct {
+		target string
+		err    error
+		want   int
+	}{
+		{"/runs/abc", nil, http.StatusBadRequest},
+		{"/runs/0", nil, http.StatusBadRequest},
+		{"/runs/1", domain.ErrNotFound, http.StatusNotFound},
+		{"/summaries/1", domain.ErrNotFound, http.StatusNotFound},
+		{"/transactions?cursor=bad", domain.ErrInvalidCursor, http.StatusBadRequest},
+		{"/transactions", domain.ErrInvalidQuery, http.StatusBadRequest},
+		{"/summaries/1", errors.New("db caída"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		rec := serve(t, &fakeHistory{err: tc.err}, tc.target)
+		if rec.Code != tc.want {
+			t.Errorf("%s (%v): status = %d, want %d", tc.target, tc.err, rec.Code, tc.want)
+		}
+		if rec.Header().Get("Content-Type") != "application/json" {
+			t.Errorf("%s: Content-Type = %q", tc.target, rec.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestHandler_APIGateway_PathParameters(t *testing.T) {
+	history := &fakeHistory{run: domain.ProcessingRun{ID: 3}}
+	h := NewHandler(&fakeUpload{}, history, &fakePreview{}, zap.NewNop())
+
+	req := events.APIGatewayV2HTTPRequest{
+		RouteKey:       "GET /runs/{id}",
+		RawPath:        "/runs/3",
+		RawQueryString: "",
+	}
+	req.RequestContext.HTTP.Method = http.MethodGet
+
+	resp, err := h.Handle(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || history.gotID != 3 {
+		t.Errorf("status = %d, id = %d, body = %s", resp.StatusCode, history.gotID, resp.Body)
+	}
+	if resp.Headers["Content-Type"] != "application/json" {
+		t.Errorf("headers = %v", resp.Headers)
+	}
+}
@@ -0,0 +1,56 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		status = http.StatusInternalServerError
+		payload = []byte(`{"error":"error serializando respuesta"}`)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(payload)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, errorBody{Error: msg})
+}
+
+// writeDomainError traduce los errores de dominio a status HTTP; lo que no se
+// reconoce se registra y se responde como 500 sin filtrar el detalle.
+func (h *Handler) writeDomainError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		writeError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidQuery),
+		errors.Is(err, domain.ErrInvalidCursor),
+		errors.Is(err, domain.ErrInvalidUpload):
+		writeError(w, http.StatusBadRequest, err.Error())
+	default:
+		h.log.Error("error atendiendo petición HTTP", zap.Error(err))
+		writeError(w, http.StatusInternalServerError, "error interno")
+	}
+}
+
+func errorResponse(status int, msg string) events.APIGatewayV2HTTPResponse {
+	payload, _ := json.Marshal(errorBody{Error: msg})
+	return events.APIGatewayV2HTTPResponse{
+		StatusCode: status,
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		Body:       string(payload),
+	}
+}
@@ -0,0 +1,152 @@
+package httpapi
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"stori-challenge/internal/core/domain"
+
+	"go.uber.org/zap"
+)
+
+// maxUploadBytes queda por debajo del límite de payload síncrono de Lambda.
+const maxUploadBytes = 5 << 20
+
+var errBodyTooLarge = errors.New("el archivo supera el tamaño máximo permitido")
+
+func (h *Handler) handleUpload(w http.ResponseWriter, r *http.Request) {
+	content, accountID, err := readUpload(r)
+	if errors.Is(err, errBodyTooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if qs := r.URL.Query().Get("account_id"); qs != "" {
+		accountID = qs
+	}
+
+	result, err := h.upload.UploadTransactions(r.Context(), accountID, content)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+
+	h.log.Info("archivo de transacciones subido",
+		zap.String("bucket", result.Bucket),
+		zap.String("key", result.ObjectKey),
+		zap.Uint64("run_id", result.RunID),
+	)
+
+	if h.process != nil {
+		// El archivo ya quedó guardado y la corrida registra el resultado, que
+		// se consulta en GET /runs/{id}; un fallo no invalida la subida.
+		obj := domain.ObjectRef{Bucket: result.Bucket, Key: result.ObjectKey}
+		if err := h.process.ProcessTransactionsFromObject(r.Context(), obj); err != nil {
+			h.log.Error("error procesando archivo subido",
+				zap.String("bucket", result.Bucket),
+				zap.String("key", result.ObjectKey),
+				zap.Uint64("run_id", result.RunID),
+				zap.Error(err),
+			)
+		}
+	}
+
+	writeJSON(w, http.StatusAccepted, toUploadDTO(result))
+}
+
+func (h *Handler) handlePreview(w http.ResponseWriter, r *http.Request) {
+	content, accountID, err := readUpload(r)
+	if errors.Is(err, errBodyTooLarge) {
+		writeError(w, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if qs := r.URL.Query().Get("account_id"); qs != "" {
+		accountID = qs
+	}
+
+	preview, err := h.preview.PreviewSummary(r.Context(), accountID, content)
+	if err != nil {
+		h.writeDomainError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toPreviewDTO(preview))
+}
+
+// readUpload acepta multipart/form-data (campo "file" y opcionalmente
+// "account_id") o el CSV directo en el cuerpo.
+func readUpload(r *http.Request) ([]byte, string, error) {
+	mediaType, params, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType == "multipart/form-data" {
+		return readMultipart(r.Body, params["boundary"])
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxUploadBytes+1))
+	if err != nil {
+		return nil, "", err
+	}
+	if len(body) > maxUploadBytes {
+		return nil, "", errBodyTooLarge
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil, "", errors.New("el cuerpo de la petición está vacío")
+	}
+	return body, "", nil
+}
+
+func readMultipart(body io.Reader, boundary string) ([]byte, string, error) {
+	if boundary == "" {
+		return nil, "", errors.New("multipart sin boundary")
+	}
+
+	var (
+		content   []byte
+		accountID string
+	)
+	mr := multipart.NewReader(body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("multipart inválido: %w", err)
+		}
+
+		switch {
+		case part.FormName() == "account_id":
+			v, err := io.ReadAll(io.LimitReader(part, 256))
+			if err != nil {
+				return nil, "", err
+			}
+			accountID = strings.TrimSpace(string(v))
+		case content == nil && (part.FormName() == "file" || part.FileName() != ""):
+			data, err := io.ReadAll(io.LimitReader(part, maxUploadBytes+1))
+			if err != nil {
+				return nil, "", err
+			}
+			if len(data) > maxUploadBytes {
+				return nil, "", errBodyTooLarge
+			}
+			content = data
+		}
+		_ = part.Close()
+	}
+
+	if content == nil {
+		return nil, "", errors.New(`falta el campo "file" en el formulario`)
+	}
+	return content, accountID, nil
+}
@@ -0,0 +1,166 @@
+package s3event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/in"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultConcurrency = 4
+
+// Handler procesa cada registro de un S3Event con el caso de uso de resumen.
+// Lo comparten la Lambda (cmd/lambda_api) y el webhook del servidor local.
+type Handler struct {
+	summary in.SummaryUseCase
+	log     *zap.Logger
+
+	concurrency    int
+	pendingRetries bool
+}
+
+type Option func(*Handler)
+
+// WithConcurrency limita cuántos registros del mismo evento se procesan a la vez.
+func WithConcurrency(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.concurrency = n
+		}
+	}
+}
+
+// WithPendingRetries indica que quien invoca reintenta los eventos fallidos
+// (invocación asíncrona de Lambda). Como el handler no sabe cuál es el último
+// intento, los fallos transitorios nunca mandan el objeto a cuarentena.
+func WithPendingRetries() Option {
+	return func(h *Handler) {
+		h.pendingRetries = true
+	}
+}
+
+func NewHandler(summary in.SummaryUseCase, log *zap.Logger, opts ...Option) *Handler {
+	h := &Handler{summary: summary, log: log, concurrency: defaultConcurrency}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+type RecordResult struct {
+	Bucket string
+	Key    string
+	Err    error
+}
+
+// Result guarda el resultado de cada registro en el orden del evento.
+type Result struct {
+	Records []RecordResult
+}
+
+func (r Result) Failed() []RecordResult {
+	var failed []RecordResult
+	for _, rec := range r.Records {
+		if rec.Err != nil {
+			failed = append(failed, rec)
+		}
+	}
+	return failed
+}
+
+// Err combina solo los errores de los registros fallidos, cada uno con su key.
+func (r Result) Err() error {
+	var errs []error
+	for _, rec := range r.Failed() {
+		errs = append(errs, fmt.Errorf("%s/%s: %w", rec.Bucket, rec.Key, rec.Err))
+	}
+	return errors.Join(errs...)
+}
+
+func (h *Handler) processRecord(ctx context.Context, rec events.S3EventRecord) error {
+	obj, err := ObjectRefFromRecord(rec)
+	if err != nil {
+		h.log.Error("registro S3 inválido", zap.Error(err))
+		return err
+	}
+	if domain.IsSettledKey(obj.Key) {
+		// Lo movió el propio pipeline a processed/ o quarantine/.
+		h.log.Info("objeto ya archivado, se ignora",
+			zap.String("bucket", obj.Bucket),
+			zap.String("key", obj.Key),
+		)
+		return nil
+	}
+
+	h.log.Info("procesando objeto S3",
+		zap.String("bucket", obj.Bucket),
+		zap.String("key", obj.Key),
+		zap.String("version_id", obj.VersionID),
+	)
+
+	if h.pendingRetries {
+		ctx = domain.WithPendingRetry(ctx)
+	}
+	if err := h.summary.ProcessTransactionsFromObject(ctx, obj); err != nil {
+		h.log.Error("error procesando transacciones",
+			zap.String("bucket", obj.Bucket),
+			zap.String("key", obj.Key),
+			zap.Error(err),
+		)
+		return err
+	}
+	return nil
+}
+
+// Process procesa cada registro de forma independiente: un fallo no cancela
+// a los demás, que terminarían a medias y se duplicarían en el reintento.
+func (h *Handler) Process(ctx context.Context, evt events.S3Event) Result {
+	res := Result{Records: make([]RecordResult, len(evt.Records))}
+
+	var g errgroup.Group
+	g.SetLimit(h.concurrency)
+
+	for i, rec := range evt.Records {
+		res.Records[i] = RecordResult{Bucket: rec.S3.Bucket.Name, Key: rec.S3.Object.Key}
+		if obj, err := ObjectRefFromRecord(rec); err == nil {
+			res.Records[i].Key = obj.Key
+		}
+		g.Go(func() error {
+			res.Records[i].Err = h.processRecord(ctx, rec)
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	return res
+}
+
+func (h *Handler) Handle(ctx context.Context, evt events.S3Event) error {
+	h.log.Info("evento S3 recibido",
+		zap.Int("records", len(evt.Records)),
+	)
+
+	res := h.Process(ctx, evt)
+
+	if failed := res.Failed(); len(failed) > 0 {
+		keys := make([]string, len(failed))
+		for i, f := range failed {
+			keys[i] = f.Key
+		}
+		h.log.Error("falló el procesamiento de objetos S3",
+			zap.Int("failed", len(failed)),
+			zap.Int("records", len(res.Records)),
+			zap.Strings("keys", keys),
+		)
+		return res.Err()
+	}
+
+	h.log.Info("evento S3 procesado correctamente")
+	return nil
+}
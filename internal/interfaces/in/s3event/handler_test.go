@@ -0,0 +1,218 @@
+package s3event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+type fakeSummaryUseCase struct {
+	mu    sync.Mutex
+	errs  map[string]error
+	keys  []string
+	objs  []domain.ObjectRef
+	delay time.Duration
+
+	running   int
+	maxActive int
+	cancelled []string
+	pending   []string
+}
+
+func (f *fakeSummaryUseCase) ProcessTransactionsFromObject(ctx context.Context, obj domain.ObjectRef) error {
+	key := obj.Key
+
+	f.mu.Lock()
+	f.keys = append(f.keys, key)
+	f.objs = append(f.objs, obj)
+	if domain.HasPendingRetry(ctx) {
+		f.pending = append(f.pending, key)
+	}
+	f.running++
+	f.maxActive = max(f.maxActive, f.running)
+	err := f.errs[key]
+	f.mu.Unlock()
+
+	if err == nil {
+		time.Sleep(f.delay)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.running--
+	if ctx.Err() != nil {
+		f.cancelled = append(f.cancelled, key)
+	}
+	return err
+}
+
+func s3Event(keys ...string) events.S3Event {
+	var evt events.S3Event
+	for _, k := range keys {
+		var rec events.S3EventRecord
+		rec.S3.Bucket.Name = "bucket"
+		rec.S3.Object.Key = k
+		evt.Records = append(evt.Records, rec)
+	}
+	return evt
+}
+
+func TestHandler_Handle_ProcessesAllRecords(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	h := NewHandler(uc, zap.NewNop())
+
+	if err := h.Handle(context.Background(), s3Event("a.csv", "b.csv", "c.csv")); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if len(uc.keys) != 3 {
+		t.Errorf("processed %v, want 3 keys", uc.keys)
+	}
+}
+
+func TestHandler_Handle_PropagatesError(t *testing.T) {
+	wantErr := errors.New("falló")
+	uc := &fakeSummaryUseCase{errs: map[string]error{"a.csv": wantErr}}
+	h := NewHandler(uc, zap.NewNop())
+
+	if err := h.Handle(context.Background(), s3Event("a.csv")); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestHandler_Handle_FailureDoesNotCancelSiblings(t *testing.T) {
+	uc := &fakeSummaryUseCase{errs: map[string]error{"b.csv": errors.New("falló")}, delay: 20 * time.Millisecond}
+	h := NewHandler(uc, zap.NewNop())
+
+	err := h.Handle(context.Background(), s3Event("a.csv", "b.csv", "c.csv"))
+	if err == nil {
+		t.Fatal("expected error for b.csv")
+	}
+	if len(uc.cancelled) != 0 {
+		t.Errorf("records cancelled by sibling failure: %v", uc.cancelled)
+	}
+	if msg := err.Error(); !strings.Contains(msg, "bucket/b.csv: falló") || strings.Contains(msg, "a.csv") || strings.Contains(msg, "c.csv") {
+		t.Errorf("error should only mention the failed key, got %q", msg)
+	}
+}
+
+func TestHandler_Process_CollectsResultsInOrder(t *testing.T) {
+	wantErr := errors.New("falló")
+	uc := &fakeSummaryUseCase{errs: map[string]error{"a.csv": wantErr, "c.csv": wantErr}}
+	res := NewHandler(uc, zap.NewNop()).Process(context.Background(), s3Event("a.csv", "b.csv", "c.csv"))
+
+	if len(res.Records) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(res.Records))
+	}
+	for i, key := range []string{"a.csv", "b.csv", "c.csv"} {
+		if res.Records[i].Key != key || res.Records[i].Bucket != "bucket" {
+			t.Errorf("record %d = %+v, want key %s", i, res.Records[i], key)
+		}
+	}
+	failed := res.Failed()
+	if len(failed) != 2 || failed[0].Key != "a.csv" || failed[1].Key != "c.csv" {
+		t.Errorf("Failed() = %+v", failed)
+	}
+	if !errors.Is(res.Err(), wantErr) {
+		t.Errorf("Err() = %v, want wrapping %v", res.Err(), wantErr)
+	}
+}
+
+func TestHandler_Handle_PendingRetries(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	if err := NewHandler(uc, zap.NewNop()).Handle(context.Background(), s3Event("a.csv")); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if len(uc.pending) != 0 {
+		t.Errorf("without retries no call should be marked, got %v", uc.pending)
+	}
+
+	if err := NewHandler(uc, zap.NewNop(), WithPendingRetries()).Handle(context.Background(), s3Event("b.csv")); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if len(uc.pending) != 1 || uc.pending[0] != "b.csv" {
+		t.Errorf("pending = %v, want [b.csv]", uc.pending)
+	}
+}
+
+func TestHandler_Handle_IgnoresSettledObjects(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	h := NewHandler(uc, zap.NewNop())
+
+	err := h.Handle(context.Background(), s3Event("processed/input/a.csv", "quarantine/b.csv", "input/c.csv"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(uc.keys) != 1 || uc.keys[0] != "input/c.csv" {
+		t.Errorf("only input/c.csv should be processed, got %v", uc.keys)
+	}
+}
+
+func TestHandler_Process_RespectsConcurrency(t *testing.T) {
+	uc := &fakeSummaryUseCase{delay: 10 * time.Millisecond}
+	h := NewHandler(uc, zap.NewNop(), WithConcurrency(2))
+
+	if err := h.Process(context.Background(), s3Event("a", "b", "c", "d", "e", "f")).Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if uc.maxActive > 2 {
+		t.Errorf("max concurrent records = %d, want <= 2", uc.maxActive)
+	}
+}
+
+func TestHandler_ServeHTTP_ReportsFailedKeys(t *testing.T) {
+	uc := &fakeSummaryUseCase{errs: map[string]error{"b.csv": errors.New("boom")}}
+	body := `{"Records":[{"s3":{"bucket":{"name":"bucket"},"object":{"key":"a.csv"}}},{"s3":{"bucket":{"name":"bucket"},"object":{"key":"b.csv"}}}]}`
+
+	rec := httptest.NewRecorder()
+	NewHandler(uc, zap.NewNop()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events/s3", strings.NewReader(body)))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", rec.Code)
+	}
+	var resp webhookResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON body: %v", err)
+	}
+	if resp.Records != 2 || len(resp.Failed) != 1 || resp.Failed[0].Key != "b.csv" || resp.Failed[0].Error != "boom" {
+		t.Errorf("unexpected body: %+v", resp)
+	}
+}
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	body := `{"Records":[{"s3":{"bucket":{"name":"bucket"},"object":{"key":"input/acc-1/a.csv"}}}]}`
+
+	cases := []struct {
+		name string
+		body string
+		errs map[string]error
+		want int
+	}{
+		{"ok", body, nil, http.StatusOK},
+		{"json inválido", "{", nil, http.StatusBadRequest},
+		{"sin registros", `{"Records":[]}`, nil, http.StatusBadRequest},
+		{"falla el procesamiento", body, map[string]error{"input/acc-1/a.csv": errors.New("boom")}, http.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			uc := &fakeSummaryUseCase{errs: tc.errs}
+			rec := httptest.NewRecorder()
+			NewHandler(uc, zap.NewNop()).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events/s3", strings.NewReader(tc.body)))
+
+			if rec.Code != tc.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tc.want, rec.Body)
+			}
+		})
+	}
+}
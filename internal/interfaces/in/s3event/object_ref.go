@@ -0,0 +1,24 @@
+package s3event
+
+import (
+	"fmt"
+	"net/url"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ObjectRefFromRecord decodifica la key del evento: S3 la envía codificada
+// como formulario ("estado+de+cuenta.csv", "%C3%B1.csv").
+func ObjectRefFromRecord(rec events.S3EventRecord) (domain.ObjectRef, error) {
+	key, err := url.QueryUnescape(rec.S3.Object.Key)
+	if err != nil {
+		return domain.ObjectRef{}, fmt.Errorf("key %q mal codificada: %w", rec.S3.Object.Key, err)
+	}
+	return domain.ObjectRef{
+		Bucket:    rec.S3.Bucket.Name,
+		Key:       key,
+		VersionID: rec.S3.Object.VersionID,
+	}, nil
+}
@@ -0,0 +1,54 @@
+package s3event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+func s3Record(key, version string) events.S3EventRecord {
+	var rec events.S3EventRecord
+	rec.S3.Bucket.Name = "bucket"
+	rec.S3.Object.Key = key
+	rec.S3.Object.VersionID = version
+	return rec
+}
+
+func TestObjectRefFromRecord_DecodesKey(t *testing.T) {
+	cases := map[string]string{
+		"input/acc-1/estado+de+cuenta.csv": "input/acc-1/estado de cuenta.csv",
+		"input/acc-1/a%C3%B1o%202024.csv":  "input/acc-1/año 2024.csv",
+		"input/acc-1/a%2Bb.csv":            "input/acc-1/a+b.csv",
+		"input/acc-1/plain.csv":            "input/acc-1/plain.csv",
+	}
+
+	for raw, want := range cases {
+		obj, err := ObjectRefFromRecord(s3Record(raw, "v-1"))
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", raw, err)
+		}
+		if obj.Key != want || obj.Bucket != "bucket" || obj.VersionID != "v-1" {
+			t.Errorf("%s: got %+v, want key %q", raw, obj, want)
+		}
+	}
+}
+
+func TestObjectRefFromRecord_InvalidEncoding(t *testing.T) {
+	if _, err := ObjectRefFromRecord(s3Record("input/%zz.csv", "")); err == nil {
+		t.Fatal("expected error for malformed key")
+	}
+}
+
+func TestHandler_Handle_PassesDecodedKeyAndVersion(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	evt := events.S3Event{Records: []events.S3EventRecord{s3Record("estado+de+cuenta.csv", "v-7")}}
+
+	if err := NewHandler(uc, zap.NewNop()).Handle(context.Background(), evt); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if len(uc.objs) != 1 || uc.objs[0].Key != "estado de cuenta.csv" || uc.objs[0].VersionID != "v-7" {
+		t.Errorf("processed %+v", uc.objs)
+	}
+}
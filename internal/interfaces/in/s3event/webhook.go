@@ -0,0 +1,55 @@
+package s3event
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+const maxEventBytes = 1 << 20
+
+type webhookResponse struct {
+	Records int            `json:"records"`
+	Failed  []failedRecord `json:"failed,omitempty"`
+	Error   string         `json:"error,omitempty"`
+}
+
+type failedRecord struct {
+	Bucket string `json:"bucket"`
+	Key    string `json:"key"`
+	Error  string `json:"error"`
+}
+
+// ServeHTTP recibe un S3Event con el mismo JSON que entrega S3 a Lambda, para
+// disparar el pipeline sin LocalStack.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var evt events.S3Event
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxEventBytes)).Decode(&evt); err != nil {
+		writeJSON(w, http.StatusBadRequest, webhookResponse{Error: "evento S3 inválido"})
+		return
+	}
+	if len(evt.Records) == 0 {
+		writeJSON(w, http.StatusBadRequest, webhookResponse{Error: "el evento no trae registros"})
+		return
+	}
+
+	failed := h.Process(r.Context(), evt).Failed()
+	if len(failed) == 0 {
+		writeJSON(w, http.StatusOK, webhookResponse{Records: len(evt.Records)})
+		return
+	}
+
+	body := webhookResponse{Records: len(evt.Records), Error: "falló el procesamiento de algunos objetos"}
+	for _, f := range failed {
+		body.Failed = append(body.Failed, failedRecord{Bucket: f.Bucket, Key: f.Key, Error: f.Err.Error()})
+	}
+	writeJSON(w, http.StatusInternalServerError, body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body webhookResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
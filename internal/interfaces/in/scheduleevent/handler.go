@@ -0,0 +1,98 @@
+package scheduleevent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"stori-challenge/internal/core/ports/in"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+// Handler dispara los estados de cuenta mensuales desde una regla programada
+// de EventBridge. Por defecto cierra el mes anterior a la hora del evento.
+type Handler struct {
+	statements in.StatementUseCase
+	log        *zap.Logger
+
+	now func() time.Time
+}
+
+func NewHandler(statements in.StatementUseCase, log *zap.Logger) *Handler {
+	return &Handler{statements: statements, log: log, now: time.Now}
+}
+
+// Event es el evento de la regla. Period permite forzar el periodo: con un
+// Input constante en el target la Lambda recibe {"period": "2024-03"} en vez
+// del evento de EventBridge.
+type Event struct {
+	events.CloudWatchEvent
+	Period string `json:"period"`
+}
+
+// detail es la misma forma dentro de detail, para eventos publicados con
+// PutEvents.
+type detail struct {
+	Period string `json:"period"`
+}
+
+func (h *Handler) Handle(ctx context.Context, evt Event) error {
+	period, err := h.period(evt)
+	if err != nil {
+		return err
+	}
+
+	h.log.Info("generando estados de cuenta mensuales",
+		zap.String("period", period),
+		zap.String("event_id", evt.ID),
+	)
+
+	res, err := h.statements.SendMonthlyStatements(ctx, period)
+	if err != nil {
+		h.log.Error("error generando estados de cuenta", zap.String("period", period), zap.Error(err))
+		return err
+	}
+
+	h.log.Info("estados de cuenta procesados",
+		zap.String("period", period),
+		zap.Int("accounts", res.Accounts),
+		zap.Int("sent", res.Sent),
+		zap.Int("skipped", res.Skipped),
+		zap.Int("failed", res.Failed),
+	)
+
+	if res.Failed > 0 {
+		accounts := make([]string, len(res.Failures))
+		for i, f := range res.Failures {
+			accounts[i] = f.AccountID
+		}
+		return fmt.Errorf("falló el envío del periodo %s para: %s", period, strings.Join(accounts, ", "))
+	}
+	return nil
+}
+
+func (h *Handler) period(evt Event) (string, error) {
+	if evt.Period != "" {
+		return evt.Period, nil
+	}
+	if len(evt.Detail) > 0 {
+		var d detail
+		if err := json.Unmarshal(evt.Detail, &d); err != nil {
+			return "", fmt.Errorf("detail del evento inválido: %w", err)
+		}
+		if d.Period != "" {
+			return d.Period, nil
+		}
+	}
+
+	at := evt.Time
+	if at.IsZero() {
+		at = h.now()
+	}
+	at = at.UTC()
+	return time.Date(at.Year(), at.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -1, 0).Format("2006-01"), nil
+}
@@ -0,0 +1,116 @@
+package scheduleevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+type fakeStatements struct {
+	res domain.StatementRunResult
+	err error
+
+	gotPeriod string
+}
+
+func (f *fakeStatements) SendMonthlyStatements(_ context.Context, period string) (domain.StatementRunResult, error) {
+	f.gotPeriod = period
+	return f.res, f.err
+}
+
+func event(evt events.CloudWatchEvent) Event {
+	return Event{CloudWatchEvent: evt}
+}
+
+func TestHandler_Handle_UsesPreviousMonthOfEventTime(t *testing.T) {
+	cases := []struct {
+		at   time.Time
+		want string
+	}{
+		{time.Date(2024, 4, 1, 6, 0, 0, 0, time.UTC), "2024-03"},
+		{time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC), "2023-12"},
+		{time.Date(2024, 3, 31, 23, 0, 0, 0, time.FixedZone("", -6*3600)), "2024-03"},
+	}
+
+	for _, tc := range cases {
+		uc := &fakeStatements{}
+		if err := NewHandler(uc, zap.NewNop()).Handle(context.Background(), event(events.CloudWatchEvent{Time: tc.at})); err != nil {
+			t.Fatalf("Handle returned error: %v", err)
+		}
+		if uc.gotPeriod != tc.want {
+			t.Errorf("event at %s: period = %s, want %s", tc.at, uc.gotPeriod, tc.want)
+		}
+	}
+}
+
+func TestHandler_Handle_PeriodFromDetail(t *testing.T) {
+	uc := &fakeStatements{}
+	evt := event(events.CloudWatchEvent{
+		Time:   time.Date(2024, 4, 1, 6, 0, 0, 0, time.UTC),
+		Detail: json.RawMessage(`{"period":"2023-11"}`),
+	})
+
+	if err := NewHandler(uc, zap.NewNop()).Handle(context.Background(), evt); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if uc.gotPeriod != "2023-11" {
+		t.Errorf("period = %s, want 2023-11", uc.gotPeriod)
+	}
+}
+
+func TestHandler_Handle_PeriodFromRuleInput(t *testing.T) {
+	// Con Input en el target, la Lambda recibe solo el JSON configurado.
+	var evt Event
+	if err := json.Unmarshal([]byte(`{"period":"2024-03"}`), &evt); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	uc := &fakeStatements{}
+	if err := NewHandler(uc, zap.NewNop()).Handle(context.Background(), evt); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if uc.gotPeriod != "2024-03" {
+		t.Errorf("period = %s, want 2024-03", uc.gotPeriod)
+	}
+}
+
+func TestHandler_Handle_FallsBackToNow(t *testing.T) {
+	uc := &fakeStatements{}
+	h := NewHandler(uc, zap.NewNop())
+	h.now = func() time.Time { return time.Date(2024, 7, 3, 0, 0, 0, 0, time.UTC) }
+
+	if err := h.Handle(context.Background(), event(events.CloudWatchEvent{Detail: json.RawMessage(`{}`)})); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+	if uc.gotPeriod != "2024-06" {
+		t.Errorf("period = %s, want 2024-06", uc.gotPeriod)
+	}
+}
+
+func TestHandler_Handle_ReportsFailedAccounts(t *testing.T) {
+	uc := &fakeStatements{res: domain.StatementRunResult{
+		Failed:   2,
+		Failures: []domain.StatementFailure{{AccountID: "acc-1"}, {AccountID: "acc-7"}},
+	}}
+
+	err := NewHandler(uc, zap.NewNop()).Handle(context.Background(), event(events.CloudWatchEvent{Time: time.Now()}))
+	if err == nil || !strings.Contains(err.Error(), "acc-1, acc-7") {
+		t.Fatalf("expected error listing failed accounts, got %v", err)
+	}
+}
+
+func TestHandler_Handle_PropagatesUseCaseError(t *testing.T) {
+	wantErr := errors.New("db caída")
+	err := NewHandler(&fakeStatements{err: wantErr}, zap.NewNop()).Handle(context.Background(), event(events.CloudWatchEvent{Time: time.Now()}))
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
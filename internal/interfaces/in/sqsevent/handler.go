@@ -0,0 +1,218 @@
+package sqsevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/in"
+	"stori-challenge/internal/interfaces/in/s3event"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	deadLetterSource = "sqs"
+
+	defaultMaxReceiveCount = 5
+	defaultConcurrency     = 4
+)
+
+// Handler consume notificaciones S3 entregadas por SQS. Cada mensaje se
+// procesa por separado y solo los fallidos vuelven a la cola
+// (BatchItemFailures); al llegar a maxReceiveCount se mandan al dead-letter.
+type Handler struct {
+	summary     in.SummaryUseCase
+	deadLetters in.DeadLetterUseCase
+	log         *zap.Logger
+
+	maxReceiveCount int
+	concurrency     int
+}
+
+type Option func(*Handler)
+
+func WithMaxReceiveCount(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.maxReceiveCount = n
+		}
+	}
+}
+
+func WithConcurrency(n int) Option {
+	return func(h *Handler) {
+		if n > 0 {
+			h.concurrency = n
+		}
+	}
+}
+
+func NewHandler(
+	summary in.SummaryUseCase,
+	deadLetters in.DeadLetterUseCase,
+	log *zap.Logger,
+	opts ...Option,
+) *Handler {
+	h := &Handler{
+		summary:         summary,
+		deadLetters:     deadLetters,
+		log:             log,
+		maxReceiveCount: defaultMaxReceiveCount,
+		concurrency:     defaultConcurrency,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *Handler) Handle(ctx context.Context, evt events.SQSEvent) (events.SQSEventResponse, error) {
+	h.log.Info("evento SQS recibido", zap.Int("messages", len(evt.Records)))
+
+	var (
+		mu   sync.Mutex
+		resp events.SQSEventResponse
+		g    errgroup.Group
+	)
+	g.SetLimit(h.concurrency)
+
+	for _, msg := range evt.Records {
+		g.Go(func() error {
+			if err := h.processMessage(ctx, msg); err != nil {
+				mu.Lock()
+				resp.BatchItemFailures = append(resp.BatchItemFailures, events.SQSBatchItemFailure{
+					ItemIdentifier: msg.MessageId,
+				})
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if n := len(resp.BatchItemFailures); n > 0 {
+		h.log.Warn("mensajes SQS devueltos a la cola",
+			zap.Int("failed", n),
+			zap.Int("messages", len(evt.Records)),
+		)
+	} else {
+		h.log.Info("evento SQS procesado correctamente")
+	}
+	return resp, nil
+}
+
+// processMessage devuelve error solo si el mensaje debe reintentarse.
+func (h *Handler) processMessage(ctx context.Context, msg events.SQSMessage) error {
+	var s3evt events.S3Event
+	if err := json.Unmarshal([]byte(msg.Body), &s3evt); err != nil {
+		// Un cuerpo ilegible no mejora con reintentos: va directo al dead-letter.
+		return h.sendToDeadLetter(ctx, msg, domain.ObjectRef{}, fmt.Errorf("mensaje no es un evento S3: %w", err))
+	}
+	if len(s3evt.Records) == 0 {
+		// s3:TestEvent y similares no traen registros.
+		h.log.Info("mensaje SQS sin registros S3, se descarta", zap.String("message_id", msg.MessageId))
+		return nil
+	}
+
+	var errs []error
+	for _, rec := range s3evt.Records {
+		if err := h.processRecord(ctx, msg, rec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (h *Handler) processRecord(ctx context.Context, msg events.SQSMessage, rec events.S3EventRecord) error {
+	obj, err := s3event.ObjectRefFromRecord(rec)
+	if err != nil {
+		// Una key mal codificada tampoco se arregla reintentando.
+		return h.sendToDeadLetter(ctx, msg, obj, err)
+	}
+	if domain.IsSettledKey(obj.Key) {
+		// Lo movió el propio pipeline a processed/ o quarantine/.
+		h.log.Info("objeto ya archivado, se ignora",
+			zap.String("message_id", msg.MessageId),
+			zap.String("bucket", obj.Bucket),
+			zap.String("key", obj.Key),
+		)
+		return nil
+	}
+
+	h.log.Info("procesando objeto S3",
+		zap.String("message_id", msg.MessageId),
+		zap.String("bucket", obj.Bucket),
+		zap.String("key", obj.Key),
+		zap.String("version_id", obj.VersionID),
+	)
+
+	if receiveCount(msg) < h.maxReceiveCount {
+		// Si falla, SQS vuelve a entregarlo: solo el último intento manda a
+		// cuarentena un fallo transitorio.
+		ctx = domain.WithPendingRetry(ctx)
+	}
+	err = h.summary.ProcessTransactionsFromObject(ctx, obj)
+	if err == nil {
+		return nil
+	}
+
+	h.log.Error("error procesando transacciones",
+		zap.String("message_id", msg.MessageId),
+		zap.String("bucket", obj.Bucket),
+		zap.String("key", obj.Key),
+		zap.Int("receive_count", receiveCount(msg)),
+		zap.Error(err),
+	)
+	if receiveCount(msg) < h.maxReceiveCount {
+		return err
+	}
+	return h.sendToDeadLetter(ctx, msg, obj, err)
+}
+
+// sendToDeadLetter guarda el mensaje; si no se puede guardar se devuelve el
+// error para que SQS lo reintente en vez de perderlo.
+func (h *Handler) sendToDeadLetter(
+	ctx context.Context,
+	msg events.SQSMessage,
+	obj domain.ObjectRef,
+	cause error,
+) error {
+	dl, err := h.deadLetters.RecordDeadLetter(ctx, domain.DeadLetter{
+		Source:       deadLetterSource,
+		MessageID:    msg.MessageId,
+		Bucket:       obj.Bucket,
+		ObjectKey:    obj.Key,
+		Payload:      msg.Body,
+		Error:        cause.Error(),
+		ReceiveCount: receiveCount(msg),
+	})
+	if err != nil {
+		h.log.Error("error guardando dead-letter",
+			zap.String("message_id", msg.MessageId),
+			zap.Error(err),
+		)
+		return errors.Join(cause, err)
+	}
+
+	h.log.Warn("mensaje enviado a dead-letter",
+		zap.String("message_id", msg.MessageId),
+		zap.Uint64("dead_letter_id", dl.ID),
+		zap.String("key", dl.ObjectKey),
+	)
+	return nil
+}
+
+func receiveCount(msg events.SQSMessage) int {
+	n, err := strconv.Atoi(msg.Attributes["ApproximateReceiveCount"])
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
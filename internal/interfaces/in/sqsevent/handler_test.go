@@ -0,0 +1,212 @@
+package sqsevent
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-lambda-go/events"
+	"go.uber.org/zap"
+)
+
+type fakeSummaryUseCase struct {
+	mu      sync.Mutex
+	errs    map[string]error
+	keys    []string
+	pending map[string]bool
+}
+
+func (f *fakeSummaryUseCase) ProcessTransactionsFromObject(ctx context.Context, obj domain.ObjectRef) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.keys = append(f.keys, obj.Key)
+	if f.pending == nil {
+		f.pending = map[string]bool{}
+	}
+	f.pending[obj.Key] = domain.HasPendingRetry(ctx)
+	return f.errs[obj.Key]
+}
+
+type fakeDeadLetters struct {
+	mu    sync.Mutex
+	err   error
+	saved []domain.DeadLetter
+}
+
+func (f *fakeDeadLetters) RecordDeadLetter(_ context.Context, dl domain.DeadLetter) (domain.DeadLetter, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return domain.DeadLetter{}, f.err
+	}
+	dl.ID = uint64(len(f.saved) + 1)
+	f.saved = append(f.saved, dl)
+	return dl, nil
+}
+
+func sqsMessage(t *testing.T, id string, receiveCount int, keys ...string) events.SQSMessage {
+	t.Helper()
+
+	var evt events.S3Event
+	for _, k := range keys {
+		var rec events.S3EventRecord
+		rec.S3.Bucket.Name = "bucket"
+		rec.S3.Object.Key = k
+		evt.Records = append(evt.Records, rec)
+	}
+	body, err := json.Marshal(evt)
+	if err != nil {
+		t.Fatalf("marshal event: %v", err)
+	}
+	return events.SQSMessage{
+		MessageId:  id,
+		Body:       string(body),
+		Attributes: map[string]string{"ApproximateReceiveCount": strconv.Itoa(receiveCount)},
+	}
+}
+
+func failedIDs(resp events.SQSEventResponse) []string {
+	var ids []string
+	for _, f := range resp.BatchItemFailures {
+		ids = append(ids, f.ItemIdentifier)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestHandler_Handle_ReportsOnlyFailedMessages(t *testing.T) {
+	uc := &fakeSummaryUseCase{errs: map[string]error{"b.csv": errors.New("falló")}}
+	dl := &fakeDeadLetters{}
+	h := NewHandler(uc, dl, zap.NewNop())
+
+	resp, err := h.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		sqsMessage(t, "m-1", 1, "a.csv"),
+		sqsMessage(t, "m-2", 1, "b.csv"),
+		sqsMessage(t, "m-3", 1, "c.csv"),
+	}})
+	if err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+
+	if ids := failedIDs(resp); len(ids) != 1 || ids[0] != "m-2" {
+		t.Errorf("BatchItemFailures = %v, want [m-2]", ids)
+	}
+	if len(uc.keys) != 3 {
+		t.Errorf("processed %v, want all 3 keys (no sibling cancellation)", uc.keys)
+	}
+	if len(dl.saved) != 0 {
+		t.Errorf("unexpected dead letters: %+v", dl.saved)
+	}
+}
+
+func TestHandler_Handle_MarksPendingRetryUntilLastReceive(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	h := NewHandler(uc, &fakeDeadLetters{}, zap.NewNop(), WithMaxReceiveCount(3))
+
+	if _, err := h.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		sqsMessage(t, "m-1", 2, "a.csv"),
+		sqsMessage(t, "m-2", 3, "b.csv"),
+	}}); err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+
+	if !uc.pending["a.csv"] || uc.pending["b.csv"] {
+		t.Errorf("pending retry = %v, want only a.csv before its last receive", uc.pending)
+	}
+}
+
+func TestHandler_Handle_PoisonMessageGoesToDeadLetter(t *testing.T) {
+	uc := &fakeSummaryUseCase{errs: map[string]error{"a.csv": errors.New("falló")}}
+	dl := &fakeDeadLetters{}
+	h := NewHandler(uc, dl, zap.NewNop(), WithMaxReceiveCount(3))
+
+	resp, err := h.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		sqsMessage(t, "m-1", 3, "a.csv"),
+	}})
+	if err != nil {
+		t.Fatalf("Handle returned error: %v", err)
+	}
+
+	if len(resp.BatchItemFailures) != 0 {
+		t.Errorf("poison message should be acknowledged, got %v", failedIDs(resp))
+	}
+	if len(dl.saved) != 1 {
+		t.Fatalf("expected 1 dead letter, got %d", len(dl.saved))
+	}
+	got := dl.saved[0]
+	if got.Source != "sqs" || got.MessageID != "m-1" || got.Bucket != "bucket" || got.ObjectKey != "a.csv" ||
+		got.Error != "falló" || got.ReceiveCount != 3 || got.Payload == "" {
+		t.Errorf("unexpected dead letter: %+v", got)
+	}
+}
+
+func TestHandler_Handle_DeadLetterFailureRetriesMessage(t *testing.T) {
+	uc := &fakeSummaryUseCase{errs: map[string]error{"a.csv": errors.New("falló")}}
+	h := NewHandler(uc, &fakeDeadLetters{err: errors.New("db caída")}, zap.NewNop(), WithMaxReceiveCount(1))
+
+	resp, _ := h.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		sqsMessage(t, "m-1", 1, "a.csv"),
+	}})
+
+	if ids := failedIDs(resp); len(ids) != 1 || ids[0] != "m-1" {
+		t.Errorf("BatchItemFailures = %v, want [m-1]", ids)
+	}
+}
+
+func TestHandler_Handle_MalformedBodyGoesStraightToDeadLetter(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	dl := &fakeDeadLetters{}
+	h := NewHandler(uc, dl, zap.NewNop())
+
+	resp, _ := h.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "m-1", Body: "not json"},
+	}})
+
+	if len(resp.BatchItemFailures) != 0 {
+		t.Errorf("unexpected failures: %v", failedIDs(resp))
+	}
+	if len(dl.saved) != 1 || dl.saved[0].Payload != "not json" || dl.saved[0].ReceiveCount != 1 {
+		t.Errorf("unexpected dead letters: %+v", dl.saved)
+	}
+	if len(uc.keys) != 0 {
+		t.Errorf("nothing should be processed, got %v", uc.keys)
+	}
+}
+
+func TestHandler_Handle_IgnoresTestEvent(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	dl := &fakeDeadLetters{}
+	h := NewHandler(uc, dl, zap.NewNop())
+
+	resp, _ := h.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		{MessageId: "m-1", Body: `{"Service":"Amazon S3","Event":"s3:TestEvent","Bucket":"bucket"}`},
+	}})
+
+	if len(resp.BatchItemFailures) != 0 || len(dl.saved) != 0 || len(uc.keys) != 0 {
+		t.Errorf("test event should be dropped: failures=%v deadLetters=%v keys=%v", failedIDs(resp), dl.saved, uc.keys)
+	}
+}
+
+func TestHandler_Handle_IgnoresSettledObjects(t *testing.T) {
+	uc := &fakeSummaryUseCase{}
+	dl := &fakeDeadLetters{}
+	h := NewHandler(uc, dl, zap.NewNop())
+
+	resp, _ := h.Handle(context.Background(), events.SQSEvent{Records: []events.SQSMessage{
+		sqsMessage(t, "m-1", 1, "processed/input/a.csv"),
+		sqsMessage(t, "m-2", 1, "input/b.csv"),
+	}})
+
+	if len(resp.BatchItemFailures) != 0 || len(dl.saved) != 0 {
+		t.Errorf("unexpected failures=%v deadLetters=%v", failedIDs(resp), dl.saved)
+	}
+	if len(uc.keys) != 1 || uc.keys[0] != "input/b.csv" {
+		t.Errorf("only input/b.csv should be processed, got %v", uc.keys)
+	}
+}
@@ -0,0 +1,264 @@
+// Package amountparse convierte montos tal como aparecen en exportaciones
+// bancarias ("1,234.56", "1.234,56", "$60.50", "(10.30)", "10.30-") a
+// decimales, según reglas regionales configurables.
+package amountparse
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/shopspring/decimal"
+)
+
+// Rules describe el formato regional de los montos. El valor cero equivale a
+// Default.
+type Rules struct {
+	// Decimal es el separador decimal.
+	Decimal rune
+	// Thousands es el separador de miles; 0 si no se admite. Con ' ' también
+	// se aceptan los espacios de no separación.
+	Thousands rune
+	// Symbols son los símbolos o códigos de moneda que pueden ir antes o
+	// después del número ("$", "MXN"); los códigos ignoran mayúsculas.
+	Symbols []string
+	// Parentheses lee "(10.30)" como negativo.
+	Parentheses bool
+	// TrailingSign acepta el signo al final ("10.30-").
+	TrailingSign bool
+}
+
+var defaultSymbols = []string{"$", "€", "£", "MXN", "USD", "EUR", "MX$", "US$"}
+
+// Default es el formato de México y Estados Unidos: punto decimal y coma de
+// miles, con símbolos de moneda, paréntesis y signo final.
+func Default() Rules {
+	return Rules{
+		Decimal:      '.',
+		Thousands:    ',',
+		Symbols:      defaultSymbols,
+		Parentheses:  true,
+		TrailingSign: true,
+	}
+}
+
+// locales son los formatos predefinidos; cambian solo los separadores.
+var locales = map[string][2]rune{
+	"es-mx": {'.', ','},
+	"en-us": {'.', ','},
+	"en-gb": {'.', ','},
+	"es-es": {',', '.'},
+	"es-ar": {',', '.'},
+	"es-co": {',', '.'},
+	"pt-br": {',', '.'},
+	"de-de": {',', '.'},
+	"it-it": {',', '.'},
+	"fr-fr": {',', ' '},
+	"de-ch": {'.', '\''},
+}
+
+var separatorNames = map[string]rune{
+	"dot":        '.',
+	"comma":      ',',
+	"space":      ' ',
+	"apostrophe": '\'',
+	"none":       0,
+}
+
+// ParseRules lee una lista separada por comas: opcionalmente un locale
+// ("es-ES") y luego pares clave=valor sobre él, p. ej.
+// "es-MX,parentheses=false,symbols=$|MXN". Las claves son decimal y
+// thousands (dot, comma, space, apostrophe o none), symbols (separados por
+// "|"), parentheses y trailing_sign. Una cadena vacía devuelve Default.
+func ParseRules(spec string) (Rules, error) {
+	r := Default()
+	for i, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			if i != 0 {
+				return Rules{}, fmt.Errorf("formato de montos inválido %q, se espera clave=valor", part)
+			}
+			seps, ok := locales[strings.ToLower(strings.ReplaceAll(part, "_", "-"))]
+			if !ok {
+				return Rules{}, fmt.Errorf("locale de montos desconocido %q", part)
+			}
+			r.Decimal, r.Thousands = seps[0], seps[1]
+			continue
+		}
+
+		key, value = strings.ToLower(strings.TrimSpace(key)), strings.TrimSpace(value)
+		var err error
+		switch key {
+		case "decimal", "thousands":
+			sep, known := separatorNames[strings.ToLower(value)]
+			if !known || (key == "decimal" && sep == 0) {
+				return Rules{}, fmt.Errorf("separador %s inválido %q", key, value)
+			}
+			if key == "decimal" {
+				r.Decimal = sep
+			} else {
+				r.Thousands = sep
+			}
+		case "symbols":
+			r.Symbols = nil
+			for _, s := range strings.Split(value, "|") {
+				if s = strings.TrimSpace(s); s != "" {
+					r.Symbols = append(r.Symbols, s)
+				}
+			}
+		case "parentheses":
+			r.Parentheses, err = strconv.ParseBool(value)
+		case "trailing_sign":
+			r.TrailingSign, err = strconv.ParseBool(value)
+		default:
+			return Rules{}, fmt.Errorf("clave desconocida %q en el formato de montos", key)
+		}
+		if err != nil {
+			return Rules{}, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+	if r.Decimal == r.Thousands {
+		return Rules{}, errors.New("el separador decimal y el de miles deben ser distintos")
+	}
+	return r, nil
+}
+
+// Parse convierte un monto. El signo puede ir antes o, con TrailingSign,
+// después del número, y los símbolos de moneda a cualquiera de los dos lados.
+// Los separadores de miles deben agrupar de a tres dígitos, así "1,5" no se
+// lee como 15 por error.
+func (r Rules) Parse(value string) (decimal.Decimal, error) {
+	if r.Decimal == 0 {
+		r = Default()
+	}
+
+	s := strings.TrimFunc(value, unicode.IsSpace)
+	if s == "" {
+		return decimal.Decimal{}, errors.New("monto vacío")
+	}
+
+	negative, signs, parens := false, 0, false
+	for changed := true; changed; {
+		changed = false
+		s = strings.TrimFunc(s, unicode.IsSpace)
+		switch {
+		case s == "":
+		case r.Parentheses && !parens && signs == 0 && s[0] == '(' && s[len(s)-1] == ')' && len(s) > 1:
+			s, negative, parens, changed = s[1:len(s)-1], true, true, true
+		case signs == 0 && isSign(s[0]):
+			negative, signs, changed = negative != (s[0] == '-'), 1, true
+			s = s[1:]
+		case signs == 0 && strings.HasPrefix(s, "−"):
+			negative, signs, changed = !negative, 1, true
+			s = s[len("−"):]
+		case r.TrailingSign && signs == 0 && isSign(s[len(s)-1]):
+			negative, signs, changed = negative != (s[len(s)-1] == '-'), 1, true
+			s = s[:len(s)-1]
+		default:
+			if rest, ok := r.trimSymbol(s); ok {
+				s, changed = rest, true
+			}
+		}
+	}
+
+	number, err := r.normalize(s)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("monto inválido %q: %w", value, err)
+	}
+	d, err := decimal.NewFromString(number)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("monto inválido %q: %w", value, err)
+	}
+	if negative {
+		d = d.Neg()
+	}
+	return d, nil
+}
+
+func isSign(c byte) bool {
+	return c == '+' || c == '-'
+}
+
+// trimSymbol quita un símbolo de moneda al inicio o al final.
+func (r Rules) trimSymbol(s string) (string, bool) {
+	for _, sym := range r.Symbols {
+		if sym == "" {
+			continue
+		}
+		if len(s) >= len(sym) && strings.EqualFold(s[:len(sym)], sym) {
+			return s[len(sym):], true
+		}
+		if len(s) >= len(sym) && strings.EqualFold(s[len(s)-len(sym):], sym) {
+			return s[:len(s)-len(sym)], true
+		}
+	}
+	return s, false
+}
+
+// normalize valida los dígitos y separadores y devuelve el número con punto
+// decimal y sin separadores de miles.
+func (r Rules) normalize(s string) (string, error) {
+	intPart, fracPart, hasDecimal := strings.Cut(s, string(r.Decimal))
+	if hasDecimal && !allDigits(fracPart) {
+		return "", errors.New("parte decimal inválida")
+	}
+
+	var digits strings.Builder
+	groups := splitThousands(intPart, r.Thousands)
+	for i, g := range groups {
+		switch {
+		case !allDigits(g):
+			return "", errors.New("caracteres no numéricos")
+		case len(groups) > 1 && i == 0 && (len(g) == 0 || len(g) > 3):
+			return "", errors.New("separador de miles mal ubicado")
+		case i > 0 && len(g) != 3:
+			return "", errors.New("separador de miles mal ubicado")
+		}
+		digits.WriteString(g)
+	}
+	if digits.Len() == 0 && fracPart == "" {
+		return "", errors.New("sin dígitos")
+	}
+
+	number := digits.String()
+	if number == "" {
+		number = "0"
+	}
+	if fracPart != "" {
+		number += "." + fracPart
+	}
+	return number, nil
+}
+
+// splitThousands separa la parte entera por el separador de miles, sin
+// descartar grupos vacíos; con ' ' también corta en los espacios de no
+// separación.
+func splitThousands(s string, sep rune) []string {
+	if sep == 0 {
+		return []string{s}
+	}
+	var groups []string
+	start := 0
+	for i, c := range s {
+		if c == sep || (sep == ' ' && (c == '\u00a0' || c == '\u202f')) {
+			groups = append(groups, s[start:i])
+			start = i + len(string(c))
+		}
+	}
+	return append(groups, s[start:])
+}
+
+func allDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
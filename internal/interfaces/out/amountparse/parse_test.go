@@ -0,0 +1,182 @@
+package amountparse
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestRules_Parse(t *testing.T) {
+	es, err := ParseRules("es-ES")
+	if err != nil {
+		t.Fatalf("ParseRules returned error: %v", err)
+	}
+	fr, err := ParseRules("fr-FR")
+	if err != nil {
+		t.Fatalf("ParseRules returned error: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		rules Rules
+		in    string
+		want  string
+	}{
+		{"plain", Rules{}, "60.5", "60.5"},
+		{"explicit plus", Rules{}, "+60.5", "60.5"},
+		{"minus", Rules{}, "-10.3", "-10.3"},
+		{"thousands", Rules{}, "1,234.56", "1234.56"},
+		{"millions", Rules{}, "-1,234,567", "-1234567"},
+		{"currency symbol", Rules{}, "$60.50", "60.5"},
+		{"sign before symbol", Rules{}, "-$60.50", "-60.5"},
+		{"sign after symbol", Rules{}, "$-60.50", "-60.5"},
+		{"currency code", Rules{}, "1,000.00 MXN", "1000"},
+		{"lowercase code", Rules{}, "usd 5", "5"},
+		{"parentheses", Rules{}, "(10.30)", "-10.3"},
+		{"parentheses and symbol", Rules{}, "($1,010.30)", "-1010.3"},
+		{"trailing minus", Rules{}, "10.30-", "-10.3"},
+		{"unicode minus", Rules{}, "−7", "-7"},
+		{"leading decimal", Rules{}, ".5", "0.5"},
+		{"surrounding spaces", Rules{}, "  42 ", "42"},
+		{"es thousands and decimal", es, "1.234,56", "1234.56"},
+		{"es euro", es, "-1.234,56 €", "-1234.56"},
+		{"fr space thousands", fr, "1 234,56", "1234.56"},
+		{"fr no-break space", fr, "1\u00a0234,56", "1234.56"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.rules.Parse(tc.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) returned error: %v", tc.in, err)
+			}
+			if !got.Equal(decimal.RequireFromString(tc.want)) {
+				t.Errorf("Parse(%q) = %s, want %s", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRules_ParseRejects(t *testing.T) {
+	es, err := ParseRules("es-ES")
+	if err != nil {
+		t.Fatalf("ParseRules returned error: %v", err)
+	}
+	strict, err := ParseRules("parentheses=false,trailing_sign=false,symbols=")
+	if err != nil {
+		t.Fatalf("ParseRules returned error: %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		rules Rules
+		in    string
+	}{
+		{"empty", Rules{}, ""},
+		{"letters", Rules{}, "abc"},
+		{"only sign", Rules{}, "-"},
+		{"two signs", Rules{}, "--5"},
+		{"sign on both ends", Rules{}, "-5-"},
+		{"sign and parentheses", Rules{}, "-(5)"},
+		{"two decimal points", Rules{}, "1.2.3"},
+		{"short thousands group", Rules{}, "1,5"},
+		{"long first group", Rules{}, "1234,567"},
+		{"empty group", Rules{}, "1,,234"},
+		{"leading separator", Rules{}, ",234"},
+		{"exponent", Rules{}, "1e3"},
+		{"unknown symbol", Rules{}, "¥5"},
+		{"es reads dot as thousands", es, "60.5"},
+		{"parentheses disabled", strict, "(5)"},
+		{"trailing sign disabled", strict, "5-"},
+		{"symbols disabled", strict, "$5"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got, err := tc.rules.Parse(tc.in); err == nil {
+				t.Errorf("Parse(%q) = %s, want an error", tc.in, got)
+			}
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	r, err := ParseRules(" de_DE , thousands=none, symbols=EUR|€ , trailing_sign=false")
+	if err != nil {
+		t.Fatalf("ParseRules returned error: %v", err)
+	}
+	if r.Decimal != ',' || r.Thousands != 0 || r.TrailingSign || !r.Parentheses {
+		t.Errorf("rules = %+v", r)
+	}
+	if len(r.Symbols) != 2 || r.Symbols[0] != "EUR" || r.Symbols[1] != "€" {
+		t.Errorf("Symbols = %q", r.Symbols)
+	}
+
+	if r, err := ParseRules(""); err != nil || r.Decimal != '.' || r.Thousands != ',' {
+		t.Errorf("empty spec = %+v, %v; want Default", r, err)
+	}
+
+	for _, spec := range []string{
+		"xx-XX",
+		"decimal=comma,es-ES",
+		"decimal=none",
+		"decimal=comma",
+		"thousands=semicolon",
+		"parentheses=maybe",
+		"rounding=up",
+	} {
+		if _, err := ParseRules(spec); err == nil {
+			t.Errorf("ParseRules(%q) should fail", spec)
+		}
+	}
+}
+
+var fuzzLocales = []string{"", "es-ES", "fr-FR", "de-CH", "thousands=none,parentheses=false,trailing_sign=false"}
+
+func FuzzRules_Parse(f *testing.F) {
+	for _, seed := range []string{
+		"60.5", "+60.5", "-10.3", "1,234.56", "1.234,56", "$60.50", "(10.30)", "10.30-",
+		"1 234,56", "1'234.56", "−7", "($-)", "(", ")", "--", "1,,2", "€", "MX$", ".", "1e3", "\xff",
+	} {
+		for i := range fuzzLocales {
+			f.Add(seed, uint8(i))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, value string, locale uint8) {
+		rules, err := ParseRules(fuzzLocales[int(locale)%len(fuzzLocales)])
+		if err != nil {
+			t.Fatalf("ParseRules returned error: %v", err)
+		}
+		d, err := rules.Parse(value)
+		if err != nil {
+			return
+		}
+
+		// Un monto aceptado vuelve a leerse igual desde su forma canónica.
+		canonical := strings.Replace(d.String(), ".", string(rules.Decimal), 1)
+		again, err := Rules{Decimal: rules.Decimal, Thousands: rules.Thousands}.Parse(canonical)
+		if err != nil {
+			t.Fatalf("Parse(%q) = %s, but its canonical form %q fails: %v", value, d, canonical, err)
+		}
+		if !again.Equal(d) {
+			t.Fatalf("Parse(%q) = %s, canonical form %q reads as %s", value, d, canonical, again)
+		}
+	})
+}
+
+func FuzzParseRules(f *testing.F) {
+	for _, seed := range []string{"", "es-ES", "es-MX,parentheses=false,symbols=$|MXN", "decimal=comma,thousands=dot", "=", ",,", "symbols=|"} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, spec string) {
+		rules, err := ParseRules(spec)
+		if err != nil {
+			return
+		}
+		if rules.Decimal == 0 || rules.Decimal == rules.Thousands {
+			t.Fatalf("ParseRules(%q) accepted invalid separators: %+v", spec, rules)
+		}
+		_, _ = rules.Parse("-$1,234.56")
+	})
+}
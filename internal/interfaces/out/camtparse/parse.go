@@ -0,0 +1,226 @@
+// Package camtparse lee extractos ISO 20022 camt.053 (BankToCustomerStatement)
+// en streaming: cada Ntry y cada Bal se decodifica por separado, sin cargar el
+// documento completo.
+package camtparse
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNotCamt indica que el XML no es un documento camt.053.
+var ErrNotCamt = errors.New("el archivo no es un extracto camt.053")
+
+const (
+	balanceOpening         = "OPBD"
+	balancePreviousClosing = "PRCD"
+	balanceClosing         = "CLBD"
+
+	statusBooked = "BOOK"
+	debit        = "DBIT"
+	notProvided  = "NOTPROVIDED"
+)
+
+// Looks informa si el inicio del contenido declara el espacio de nombres de
+// camt.053.
+func Looks(head []byte) bool {
+	return bytes.Contains(head, []byte("camt.053"))
+}
+
+// Parse devuelve un archivo con una transacción por Ntry contabilizado (sin
+// Sts o con Sts BOOK) y los saldos del extracto: el inicial es el primer OPBD
+// (o PRCD si no lo hay) y el final el último CLBD. Un Ntry sin fecha o monto
+// válidos corta el parseo con error.
+func Parse(ctx context.Context, r io.Reader) (domain.TransactionFile, error) {
+	dec := xml.NewDecoder(r)
+
+	var (
+		file       domain.TransactionFile
+		isCamt     bool
+		hasOPBD    bool
+		entryCount int
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return domain.TransactionFile{}, err
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "Document":
+			isCamt = strings.Contains(start.Name.Space, "camt.053")
+		case "BkToCstmrStmt":
+			isCamt = true
+		case "Bal":
+			var b balance
+			if err := dec.DecodeElement(&b, &start); err != nil {
+				return domain.TransactionFile{}, err
+			}
+			amount, err := b.Amt.signed(b.CdtDbtInd)
+			if err != nil {
+				return domain.TransactionFile{}, fmt.Errorf("Bal %s: %w", b.Code, err)
+			}
+			switch {
+			case b.Code == balanceOpening && !hasOPBD:
+				file.OpeningBalance, hasOPBD = &amount, true
+			case b.Code == balancePreviousClosing && file.OpeningBalance == nil:
+				file.OpeningBalance = &amount
+			case b.Code == balanceClosing:
+				file.ClosingBalance = &amount
+			}
+		case "Ntry":
+			if err := ctx.Err(); err != nil {
+				return domain.TransactionFile{}, err
+			}
+			var e entry
+			if err := dec.DecodeElement(&e, &start); err != nil {
+				return domain.TransactionFile{}, err
+			}
+			entryCount++
+			if !e.booked() {
+				continue
+			}
+			tx, err := e.transaction()
+			if err != nil {
+				return domain.TransactionFile{}, fmt.Errorf("Ntry %d: %w", entryCount, err)
+			}
+			file.Transactions = append(file.Transactions, tx)
+		}
+	}
+
+	if !isCamt {
+		return domain.TransactionFile{}, ErrNotCamt
+	}
+	return file, nil
+}
+
+type amount struct {
+	Value string `xml:",chardata"`
+	Ccy   string `xml:"Ccy,attr"`
+}
+
+// signed aplica el indicador crédito/débito: los montos de camt siempre son
+// positivos.
+func (a amount) signed(indicator string) (decimal.Decimal, error) {
+	d, err := decimal.NewFromString(strings.TrimSpace(a.Value))
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("Amt inválido %q: %w", a.Value, err)
+	}
+	if strings.TrimSpace(indicator) == debit {
+		d = d.Neg()
+	}
+	return d, nil
+}
+
+type dateChoice struct {
+	Dt   string `xml:"Dt"`
+	DtTm string `xml:"DtTm"`
+}
+
+// day toma solo el día (YYYY-MM-DD) de Dt o DtTm.
+func (d dateChoice) day() (time.Time, bool) {
+	value := strings.TrimSpace(d.Dt)
+	if value == "" {
+		value = strings.TrimSpace(d.DtTm)
+	}
+	if len(value) < 10 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02", value[:10])
+	return t, err == nil
+}
+
+type balance struct {
+	Code      string `xml:"Tp>CdOrPrtry>Cd"`
+	Amt       amount `xml:"Amt"`
+	CdtDbtInd string `xml:"CdtDbtInd"`
+}
+
+// status cubre Sts como texto (camt.053.001.02) y como Sts>Cd (.001.08 en
+// adelante).
+type status struct {
+	Text string `xml:",chardata"`
+	Cd   string `xml:"Cd"`
+}
+
+type entry struct {
+	NtryRef      string     `xml:"NtryRef"`
+	Amt          amount     `xml:"Amt"`
+	CdtDbtInd    string     `xml:"CdtDbtInd"`
+	Sts          status     `xml:"Sts"`
+	BookgDt      dateChoice `xml:"BookgDt"`
+	ValDt        dateChoice `xml:"ValDt"`
+	AcctSvcrRef  string     `xml:"AcctSvcrRef"`
+	AddtlNtryInf string     `xml:"AddtlNtryInf"`
+	Details      []detail   `xml:"NtryDtls>TxDtls"`
+}
+
+type detail struct {
+	EndToEndID string   `xml:"Refs>EndToEndId"`
+	Ustrd      []string `xml:"RmtInf>Ustrd"`
+	AddtlTxInf string   `xml:"AddtlTxInf"`
+}
+
+func (e entry) booked() bool {
+	code := strings.TrimSpace(e.Sts.Cd)
+	if code == "" {
+		code = strings.TrimSpace(e.Sts.Text)
+	}
+	return code == "" || code == statusBooked
+}
+
+// transaction arma una transacción por Ntry, aunque agrupe varios TxDtls: el
+// monto del Ntry es el que mueve el saldo.
+func (e entry) transaction() (domain.Transaction, error) {
+	date, ok := e.BookgDt.day()
+	if !ok {
+		date, ok = e.ValDt.day()
+	}
+	if !ok {
+		return domain.Transaction{}, errors.New("sin BookgDt ni ValDt válidos")
+	}
+	amount, err := e.Amt.signed(e.CdtDbtInd)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+
+	var first detail
+	if len(e.Details) > 0 {
+		first = e.Details[0]
+	}
+	return domain.Transaction{
+		Date:        date,
+		Amount:      amount,
+		Currency:    strings.TrimSpace(e.Amt.Ccy),
+		Reference:   firstNonEmpty(e.AcctSvcrRef, e.NtryRef, first.EndToEndID),
+		Description: firstNonEmpty(e.AddtlNtryInf, first.AddtlTxInf),
+		Memo:        strings.Join(first.Ustrd, " "),
+	}, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && v != notProvided {
+			return v
+		}
+	}
+	return ""
+}
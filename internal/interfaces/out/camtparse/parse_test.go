@@ -0,0 +1,171 @@
+package camtparse
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const statement = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">
+  <BkToCstmrStmt>
+    <GrpHdr><MsgId>MSG-1</MsgId><CreDtTm>2021-08-01T06:00:00</CreDtTm></GrpHdr>
+    <Stmt>
+      <Id>STMT-2021-07</Id>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>PRCD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="MXN">900.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Dt><Dt>2021-06-30</Dt></Dt>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="MXN">1000.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Dt><Dt>2021-07-01</Dt></Dt>
+      </Bal>
+      <Bal>
+        <Tp><CdOrPrtry><Cd>CLBD</Cd></CdOrPrtry></Tp>
+        <Amt Ccy="MXN">1050.20</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Dt><Dt>2021-07-31</Dt></Dt>
+      </Bal>
+      <Ntry>
+        <NtryRef>1</NtryRef>
+        <Amt Ccy="MXN">60.50</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><Dt>2021-07-15</Dt></BookgDt>
+        <AcctSvcrRef>SPEI-0001</AcctSvcrRef>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>E2E-1</EndToEndId></Refs>
+          <RmtInf><Ustrd>Nomina</Ustrd><Ustrd>julio</Ustrd></RmtInf>
+        </TxDtls></NtryDtls>
+        <AddtlNtryInf>Deposito SPEI</AddtlNtryInf>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="MXN">10.30</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>PDNG</Cd></Sts>
+        <BookgDt><Dt>2021-07-30</Dt></BookgDt>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="MXN">10.30</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts><Cd>BOOK</Cd></Sts>
+        <BookgDt><DtTm>2021-07-28T13:45:00-05:00</DtTm></BookgDt>
+        <NtryDtls><TxDtls>
+          <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+          <AddtlTxInf>Tienda</AddtlTxInf>
+        </TxDtls></NtryDtls>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>
+`
+
+func TestParse_EntriesAndBalances(t *testing.T) {
+	file, err := Parse(context.Background(), strings.NewReader(statement))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(file.Transactions) != 2 {
+		t.Fatalf("expected 2 booked entries, got %d", len(file.Transactions))
+	}
+
+	credit := file.Transactions[0]
+	if !credit.Date.Equal(time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)) || !credit.Amount.Equal(decimal.RequireFromString("60.5")) {
+		t.Errorf("unexpected credit date or amount: %+v", credit)
+	}
+	if credit.Currency != "MXN" || credit.Reference != "SPEI-0001" || credit.Description != "Deposito SPEI" || credit.Memo != "Nomina julio" {
+		t.Errorf("unexpected credit fields: %+v", credit)
+	}
+
+	debitTx := file.Transactions[1]
+	if !debitTx.Date.Equal(time.Date(2021, 7, 28, 0, 0, 0, 0, time.UTC)) || !debitTx.Amount.Equal(decimal.RequireFromString("-10.3")) {
+		t.Errorf("unexpected debit date or amount: %+v", debitTx)
+	}
+	if debitTx.Reference != "" || debitTx.Description != "Tienda" {
+		t.Errorf("unexpected debit fields: %+v", debitTx)
+	}
+
+	if file.OpeningBalance == nil || !file.OpeningBalance.Equal(decimal.RequireFromString("1000")) {
+		t.Errorf("opening balance = %v, want 1000 (OPBD over PRCD)", file.OpeningBalance)
+	}
+	if file.ClosingBalance == nil || !file.ClosingBalance.Equal(decimal.RequireFromString("1050.2")) {
+		t.Errorf("closing balance = %v, want 1050.2", file.ClosingBalance)
+	}
+}
+
+func TestParse_PreviousClosingAsOpeningAndDebitBalance(t *testing.T) {
+	body := `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"><BkToCstmrStmt><Stmt>
+<Bal><Tp><CdOrPrtry><Cd>PRCD</Cd></CdOrPrtry></Tp><Amt Ccy="MXN">25</Amt><CdtDbtInd>DBIT</CdtDbtInd></Bal>
+<Ntry><Amt Ccy="MXN">5</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts><BookgDt><Dt>2021-08-02</Dt></BookgDt></Ntry>
+</Stmt></BkToCstmrStmt></Document>`
+
+	file, err := Parse(context.Background(), strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(file.Transactions) != 1 || file.ClosingBalance != nil {
+		t.Fatalf("unexpected file: %+v", file)
+	}
+	if file.OpeningBalance == nil || !file.OpeningBalance.Equal(decimal.RequireFromString("-25")) {
+		t.Errorf("opening balance = %v, want -25", file.OpeningBalance)
+	}
+}
+
+func TestParse_InvalidEntry(t *testing.T) {
+	body := `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"><BkToCstmrStmt><Stmt>
+<Ntry><Amt Ccy="MXN">5</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2021-08-02</Dt></BookgDt></Ntry>
+<Ntry><Amt Ccy="MXN">abc</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2021-08-03</Dt></BookgDt></Ntry>
+</Stmt></BkToCstmrStmt></Document>`
+
+	_, err := Parse(context.Background(), strings.NewReader(body))
+	if err == nil || !strings.Contains(err.Error(), "Ntry 2") {
+		t.Fatalf("expected an error naming the second Ntry, got %v", err)
+	}
+}
+
+func TestParse_NotCamt(t *testing.T) {
+	body := `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:pain.001.001.09"><CstmrCdtTrfInitn/></Document>`
+
+	if _, err := Parse(context.Background(), strings.NewReader(body)); !errors.Is(err, ErrNotCamt) {
+		t.Fatalf("expected ErrNotCamt, got %v", err)
+	}
+}
+
+func TestParse_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Parse(ctx, strings.NewReader(statement)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestParse_LongRemittanceKeptWhole(t *testing.T) {
+	info := strings.Repeat("Pago de servicios ", 25)
+	ustrd := strings.Repeat("ref ", 70)
+	body := `<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"><BkToCstmrStmt><Stmt>
+<Ntry><Amt Ccy="MXN">5</Amt><CdtDbtInd>CRDT</CdtDbtInd><BookgDt><Dt>2021-08-02</Dt></BookgDt>
+<NtryDtls><TxDtls><RmtInf><Ustrd>` + ustrd + `</Ustrd><Ustrd>fin</Ustrd></RmtInf></TxDtls></NtryDtls>
+<AddtlNtryInf>` + info + `</AddtlNtryInf></Ntry>
+</Stmt></BkToCstmrStmt></Document>`
+
+	file, err := Parse(context.Background(), strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	tx := file.Transactions[0]
+	if tx.Description != strings.TrimSpace(info) {
+		t.Errorf("Description has %d runes, want %d", len([]rune(tx.Description)), len([]rune(strings.TrimSpace(info))))
+	}
+	if want := ustrd + " fin"; tx.Memo != want {
+		t.Errorf("Memo has %d runes, want %d", len([]rune(tx.Memo)), len([]rune(want)))
+	}
+}
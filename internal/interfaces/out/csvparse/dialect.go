@@ -0,0 +1,185 @@
+package csvparse
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"slices"
+	"unicode/utf8"
+
+	"stori-challenge/internal/core/domain"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// sniffSize es cuánto del inicio del archivo se mira para decidir la
+// codificación y el separador.
+const sniffSize = 32 * 1024
+
+// sniffLines es cuántos registros se usan para elegir el separador.
+const sniffLines = 20
+
+const (
+	encodingUTF8    = "UTF-8"
+	encodingUTF16LE = "UTF-16LE"
+	encodingUTF16BE = "UTF-16BE"
+	// Los CSV de Excel en español sin BOM suelen venir en Windows-1252, que
+	// además cubre Latin-1 en los caracteres imprimibles.
+	encodingWindows1252 = "windows-1252"
+)
+
+// delimiters son los separadores candidatos, en orden de preferencia ante
+// un empate.
+var delimiters = []rune{',', ';', '\t', '|'}
+
+var (
+	bomUTF8    = []byte{0xef, 0xbb, 0xbf}
+	bomUTF16LE = []byte{0xff, 0xfe}
+	bomUTF16BE = []byte{0xfe, 0xff}
+)
+
+// Sniff detecta el dialecto del CSV y devuelve un reader con el contenido en
+// UTF-8 y sin BOM. Salvo con BOM de UTF-16, el archivo es UTF-8 si el inicio es
+// UTF-8 válido y Windows-1252 si no.
+func Sniff(r io.Reader) (domain.Dialect, io.Reader, error) {
+	return sniff(r, false)
+}
+
+// sniff es Sniff; con prefix, r es solo el inicio del archivo y su fin no es
+// el del archivo.
+func sniff(r io.Reader, prefix bool) (domain.Dialect, io.Reader, error) {
+	br := bufio.NewReaderSize(r, sniffSize)
+	head, err := br.Peek(sniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return domain.Dialect{}, nil, err
+	}
+	complete := err == io.EOF && !prefix
+
+	var (
+		dialect domain.Dialect
+		dec     *encoding.Decoder
+	)
+	if bytes.HasPrefix(head, bomUTF8) {
+		// El BOM de UTF-8 no garantiza el resto: hay exportaciones que lo
+		// anteponen a contenido en Windows-1252.
+		dialect.BOM = true
+		head = head[len(bomUTF8):]
+		if _, err := br.Discard(len(bomUTF8)); err != nil {
+			return domain.Dialect{}, nil, err
+		}
+	}
+	switch {
+	case !dialect.BOM && bytes.HasPrefix(head, bomUTF16LE):
+		dialect = domain.Dialect{Encoding: encodingUTF16LE, BOM: true}
+		dec = unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()
+	case !dialect.BOM && bytes.HasPrefix(head, bomUTF16BE):
+		dialect = domain.Dialect{Encoding: encodingUTF16BE, BOM: true}
+		dec = unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()
+	case utf8.Valid(completeRunes(head, complete)):
+		dialect.Encoding = encodingUTF8
+	default:
+		dialect.Encoding = encodingWindows1252
+		dec = charmap.Windows1252.NewDecoder()
+	}
+
+	text := head
+	var out io.Reader = br
+	if dec != nil {
+		if !complete && dialect.Encoding != encodingWindows1252 {
+			// Un carácter UTF-16 puede quedar partido al final de la muestra.
+			text = text[:len(text)&^1]
+		}
+		text, _ = dec.Bytes(text)
+		dec.Reset()
+		out = transform.NewReader(br, dec)
+	}
+
+	dialect.Delimiter = sniffDelimiter(text, complete)
+	return dialect, out, nil
+}
+
+// completeRunes quita del final de una muestra cortada los bytes de un
+// carácter UTF-8 incompleto.
+func completeRunes(head []byte, complete bool) []byte {
+	if complete {
+		return head
+	}
+	for i := len(head) - 1; i >= 0 && i >= len(head)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(head[i]) {
+			if !utf8.FullRune(head[i:]) {
+				return head[:i]
+			}
+			break
+		}
+	}
+	return head
+}
+
+// sniffDelimiter cuenta cada candidato fuera de comillas en los primeros
+// registros. Gana el que aparece en la cabecera la misma cantidad de veces en
+// más registros; ante un empate, el que más columnas arma y luego el orden de
+// delimiters. Sin candidatos (una sola columna) se usa la coma.
+func sniffDelimiter(text []byte, complete bool) rune {
+	records := countDelimiters(text, complete)
+	if len(records) == 0 {
+		return ','
+	}
+
+	best, bestConsistent, bestCount := ',', 0, 0
+	for _, d := range delimiters {
+		header := records[0][d]
+		if header == 0 {
+			continue
+		}
+		consistent := 0
+		for _, rec := range records {
+			if rec[d] == header {
+				consistent++
+			}
+		}
+		if consistent > bestConsistent || (consistent == bestConsistent && header > bestCount) {
+			best, bestConsistent, bestCount = d, consistent, header
+		}
+	}
+	return best
+}
+
+// countDelimiters separa la muestra en registros, respetando saltos de línea
+// entre comillas, y cuenta los candidatos de cada uno. Si la muestra está
+// cortada, el último registro se descarta porque puede estar incompleto.
+func countDelimiters(text []byte, complete bool) []map[rune]int {
+	var (
+		records  []map[rune]int
+		current  = map[rune]int{}
+		inQuotes bool
+		empty    = true
+	)
+	for _, c := range string(text) {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+		case inQuotes:
+		case c == '\n':
+			if !empty {
+				records = append(records, current)
+				if len(records) == sniffLines {
+					return records
+				}
+			}
+			current, empty = map[rune]int{}, true
+			continue
+		case c == '\r':
+			continue
+		case slices.Contains(delimiters, c):
+			current[c]++
+		}
+		empty = false
+	}
+	if complete && !empty {
+		records = append(records, current)
+	}
+	return records
+}
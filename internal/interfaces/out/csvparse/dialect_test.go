@@ -0,0 +1,150 @@
+package csvparse
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func TestSniff(t *testing.T) {
+	latin1, err := charmap.Windows1252.NewEncoder().String("Id;Date;Transaction;Category\n0;7/15;+60.5;Café\n")
+	if err != nil {
+		t.Fatalf("encode windows-1252: %v", err)
+	}
+	utf16, err := unicode.UTF16(unicode.LittleEndian, unicode.UseBOM).NewEncoder().String("Id\tDate\tTransaction\n0\t7/15\t+60.5\n")
+	if err != nil {
+		t.Fatalf("encode utf-16: %v", err)
+	}
+
+	cases := []struct {
+		name     string
+		body     string
+		want     domain.Dialect
+		wantText string
+	}{
+		{
+			name:     "plain comma",
+			body:     "Id,Date,Transaction\n0,7/15,+60.5\n",
+			want:     domain.Dialect{Encoding: "UTF-8", Delimiter: ','},
+			wantText: "Id,Date,Transaction\n0,7/15,+60.5\n",
+		},
+		{
+			name:     "utf-8 bom and pipe",
+			body:     "\xef\xbb\xbfId|Date|Transaction\n0|7/15|+60.5\n",
+			want:     domain.Dialect{Encoding: "UTF-8", BOM: true, Delimiter: '|'},
+			wantText: "Id|Date|Transaction\n0|7/15|+60.5\n",
+		},
+		{
+			name:     "windows-1252 semicolon",
+			body:     latin1,
+			want:     domain.Dialect{Encoding: "windows-1252", Delimiter: ';'},
+			wantText: "Id;Date;Transaction;Category\n0;7/15;+60.5;Café\n",
+		},
+		{
+			name:     "utf-16 tab",
+			body:     utf16,
+			want:     domain.Dialect{Encoding: "UTF-16LE", BOM: true, Delimiter: '\t'},
+			wantText: "Id\tDate\tTransaction\n0\t7/15\t+60.5\n",
+		},
+		{
+			// Las comas del monto van entre comillas y no cuentan.
+			name:     "quoted commas",
+			body:     "Id;Date;Transaction\n0;7/15;\"1,060.5\"\n1;7/16;\"-2,000\"\n",
+			want:     domain.Dialect{Encoding: "UTF-8", Delimiter: ';'},
+			wantText: "Id;Date;Transaction\n0;7/15;\"1,060.5\"\n1;7/16;\"-2,000\"\n",
+		},
+		{
+			name:     "single column",
+			body:     "Date\n7/15\n",
+			want:     domain.Dialect{Encoding: "UTF-8", Delimiter: ','},
+			wantText: "Date\n7/15\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dialect, r, err := Sniff(strings.NewReader(tc.body))
+			if err != nil {
+				t.Fatalf("Sniff returned error: %v", err)
+			}
+			if dialect != tc.want {
+				t.Errorf("dialect = %+v, want %+v", dialect, tc.want)
+			}
+			text, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(text) != tc.wantText {
+				t.Errorf("text = %q, want %q", text, tc.wantText)
+			}
+		})
+	}
+}
+
+func TestSniff_PrefersConsistentDelimiter(t *testing.T) {
+	// La cabecera tiene una coma suelta, pero solo el punto y coma arma las
+	// mismas columnas en todas las filas.
+	body := "Id;Date;Transaction;Note, extra\n0;7/15;+60.5;a\n1;7/16;-1;b\n"
+
+	dialect, _, err := Sniff(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Sniff returned error: %v", err)
+	}
+	if dialect.Delimiter != ';' {
+		t.Errorf("Delimiter = %q, want ';'", dialect.Delimiter)
+	}
+}
+
+func TestSniff_LongFileKeepsWholeContent(t *testing.T) {
+	// Más grande que la muestra: el resto del archivo se transcodifica igual.
+	var b bytes.Buffer
+	b.WriteString("Id;Date;Transaction;Category\n")
+	for i := range 2000 {
+		b.WriteString("0;7/15;+1;Señal " + strings.Repeat("x", i%7) + "\n")
+	}
+	encoded, err := charmap.Windows1252.NewEncoder().Bytes(b.Bytes())
+	if err != nil {
+		t.Fatalf("encode windows-1252: %v", err)
+	}
+
+	dialect, r, err := Sniff(bytes.NewReader(encoded))
+	if err != nil {
+		t.Fatalf("Sniff returned error: %v", err)
+	}
+	if dialect.Encoding != "windows-1252" || dialect.Delimiter != ';' {
+		t.Errorf("dialect = %+v", dialect)
+	}
+	text, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !bytes.Equal(text, b.Bytes()) {
+		t.Errorf("transcoded content differs from the original (%d vs %d bytes)", len(text), b.Len())
+	}
+}
+
+func TestParse_ExcelExport(t *testing.T) {
+	body, err := charmap.Windows1252.NewEncoder().String("Id;Date;Transaction;Category\r\n0;7/15;+60.5;Nómina\r\n1;7/28;-10.3;Súper\r\n")
+	if err != nil {
+		t.Fatalf("encode windows-1252: %v", err)
+	}
+	body = "\xef\xbb\xbf" + body
+
+	txs, report, err := Parse(context.Background(), strings.NewReader(body), Options{})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 2 || txs[0].Category != "Nómina" || txs[1].Category != "Súper" {
+		t.Errorf("unexpected transactions: %+v", txs)
+	}
+	if report.Dialect.Delimiter != ';' || !report.Dialect.BOM {
+		t.Errorf("report dialect = %+v", report.Dialect)
+	}
+}
@@ -0,0 +1,227 @@
+// Package csvparse contiene las reglas de parseo del CSV de transacciones,
+// independientes de dónde venga el archivo (S3, disco o una subida HTTP).
+package csvparse
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// batchSize acota cuántas filas se leen antes de parsearlas, así el modo
+// paralelo conserva el orden sin cargar el archivo completo.
+const batchSize = 512
+
+type Options struct {
+	// Workers > 1 parsea cada lote de filas en paralelo. El resultado
+	// conserva el orden del archivo.
+	Workers int
+	// CollectIssues sigue leyendo ante filas inválidas y las deja en el
+	// reporte; sin él, la primera fila inválida corta el parseo con error.
+	CollectIssues bool
+	// Mapping ubica los campos por nombre de columna; el valor cero es la
+	// cabecera Id,Date,Transaction[,Category].
+	Mapping fieldmap.Mapping
+	// Amounts es el formato regional de los montos; el valor cero es
+	// amountparse.Default.
+	Amounts amountparse.Rules
+}
+
+// Parse lee un CSV con cabecera; las columnas se ubican con opts.Mapping. La
+// codificación y el separador se detectan con Sniff y quedan en el reporte.
+// Una cabecera sin fecha o monto devuelve cero transacciones sin error y
+// queda en el reporte.
+func Parse(ctx context.Context, r io.Reader, opts Options) ([]domain.Transaction, domain.ParseReport, error) {
+	var report domain.ParseReport
+
+	dialect, r, err := Sniff(r)
+	if err != nil {
+		return nil, report, err
+	}
+	report.Dialect = dialect
+
+	reader := newReader(r, dialect, opts)
+	cols, ok, err := readHeader(reader, opts, &report)
+	if !ok || err != nil {
+		return nil, report, err
+	}
+
+	txs, err := parseRecords(ctx, reader, cols, opts, &report, 0)
+	if err != nil {
+		return nil, report, err
+	}
+	return txs, report, nil
+}
+
+func newReader(r io.Reader, dialect domain.Dialect, opts Options) *csv.Reader {
+	reader := csv.NewReader(r)
+	reader.Comma = dialect.Delimiter
+	if opts.CollectIssues {
+		reader.FieldsPerRecord = -1
+	}
+	return reader
+}
+
+// readHeader lee y resuelve la cabecera. ok es false si el archivo está vacío
+// o la cabecera no sirve; el motivo queda en el reporte.
+func readHeader(reader *csv.Reader, opts Options, report *domain.ParseReport) (fieldmap.Columns, bool, error) {
+	header, err := reader.Read()
+	if err == io.EOF {
+		report.Issues = append(report.Issues, domain.RowIssue{Line: 1, Message: "archivo vacío"})
+		return fieldmap.Columns{}, false, nil
+	}
+	if err != nil {
+		return fieldmap.Columns{}, false, err
+	}
+	report.Columns = header
+
+	cols, err := opts.Mapping.Resolve(header)
+	if err != nil {
+		report.Issues = append(report.Issues, domain.RowIssue{
+			Line:    1,
+			Message: "cabecera no reconocida: " + err.Error(),
+		})
+		return cols, false, nil
+	}
+	report.HeaderValid = true
+	return cols, true, nil
+}
+
+// parseRecords lee las filas que quedan en reader. lineOffset se suma a los
+// números de línea, para las lecturas que empiezan a mitad de archivo.
+func parseRecords(
+	ctx context.Context,
+	reader *csv.Reader,
+	cols fieldmap.Columns,
+	opts Options,
+	report *domain.ParseReport,
+	lineOffset int,
+) ([]domain.Transaction, error) {
+	var txs []domain.Transaction
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		batch, done, err := readBatch(reader, lineOffset)
+		if err != nil {
+			return nil, err
+		}
+		results := parseBatch(batch, cols, opts)
+
+		for i, res := range results {
+			row := batch[i]
+			report.TotalRows++
+			switch {
+			case res.skipped:
+				report.SkippedRows++
+				report.Issues = append(report.Issues, domain.RowIssue{
+					Line:    row.line,
+					Message: fmt.Sprintf("fila con %d columnas, se omite", len(row.record)),
+				})
+			case res.err != nil:
+				if !opts.CollectIssues {
+					return nil, res.err
+				}
+				report.Issues = append(report.Issues, domain.RowIssue{Line: row.line, Message: issueMessage(res.err)})
+			default:
+				report.ValidRows++
+				txs = append(txs, res.tx)
+			}
+		}
+
+		if done {
+			return txs, nil
+		}
+	}
+}
+
+type row struct {
+	line   int
+	record []string
+	err    error
+}
+
+type result struct {
+	tx      domain.Transaction
+	err     error
+	skipped bool
+}
+
+// readBatch lee hasta batchSize filas. Los errores de formato de una fila
+// (comillas, número de columnas) viajan con la fila; el resto corta la lectura.
+func readBatch(reader *csv.Reader, lineOffset int) ([]row, bool, error) {
+	batch := make([]row, 0, batchSize)
+	for len(batch) < batchSize {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return batch, true, nil
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			parseErr.StartLine += lineOffset
+			parseErr.Line += lineOffset
+			batch = append(batch, row{line: parseErr.Line, err: err})
+			continue
+		}
+		if err != nil {
+			return nil, false, err
+		}
+		line, _ := reader.FieldPos(0)
+		batch = append(batch, row{line: line + lineOffset, record: record})
+	}
+	return batch, false, nil
+}
+
+func parseBatch(batch []row, cols fieldmap.Columns, opts Options) []result {
+	results := make([]result, len(batch))
+	parse := func(i int) {
+		row := batch[i]
+		switch {
+		case row.err != nil:
+			results[i].err = row.err
+		case len(row.record) < cols.Width():
+			results[i].skipped = true
+		default:
+			results[i].tx, results[i].err = fieldmap.Transaction(func(f fieldmap.Field) fieldmap.Value {
+				return cols.Value(row.record, f)
+			}, fieldmap.MonthDay, opts.Amounts)
+		}
+	}
+
+	if opts.Workers <= 1 {
+		for i := range batch {
+			parse(i)
+		}
+		return results
+	}
+
+	var g errgroup.Group
+	g.SetLimit(opts.Workers)
+	for i := range batch {
+		g.Go(func() error {
+			parse(i)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+// issueMessage quita el prefijo "record on line N" de csv.ParseError: la
+// línea ya va en el RowIssue.
+func issueMessage(err error) string {
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return parseErr.Err.Error()
+	}
+	return err.Error()
+}
@@ -0,0 +1,178 @@
+package csvparse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+)
+
+func TestParse_ReturnsTransactionsAndReport(t *testing.T) {
+	body := "Id,Date,Transaction,Category\n0,7/15,+60.5,salary\n1,7/28,-10.3,groceries\n"
+
+	txs, report, err := Parse(context.Background(), strings.NewReader(body), Options{})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(txs))
+	}
+	if !txs[0].Date.Equal(time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)) || txs[1].Category != "groceries" {
+		t.Errorf("unexpected transactions: %+v", txs)
+	}
+	if !report.OK() || report.TotalRows != 2 || report.ValidRows != 2 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestParse_StopsAtFirstInvalidRow(t *testing.T) {
+	body := "Id,Date,Transaction\n0,7/15,+60.5\n1,7/30,abc\n2,13/45,1\n"
+
+	txs, report, err := Parse(context.Background(), strings.NewReader(body), Options{})
+	if err == nil {
+		t.Fatalf("expected an error for the invalid amount")
+	}
+	if txs != nil {
+		t.Errorf("expected no transactions on error, got %v", txs)
+	}
+	if report.ValidRows != 1 {
+		t.Errorf("report should stop at the failing row, got %+v", report)
+	}
+}
+
+func TestParse_CollectIssues(t *testing.T) {
+	body := "Id,Date,Transaction\n" +
+		"0,7/15,+60.5\n" +
+		"1,13/45,-10\n" +
+		"2,7/28\n" +
+		"3,\"7/30,1\n"
+
+	txs, report, err := Parse(context.Background(), strings.NewReader(body), Options{CollectIssues: true})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Errorf("expected the valid row to be returned, got %d transactions", len(txs))
+	}
+	if report.TotalRows != 4 || report.ValidRows != 1 || report.SkippedRows != 1 {
+		t.Errorf("rows total/valid/skipped = %d/%d/%d, want 4/1/1", report.TotalRows, report.ValidRows, report.SkippedRows)
+	}
+
+	wantLines := []int{3, 4, 5}
+	if len(report.Issues) != len(wantLines) {
+		t.Fatalf("issues = %+v", report.Issues)
+	}
+	for i, line := range wantLines {
+		if report.Issues[i].Line != line {
+			t.Errorf("issue %d line = %d, want %d", i, report.Issues[i].Line, line)
+		}
+	}
+	if strings.Contains(report.Issues[2].Message, "line") {
+		t.Errorf("CSV format issues should not repeat the line: %q", report.Issues[2].Message)
+	}
+}
+
+func TestParse_UnrecognizedHeader(t *testing.T) {
+	txs, report, err := Parse(context.Background(), strings.NewReader("Fecha,Monto\n7/15,1\n"), Options{})
+	if err != nil || txs != nil {
+		t.Fatalf("expected no transactions and no error, got %v / %v", txs, err)
+	}
+	if report.HeaderValid || len(report.Issues) != 1 || report.Issues[0].Line != 1 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestParse_EmptyInput(t *testing.T) {
+	txs, report, err := Parse(context.Background(), strings.NewReader(""), Options{})
+	if err != nil || txs != nil {
+		t.Fatalf("expected no transactions and no error, got %v / %v", txs, err)
+	}
+	if len(report.Issues) != 1 || report.Issues[0].Message != "archivo vacío" {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestParse_WorkersPreserveOrder(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("Id,Date,Transaction\n")
+	const rows = batchSize*2 + 7
+	for i := range rows {
+		fmt.Fprintf(&b, "%d,7/15,%d\n", i, i)
+	}
+
+	txs, report, err := Parse(context.Background(), strings.NewReader(b.String()), Options{Workers: 4})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != rows || report.ValidRows != rows {
+		t.Fatalf("expected %d transactions, got %d (report %+v)", rows, len(txs), report)
+	}
+	for i, tx := range txs {
+		if tx.Amount.IntPart() != int64(i) {
+			t.Fatalf("transaction %d has amount %s, order not preserved", i, tx.Amount)
+		}
+	}
+}
+
+func TestParse_WorkersReportFirstInvalidRowInFileOrder(t *testing.T) {
+	body := "Id,Date,Transaction\n0,7/15,1\n1,7/16,abc\n2,13/45,1\n"
+
+	_, _, err := Parse(context.Background(), strings.NewReader(body), Options{Workers: 4})
+	if err == nil || strings.Contains(err.Error(), "month") {
+		t.Fatalf("expected the amount error from line 3, got %v", err)
+	}
+}
+
+func TestParse_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := Parse(ctx, strings.NewReader("Id,Date,Transaction\n0,7/15,1\n"), Options{Workers: 2})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestParse_FieldMapping(t *testing.T) {
+	mapping, err := fieldmap.Parse("date=Fecha,amount=Monto,category=Categoria")
+	if err != nil {
+		t.Fatalf("fieldmap.Parse returned error: %v", err)
+	}
+	body := "Monto,Referencia,Fecha,Categoria\n-10.3,r-1,7/28,groceries\n"
+
+	txs, report, err := Parse(context.Background(), strings.NewReader(body), Options{Mapping: mapping})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 1 || !report.HeaderValid {
+		t.Fatalf("expected 1 transaction and a valid header, got %d (report %+v)", len(txs), report)
+	}
+	if txs[0].Amount.String() != "-10.3" || txs[0].Category != "groceries" || txs[0].Date.Month() != time.July {
+		t.Errorf("unexpected transaction: %+v", txs[0])
+	}
+}
+
+func TestParse_LocaleAmountsAndDebitCredit(t *testing.T) {
+	mapping, err := fieldmap.Parse("date=Fecha,debit=Cargo,credit=Abono")
+	if err != nil {
+		t.Fatalf("fieldmap.Parse returned error: %v", err)
+	}
+	amounts, err := amountparse.ParseRules("es-ES")
+	if err != nil {
+		t.Fatalf("amountparse.ParseRules returned error: %v", err)
+	}
+	body := "Fecha;Concepto;Cargo;Abono\n7/15;Nómina;;1.060,50 €\n7/28;Súper;\"1.234,56\";\n"
+
+	txs, _, err := Parse(context.Background(), strings.NewReader(body), Options{Mapping: mapping, Amounts: amounts})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 2 || txs[0].Amount.String() != "1060.5" || txs[1].Amount.String() != "-1234.56" {
+		t.Errorf("unexpected transactions: %+v", txs)
+	}
+}
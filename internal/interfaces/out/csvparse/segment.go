@@ -0,0 +1,123 @@
+package csvparse
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/transform"
+)
+
+// Layout es lo que hace falta saber de la cabecera para parsear un tramo del
+// archivo por separado.
+type Layout struct {
+	Dialect domain.Dialect
+	Header  []string
+	Columns fieldmap.Columns
+	// HeaderEnd es el offset, en los bytes originales, de la primera fila.
+	HeaderEnd int
+}
+
+// Splittable informa si el archivo se puede cortar en cualquier salto de
+// línea: en UTF-8 y Windows-1252 las comillas y los saltos son bytes ASCII que
+// no aparecen dentro de otro carácter; en UTF-16 sí.
+func (l Layout) Splittable() bool {
+	return l.Dialect.Encoding == encodingUTF8 || l.Dialect.Encoding == encodingWindows1252
+}
+
+// ParseHeader detecta el dialecto y lee la cabecera de head, el inicio del
+// archivo, que debe incluir la cabecera completa (ver HeaderEnd). Como en
+// Parse, una cabecera inválida no es un error: report.HeaderValid queda en
+// false y el motivo en el reporte.
+func ParseHeader(head []byte, opts Options) (Layout, domain.ParseReport, error) {
+	var report domain.ParseReport
+
+	dialect, r, err := sniff(bytes.NewReader(head), true)
+	if err != nil {
+		return Layout{}, report, err
+	}
+	report.Dialect = dialect
+
+	cols, ok, err := readHeader(newReader(r, dialect, opts), opts, &report)
+	if !ok || err != nil {
+		return Layout{}, report, err
+	}
+	return Layout{Dialect: dialect, Header: report.Columns, Columns: cols, HeaderEnd: HeaderEnd(head)}, report, nil
+}
+
+// HeaderEnd devuelve el offset que sigue al primer salto de línea fuera de
+// comillas, o -1 si no lo hay.
+func HeaderEnd(b []byte) int {
+	quoted := false
+	for i, c := range b {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case c == '\n' && !quoted:
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// ParseSegment parsea un tramo de filas completas de un archivo cuya cabecera
+// ya se leyó con ParseHeader. firstLine es la línea del archivo en la que
+// empieza el tramo, para que los errores la informen igual que Parse.
+func ParseSegment(ctx context.Context, segment []byte, layout Layout, firstLine int, opts Options) ([]domain.Transaction, error) {
+	var r io.Reader = bytes.NewReader(segment)
+	if layout.Dialect.Encoding == encodingWindows1252 {
+		r = transform.NewReader(r, charmap.Windows1252.NewDecoder())
+	}
+
+	reader := newReader(r, layout.Dialect, opts)
+	if !opts.CollectIssues {
+		// En Parse la cabecera fija la cantidad de columnas.
+		reader.FieldsPerRecord = len(layout.Header)
+	}
+
+	var report domain.ParseReport
+	return parseRecords(ctx, reader, layout.Columns, opts, &report, firstLine-1)
+}
+
+// RecordScan resume un tramo de bytes para cortarlo en filas completas sin
+// conocer todavía lo que lo precede. Como las comillas de un campo siempre van
+// de a pares (las escapadas son ""), un salto de línea está fuera de comillas
+// si antes de él hubo una cantidad par en todo el archivo; el tramo solo puede
+// contar las suyas, así que guarda el resultado para las dos paridades de
+// inicio posibles.
+type RecordScan struct {
+	Quotes   int
+	Newlines int
+	// Last[p] es el índice del último salto de línea fuera de comillas si el
+	// tramo empieza con paridad p (1: dentro de un campo entre comillas), o
+	// -1 si no hay ninguno.
+	Last [2]int
+	// LinesThrough[p] cuenta los saltos de línea hasta Last[p] inclusive.
+	LinesThrough [2]int
+}
+
+// ScanRecords recorre el tramo una vez; varios tramos se pueden escanear en
+// paralelo y combinar en orden con la paridad de Quotes.
+func ScanRecords(b []byte) RecordScan {
+	scan := RecordScan{Last: [2]int{-1, -1}}
+	for i := 0; ; {
+		j := bytes.IndexAny(b[i:], "\"\n")
+		if j < 0 {
+			return scan
+		}
+		i += j
+		if b[i] == '"' {
+			scan.Quotes++
+		} else {
+			scan.Newlines++
+			p := scan.Quotes % 2
+			scan.Last[p] = i
+			scan.LinesThrough[p] = scan.Newlines
+		}
+		i++
+	}
+}
@@ -0,0 +1,75 @@
+package csvparse
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestScanRecords(t *testing.T) {
+	// El tramo empieza a mitad de un campo entre comillas si lo precede una
+	// cantidad impar de comillas.
+	b := []byte("a\",x\n1,\"b\nc\"\n2,d")
+	scan := ScanRecords(b)
+
+	if scan.Quotes != 3 || scan.Newlines != 3 {
+		t.Fatalf("scan = %+v, want 3 quotes and 3 newlines", scan)
+	}
+	// Empezando fuera de comillas, `a"` abre un campo que cierra antes de b,
+	// así que el último salto fuera de comillas es el del índice 9.
+	if scan.Last[0] != 9 || scan.LinesThrough[0] != 2 {
+		t.Errorf("parity 0: Last=%d LinesThrough=%d, want 9 and 2", scan.Last[0], scan.LinesThrough[0])
+	}
+	if scan.Last[1] != 12 || scan.LinesThrough[1] != 3 {
+		t.Errorf("parity 1: Last=%d LinesThrough=%d, want 12 and 3", scan.Last[1], scan.LinesThrough[1])
+	}
+
+	if got := ScanRecords([]byte("\"a\nb")); got.Last != [2]int{-1, 2} {
+		t.Errorf("Last = %v, want [-1 2]", got.Last)
+	}
+}
+
+func TestHeaderEnd(t *testing.T) {
+	cases := map[string]int{
+		"Id,Date\n0,7/15\n":     8,
+		"\"Id\nx\",Date\r\n0\n": 13,
+		"Id,Date":               -1,
+		"\"Id\n":                -1,
+	}
+	for in, want := range cases {
+		if got := HeaderEnd([]byte(in)); got != want {
+			t.Errorf("HeaderEnd(%q) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestParseSegment(t *testing.T) {
+	full := "Id,Date,Transaction,Category\n0,7/15,+60.5,\"a\nb\"\n1,7/28,-10.3,c\n2,bad,+1,d\n"
+	layout, report, err := ParseHeader([]byte(full), Options{})
+	if err != nil || !report.HeaderValid {
+		t.Fatalf("ParseHeader: %v, %+v", err, report)
+	}
+	if layout.HeaderEnd != strings.Index(full, "0,") {
+		t.Errorf("HeaderEnd = %d", layout.HeaderEnd)
+	}
+
+	// Las filas 0 y 1 ocupan las líneas 2 a 4.
+	txs, err := ParseSegment(context.Background(), []byte(full[layout.HeaderEnd:strings.Index(full, "2,")]), layout, 2, Options{})
+	if err != nil {
+		t.Fatalf("ParseSegment error: %v", err)
+	}
+	if len(txs) != 2 || txs[0].Category != "a\nb" {
+		t.Fatalf("txs = %+v", txs)
+	}
+
+	// El error informa la línea del archivo, igual que Parse.
+	_, _, want := Parse(context.Background(), strings.NewReader(full), Options{})
+	_, got := ParseSegment(context.Background(), []byte(full[strings.Index(full, "2,"):]), layout, 5, Options{})
+	if want == nil || got == nil || got.Error() != want.Error() {
+		t.Fatalf("ParseSegment error = %v, want %v", got, want)
+	}
+
+	if _, err := ParseSegment(context.Background(), []byte("3,7/15\n"), layout, 6, Options{}); err == nil {
+		t.Error("expected an error for a row with missing columns")
+	}
+}
@@ -0,0 +1,288 @@
+package csvreader
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"slices"
+	"strings"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/camtparse"
+	"stori-challenge/internal/interfaces/out/csvparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+	"stori-challenge/internal/interfaces/out/jsonlparse"
+	"stori-challenge/internal/interfaces/out/ofxparse"
+	"stori-challenge/internal/interfaces/out/parquetparse"
+)
+
+type format int
+
+const (
+	formatCSV format = iota
+	formatGzip
+	formatZip
+	formatParquet
+)
+
+// parseConfig es lo que comparten los parsers de todos los formatos.
+type parseConfig struct {
+	workers int
+	mapping fieldmap.Mapping
+	amounts amountparse.Rules
+}
+
+func (c parseConfig) csvOptions() csvparse.Options {
+	return csvparse.Options{Workers: c.workers, Mapping: c.mapping, Amounts: c.amounts}
+}
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	// Cabecera local de archivo y fin de directorio central (zip vacío).
+	zipMagics = [][]byte{[]byte("PK\x03\x04"), []byte("PK\x05\x06")}
+)
+
+// detectFormat da prioridad a los magic bytes (un Content-Encoding gzip los
+// trae igual). Sin ellos, ese Content-Encoding significa que el cuerpo ya llegó
+// descomprimido; si no lo hay, manda el sufijo de la key.
+func detectFormat(key, contentEncoding string, head []byte) format {
+	for _, magic := range zipMagics {
+		if bytes.HasPrefix(head, magic) {
+			return formatZip
+		}
+	}
+	if bytes.HasPrefix(head, gzipMagic) {
+		return formatGzip
+	}
+	if parquetparse.Looks(head) {
+		return formatParquet
+	}
+
+	if strings.EqualFold(contentEncoding, "gzip") {
+		return formatCSV
+	}
+
+	lower := strings.ToLower(key)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return formatZip
+	case strings.HasSuffix(lower, ".gz"):
+		return formatGzip
+	case strings.HasSuffix(lower, ".parquet"):
+		return formatParquet
+	}
+	return formatCSV
+}
+
+// readFiles lee los extractos de un objeto ya abierto. Un zip o un Parquet
+// necesitan acceso aleatorio: se usa el archivo si body es uno y, si no, se
+// vuelca a un temporal.
+func readFiles(
+	ctx context.Context,
+	obj domain.ObjectRef,
+	body io.Reader,
+	contentEncoding string,
+	cfg parseConfig,
+) ([]domain.TransactionFile, error) {
+	br := bufio.NewReader(body)
+	head, _ := br.Peek(4)
+
+	kind := detectFormat(obj.Key, contentEncoding, head)
+	if kind != formatZip && obj.Entry != "" {
+		return nil, fmt.Errorf("%s: solo un zip tiene entradas", obj)
+	}
+
+	switch kind {
+	case formatGzip:
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", obj, err)
+		}
+		defer gz.Close()
+		name := obj.Key
+		if strings.EqualFold(path.Ext(name), ".gz") {
+			name = name[:len(name)-len(".gz")]
+		}
+		return []domain.TransactionFile{parseStatement(ctx, name, gz, cfg)}, nil
+
+	case formatZip:
+		if f, ok := body.(*os.File); ok {
+			return readZipFile(ctx, obj, f, cfg)
+		}
+		tmp, err := spool(br)
+		if err != nil {
+			return nil, err
+		}
+		defer removeSpooled(tmp)
+		return readZipFile(ctx, obj, tmp, cfg)
+
+	case formatParquet:
+		if f, ok := body.(*os.File); ok {
+			return []domain.TransactionFile{readParquetFile(ctx, f, cfg)}, nil
+		}
+		return []domain.TransactionFile{parseStatement(ctx, obj.Key, br, cfg)}, nil
+
+	default:
+		return []domain.TransactionFile{parseStatement(ctx, obj.Key, br, cfg)}, nil
+	}
+}
+
+// spool vuelca r a un archivo temporal; se libera con removeSpooled.
+func spool(r io.Reader) (*os.File, error) {
+	tmp, err := os.CreateTemp("", "stori-*")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		removeSpooled(tmp)
+		return nil, err
+	}
+	return tmp, nil
+}
+
+func removeSpooled(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
+
+type statementFormat int
+
+const (
+	statementCSV statementFormat = iota
+	statementOFX
+	statementParquet
+	statementJSONL
+	statementCamt
+)
+
+// detectStatement elige el formato de un extracto por la extensión del
+// archivo o, si no la tiene, por su contenido; todo lo demás es CSV.
+func detectStatement(name string, head []byte) statementFormat {
+	ext := strings.ToLower(path.Ext(name))
+	switch {
+	case ext == ".ofx" || ext == ".qfx" || ofxparse.Looks(head):
+		return statementOFX
+	case ext == ".parquet" || parquetparse.Looks(head):
+		return statementParquet
+	case ext == ".jsonl" || ext == ".ndjson" || jsonlparse.Looks(head):
+		return statementJSONL
+	case camtparse.Looks(head):
+		return statementCamt
+	default:
+		return statementCSV
+	}
+}
+
+// IsPlainCSV dice si un archivo se leería como CSV sin comprimir, con las
+// mismas reglas que ReadObjectFiles: ni zip, gzip o Parquet, ni un extracto
+// OFX, JSON Lines o camt.053.
+func IsPlainCSV(name string, content []byte) bool {
+	head := content[:min(len(content), sniffSize)]
+	return detectFormat(name, "", head) == formatCSV && detectStatement(name, head) == statementCSV
+}
+
+// parseStatement parsea un extracto con el parser de su formato. El archivo
+// devuelto no trae Entry.
+func parseStatement(ctx context.Context, name string, r io.Reader, cfg parseConfig) domain.TransactionFile {
+	br := bufio.NewReaderSize(r, sniffSize)
+	head, _ := br.Peek(sniffSize)
+
+	switch detectStatement(name, head) {
+	case statementOFX:
+		txs, err := ofxparse.Parse(ctx, br)
+		return domain.TransactionFile{Transactions: txs, Err: err}
+	case statementParquet:
+		tmp, err := spool(br)
+		if err != nil {
+			return domain.TransactionFile{Err: err}
+		}
+		defer removeSpooled(tmp)
+		return readParquetFile(ctx, tmp, cfg)
+	case statementJSONL:
+		txs, err := jsonlparse.Parse(ctx, br, cfg.mapping, cfg.amounts)
+		return domain.TransactionFile{Transactions: txs, Err: err}
+	case statementCamt:
+		file, err := camtparse.Parse(ctx, br)
+		file.Err = err
+		return file
+	default:
+		txs, report, err := csvparse.Parse(ctx, br, cfg.csvOptions())
+		return domain.TransactionFile{Transactions: txs, Dialect: report.Dialect, Err: err}
+	}
+}
+
+// sniffSize cubre la cabecera SGML de OFX 1.x y la declaración XML que
+// precede a <?OFX ...?> o al <Document> de camt.053.
+const sniffSize = 512
+
+func readParquetFile(ctx context.Context, f *os.File, cfg parseConfig) domain.TransactionFile {
+	info, err := f.Stat()
+	if err != nil {
+		return domain.TransactionFile{Err: err}
+	}
+	txs, err := parquetparse.Parse(ctx, f, info.Size(), cfg.mapping, cfg.amounts)
+	return domain.TransactionFile{Transactions: txs, Err: err}
+}
+
+func readZipFile(ctx context.Context, obj domain.ObjectRef, f *os.File, cfg parseConfig) ([]domain.TransactionFile, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	zr, err := zip.NewReader(f, info.Size())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", obj, err)
+	}
+
+	var files []domain.TransactionFile
+	for _, entry := range zr.File {
+		if !isStatementEntry(entry) || (obj.Entry != "" && entry.Name != obj.Entry) {
+			continue
+		}
+		rc, err := entry.Open()
+		if err != nil {
+			files = append(files, domain.TransactionFile{Entry: entry.Name, Err: err})
+			continue
+		}
+		file := parseStatement(ctx, entry.Name, rc, cfg)
+		rc.Close()
+		file.Entry = entry.Name
+		files = append(files, file)
+	}
+
+	switch {
+	case len(files) > 0:
+		return files, nil
+	case obj.Entry != "":
+		return nil, fmt.Errorf("%s: entrada inexistente", obj)
+	default:
+		return nil, fmt.Errorf("%s: el zip no contiene extractos reconocidos", obj)
+	}
+}
+
+// statementExts son las extensiones que se leen dentro de un zip.
+var statementExts = []string{".csv", ".xml", ".ofx", ".qfx", ".jsonl", ".ndjson", ".parquet"}
+
+// isStatementEntry descarta directorios, los metadatos de macOS y las
+// extensiones que no son de extractos.
+func isStatementEntry(f *zip.File) bool {
+	if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(path.Base(f.Name), "._") {
+		return false
+	}
+	return slices.Contains(statementExts, strings.ToLower(path.Ext(f.Name)))
+}
+
+// singleFile adapta ReadObjectFiles a los métodos que devuelven un solo archivo.
+func singleFile(obj domain.ObjectRef, files []domain.TransactionFile) ([]domain.Transaction, error) {
+	if len(files) != 1 {
+		return nil, fmt.Errorf("%s contiene %d extractos, se debe indicar la entrada", obj, len(files))
+	}
+	return files[0].Transactions, files[0].Err
+}
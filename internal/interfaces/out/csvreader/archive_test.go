@@ -0,0 +1,392 @@
+package csvreader
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/parquet-go/parquet-go"
+)
+
+const archiveCSV = "Id,Date,Transaction\n0,7/15,+60.5\n1,7/28,-10.3\n"
+
+func gzipBytes(t *testing.T, s string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write([]byte(s)); err != nil {
+		t.Fatalf("gzip write: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("gzip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zipBytes(t *testing.T, entries ...[2]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, e := range entries {
+		w, err := zw.Create(e[0])
+		if err != nil {
+			t.Fatalf("zip create: %v", err)
+		}
+		if _, err := w.Write([]byte(e[1])); err != nil {
+			t.Fatalf("zip write: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakeEncodedS3Client devuelve un cuerpo binario con su Content-Encoding.
+type fakeEncodedS3Client struct {
+	body     []byte
+	encoding *string
+}
+
+func (f *fakeEncodedS3Client) GetObject(
+	_ context.Context,
+	_ *s3.GetObjectInput,
+	_ ...func(*s3.Options),
+) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{
+		Body:            io.NopCloser(bytes.NewReader(f.body)),
+		ContentEncoding: f.encoding,
+	}, nil
+}
+
+func TestDetectFormat(t *testing.T) {
+	gz := gzipBytes(t, archiveCSV)[:4]
+	zipped := zipBytes(t, [2]string{"a.csv", archiveCSV})[:4]
+	plain := []byte("Id,D")
+	gzipEncoding := "gzip"
+
+	cases := []struct {
+		name     string
+		key      string
+		encoding string
+		head     []byte
+		want     format
+	}{
+		{"gzip magic without suffix", "export", "", gz, formatGzip},
+		{"zip magic without suffix", "export", "", zipped, formatZip},
+		{"gz suffix", "export.csv.gz", "", plain, formatGzip},
+		{"zip suffix", "export.ZIP", "", plain, formatZip},
+		{"decoded in transit", "export.csv.gz", gzipEncoding, plain, formatCSV},
+		{"encoded body", "export.csv", gzipEncoding, gz, formatGzip},
+		{"plain csv", "export.csv", "", plain, formatCSV},
+	}
+	for _, tc := range cases {
+		if got := detectFormat(tc.key, tc.encoding, tc.head); got != tc.want {
+			t.Errorf("%s: detectFormat = %d, want %d", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestIsPlainCSV(t *testing.T) {
+	cases := []struct {
+		name    string
+		content []byte
+		want    bool
+	}{
+		{"txns.csv", []byte(archiveCSV), true},
+		{"export", []byte(archiveCSV), true},
+		{"txns.csv.gz", gzipBytes(t, archiveCSV), false},
+		{"export", zipBytes(t, [2]string{"a.csv", archiveCSV}), false},
+		{"txns.ofx", []byte("OFXHEADER:100"), false},
+		{"txns.jsonl", []byte(`{"date":"2021-07-15","amount":"1"}`), false},
+		{"export", []byte(`<?xml version="1.0"?><Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08">`), false},
+		{"txns.csv", nil, true},
+	}
+	for _, tc := range cases {
+		if got := IsPlainCSV(tc.name, tc.content); got != tc.want {
+			t.Errorf("IsPlainCSV(%q, %.20q) = %v, want %v", tc.name, tc.content, got, tc.want)
+		}
+	}
+}
+
+func TestS3CSVReader_ReadsGzip(t *testing.T) {
+	encoding := "gzip"
+	for name, client := range map[string]*fakeEncodedS3Client{
+		"suffix":           {body: gzipBytes(t, archiveCSV)},
+		"content-encoding": {body: gzipBytes(t, archiveCSV), encoding: &encoding},
+	} {
+		reader := NewS3CSVReader(client)
+		txs, err := reader.ReadTransactionsFromObjectParallel(context.Background(), domain.ObjectRef{Bucket: "b", Key: "input/txns.csv.gz"})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if len(txs) != 2 {
+			t.Fatalf("%s: expected 2 transactions, got %d", name, len(txs))
+		}
+		assertDecEq2(t, txs[1].Amount, dec("-10.3"), name+" amount")
+	}
+}
+
+func TestS3CSVReader_CorruptGzipFails(t *testing.T) {
+	reader := NewS3CSVReader(&fakeEncodedS3Client{body: []byte("not gzip at all")})
+
+	_, err := reader.ReadTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "b", Key: "txns.csv.gz"})
+	if err == nil {
+		t.Fatalf("expected an error for a .gz key without gzip content")
+	}
+}
+
+func TestS3CSVReader_ReadObjectFiles_Zip(t *testing.T) {
+	body := zipBytes(t,
+		[2]string{"acc-1/txns.csv", archiveCSV},
+		[2]string{"README.txt", "ignored"},
+		[2]string{"__MACOSX/acc-1/._txns.csv", "ignored"},
+		[2]string{"acc-2/txns.csv", "Id,Date,Transaction\n0,7/15,abc\n"},
+	)
+	reader := NewS3CSVReader(&fakeEncodedS3Client{body: body})
+	obj := domain.ObjectRef{Bucket: "b", Key: "input/export.zip"}
+
+	files, err := reader.ReadObjectFiles(context.Background(), obj)
+	if err != nil {
+		t.Fatalf("ReadObjectFiles returned error: %v", err)
+	}
+	if len(files) != 2 || files[0].Entry != "acc-1/txns.csv" || files[1].Entry != "acc-2/txns.csv" {
+		t.Fatalf("unexpected entries: %+v", files)
+	}
+	if len(files[0].Transactions) != 2 || files[0].Err != nil {
+		t.Errorf("acc-1 = %+v", files[0])
+	}
+	if files[1].Err == nil {
+		t.Errorf("acc-2 should carry its parse error")
+	}
+
+	if _, err := reader.ReadTransactionsFromObject(context.Background(), obj); err == nil {
+		t.Errorf("reading a multi-file zip as a single CSV should fail")
+	}
+	txs, err := reader.ReadTransactionsFromObject(context.Background(), obj.WithEntry("acc-1/txns.csv"))
+	if err != nil || len(txs) != 2 {
+		t.Errorf("reading one entry: %d transactions, err %v", len(txs), err)
+	}
+	if _, err := reader.ReadObjectFiles(context.Background(), obj.WithEntry("missing.csv")); err == nil {
+		t.Errorf("expected an error for a missing entry")
+	}
+}
+
+func TestS3CSVReader_ReadObjectFiles_RecordsDialect(t *testing.T) {
+	// "Café" en Windows-1252, con BOM y punto y coma como lo exporta Excel.
+	excel := "\xef\xbb\xbfId;Date;Transaction;Category\r\n0;7/15;+60.5;Caf\xe9\r\n"
+	body := zipBytes(t,
+		[2]string{"acc-1/txns.csv", archiveCSV},
+		[2]string{"acc-2/txns.csv", excel},
+	)
+	reader := NewS3CSVReader(&fakeEncodedS3Client{body: body})
+
+	files, err := reader.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "b", Key: "input/export.zip"})
+	if err != nil {
+		t.Fatalf("ReadObjectFiles returned error: %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("unexpected entries: %+v", files)
+	}
+	if want := (domain.Dialect{Encoding: "UTF-8", Delimiter: ','}); files[0].Dialect != want {
+		t.Errorf("acc-1 dialect = %+v, want %+v", files[0].Dialect, want)
+	}
+	if want := (domain.Dialect{Encoding: "windows-1252", BOM: true, Delimiter: ';'}); files[1].Dialect != want {
+		t.Errorf("acc-2 dialect = %+v, want %+v", files[1].Dialect, want)
+	}
+	if files[1].Err != nil || len(files[1].Transactions) != 1 || files[1].Transactions[0].Category != "Café" {
+		t.Errorf("acc-2 = %+v", files[1])
+	}
+}
+
+func TestS3CSVReader_ZipWithoutCSV(t *testing.T) {
+	reader := NewS3CSVReader(&fakeEncodedS3Client{body: zipBytes(t, [2]string{"notes.txt", "x"})})
+
+	if _, err := reader.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "b", Key: "export.zip"}); err == nil {
+		t.Fatalf("expected an error for a zip without CSV files")
+	}
+}
+
+func TestFSCSVReader_ReadObjectFiles_ZipAndGzip(t *testing.T) {
+	root := t.TempDir()
+	dir := filepath.Join(root, "bucket", "input")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	zipped := zipBytes(t, [2]string{"a.csv", archiveCSV}, [2]string{"b.csv", archiveCSV})
+	if err := os.WriteFile(filepath.Join(dir, "export.zip"), zipped, 0o644); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "txns.csv.gz"), gzipBytes(t, archiveCSV), 0o644); err != nil {
+		t.Fatalf("write gzip: %v", err)
+	}
+
+	r := NewFSCSVReader(root)
+
+	files, err := r.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/export.zip"})
+	if err != nil || len(files) != 2 {
+		t.Fatalf("zip: %d files, err %v", len(files), err)
+	}
+
+	files, err = r.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/txns.csv.gz"})
+	if err != nil || len(files) != 1 || files[0].Entry != "" || len(files[0].Transactions) != 2 {
+		t.Fatalf("gzip: %+v, err %v", files, err)
+	}
+}
+
+const archiveOFX = `OFXHEADER:100
+DATA:OFXSGML
+
+<OFX><BANKTRANLIST>
+<STMTTRN><DTPOSTED>20210715<TRNAMT>60.50<FITID>1<NAME>Payroll</STMTTRN>
+<STMTTRN><DTPOSTED>20210728<TRNAMT>-10.30<FITID>2<NAME>Market</STMTTRN>
+</BANKTRANLIST></OFX>
+`
+
+func TestS3CSVReader_ReadsOFX(t *testing.T) {
+	for _, key := range []string{"input/statement.qfx", "input/statement.txt", "input/statement.ofx.gz"} {
+		body := []byte(archiveOFX)
+		if filepath.Ext(key) == ".gz" {
+			body = gzipBytes(t, archiveOFX)
+		}
+		reader := NewS3CSVReader(&fakeEncodedS3Client{body: body})
+
+		txs, err := reader.ReadTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "b", Key: key})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", key, err)
+		}
+		if len(txs) != 2 || txs[1].Reference != "2" || txs[1].Description != "Market" {
+			t.Fatalf("%s: unexpected transactions: %+v", key, txs)
+		}
+		assertDecEq2(t, txs[1].Amount, dec("-10.3"), key+" amount")
+	}
+}
+
+func TestS3CSVReader_ZipMixesCSVAndOFX(t *testing.T) {
+	body := zipBytes(t,
+		[2]string{"acc-1/txns.csv", archiveCSV},
+		[2]string{"acc-2/statement.OFX", archiveOFX},
+	)
+	reader := NewS3CSVReader(&fakeEncodedS3Client{body: body})
+
+	files, err := reader.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "b", Key: "export.zip"})
+	if err != nil {
+		t.Fatalf("ReadObjectFiles returned error: %v", err)
+	}
+	if len(files) != 2 || files[1].Entry != "acc-2/statement.OFX" || files[1].Err != nil || len(files[1].Transactions) != 2 {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+}
+
+func TestS3CSVReader_ReadObjectFiles_Camt053(t *testing.T) {
+	body := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.08"><BkToCstmrStmt><Stmt>
+<Bal><Tp><CdOrPrtry><Cd>OPBD</Cd></CdOrPrtry></Tp><Amt Ccy="MXN">100</Amt><CdtDbtInd>CRDT</CdtDbtInd></Bal>
+<Ntry><Amt Ccy="MXN">10.30</Amt><CdtDbtInd>DBIT</CdtDbtInd><BookgDt><Dt>2021-07-28</Dt></BookgDt></Ntry>
+</Stmt></BkToCstmrStmt></Document>`
+	reader := NewS3CSVReader(&fakeEncodedS3Client{body: []byte(body)})
+
+	files, err := reader.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "b", Key: "input/acc-1/statement.xml"})
+	if err != nil {
+		t.Fatalf("ReadObjectFiles returned error: %v", err)
+	}
+	if len(files) != 1 || files[0].Err != nil || len(files[0].Transactions) != 1 {
+		t.Fatalf("unexpected files: %+v", files)
+	}
+	assertDecEq2(t, files[0].Transactions[0].Amount, dec("-10.3"), "camt amount")
+	if files[0].OpeningBalance == nil {
+		t.Fatalf("expected the opening balance from Bal")
+	}
+	assertDecEq2(t, *files[0].OpeningBalance, dec("100"), "opening balance")
+}
+
+type parquetRow struct {
+	Fecha string  `parquet:"fecha"`
+	Monto float64 `parquet:"monto"`
+}
+
+func parquetBytes(t *testing.T, rows ...parquetRow) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[parquetRow](&buf)
+	if _, err := w.Write(rows); err != nil {
+		t.Fatalf("parquet write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("parquet close: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestS3CSVReader_ReadsJSONLinesAndParquet(t *testing.T) {
+	mapping, err := fieldmap.Parse("date=fecha,amount=monto")
+	if err != nil {
+		t.Fatalf("fieldmap.Parse returned error: %v", err)
+	}
+	jsonl := "{\"fecha\":\"2021-07-15\",\"monto\":60.5}\n{\"fecha\":\"2021-07-28\",\"monto\":-10.3}\n"
+	pq := parquetBytes(t, parquetRow{"2021-07-15", 60.5}, parquetRow{"2021-07-28", -10.3})
+
+	for key, body := range map[string][]byte{
+		"input/txns.jsonl":   []byte(jsonl),
+		"input/txns":         []byte(jsonl),
+		"input/txns.parquet": pq,
+		"input/export.bin":   pq,
+	} {
+		reader := NewS3CSVReader(&fakeEncodedS3Client{body: body}, WithFieldMapping(mapping))
+
+		txs, err := reader.ReadTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "b", Key: key})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", key, err)
+		}
+		if len(txs) != 2 {
+			t.Fatalf("%s: expected 2 transactions, got %d", key, len(txs))
+		}
+		assertDecEq2(t, txs[1].Amount, dec("-10.3"), key+" amount")
+	}
+}
+
+func TestFSCSVReader_ReadsParquetFromDiskAndZip(t *testing.T) {
+	mapping, err := fieldmap.Parse("date=fecha,amount=monto")
+	if err != nil {
+		t.Fatalf("fieldmap.Parse returned error: %v", err)
+	}
+	root := t.TempDir()
+	dir := filepath.Join(root, "bucket", "input")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	pq := parquetBytes(t, parquetRow{"2021-07-15", 60.5})
+	if err := os.WriteFile(filepath.Join(dir, "txns.parquet"), pq, 0o644); err != nil {
+		t.Fatalf("write parquet: %v", err)
+	}
+	zipped := zipBytes(t, [2]string{"acc-1/txns.parquet", string(pq)}, [2]string{"acc-2/txns.jsonl", "{\"fecha\":\"2021-07-16\",\"monto\":1}\n"})
+	if err := os.WriteFile(filepath.Join(dir, "export.zip"), zipped, 0o644); err != nil {
+		t.Fatalf("write zip: %v", err)
+	}
+
+	r := NewFSCSVReader(root, WithFieldMapping(mapping))
+
+	files, err := r.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/txns.parquet"})
+	if err != nil || len(files) != 1 || files[0].Err != nil || len(files[0].Transactions) != 1 {
+		t.Fatalf("parquet: %+v, err %v", files, err)
+	}
+
+	files, err = r.ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "input/export.zip"})
+	if err != nil || len(files) != 2 {
+		t.Fatalf("zip: %+v, err %v", files, err)
+	}
+	for _, f := range files {
+		if f.Err != nil || len(f.Transactions) != 1 {
+			t.Errorf("zip entry %s: %+v", f.Entry, f)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package csvreader
+
+import (
+	"context"
+	"io"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+	"stori-challenge/internal/interfaces/out/csvparse"
+)
+
+// workerCount es el paralelismo de las lecturas *Parallel.
+const workerCount = 5
+
+// CSVParser expone las reglas de csvparse para contenido que no viene de un
+// bucket (por ejemplo, una subida por HTTP).
+type CSVParser struct {
+	opts readerOptions
+}
+
+var _ out.TransactionParser = CSVParser{}
+
+// NewCSVParser acepta las opciones de los lectores, así una subida se lee con
+// el mismo mapeo de columnas y formato de montos que un objeto del bucket.
+func NewCSVParser(opts ...ReaderOption) CSVParser {
+	return CSVParser{opts: newReaderOptions(opts)}
+}
+
+func (p CSVParser) ParseTransactions(ctx context.Context, r io.Reader) ([]domain.Transaction, error) {
+	txs, _, err := csvparse.Parse(ctx, r, p.csvOptions())
+	return txs, err
+}
+
+// Validate recorre todo el archivo y reporta cada fila con problemas. Las
+// filas con menos de tres columnas se cuentan como omitidas.
+func (p CSVParser) Validate(r io.Reader) (domain.ParseReport, error) {
+	opts := p.csvOptions()
+	opts.CollectIssues = true
+	_, report, err := csvparse.Parse(context.Background(), r, opts)
+	return report, err
+}
+
+func (p CSVParser) csvOptions() csvparse.Options {
+	return p.opts.parseConfig(0).csvOptions()
+}
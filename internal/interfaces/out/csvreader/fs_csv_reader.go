@@ -0,0 +1,104 @@
+package csvreader
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+)
+
+// FSCSVReader lee los objetos desde disco con las mismas reglas que
+// S3CSVReader.
+type FSCSVReader struct {
+	open func(obj domain.ObjectRef) (*os.File, error)
+	opts readerOptions
+}
+
+var _ out.TransactionFileReader = (*FSCSVReader)(nil)
+
+// NewFSCSVReader resuelve bucket/key como <root>/<bucket>/<key>. Sirve para
+// partners on-prem y para correr el pipeline completo sin AWS.
+func NewFSCSVReader(root string, opts ...ReaderOption) *FSCSVReader {
+	return &FSCSVReader{
+		open: func(obj domain.ObjectRef) (*os.File, error) {
+			return openUnderRoot(root, obj)
+		},
+		opts: newReaderOptions(opts),
+	}
+}
+
+// NewLocalFileReader usa la key como ruta del archivo e ignora el bucket (la
+// CLI procesa así archivos sueltos).
+func NewLocalFileReader(opts ...ReaderOption) *FSCSVReader {
+	return &FSCSVReader{
+		open: func(obj domain.ObjectRef) (*os.File, error) {
+			return os.Open(filepath.FromSlash(obj.Key))
+		},
+		opts: newReaderOptions(opts),
+	}
+}
+
+func (r *FSCSVReader) ReadTransactionsFromObject(
+	ctx context.Context,
+	obj domain.ObjectRef,
+) ([]domain.Transaction, error) {
+	files, err := r.readObjectFiles(ctx, obj, 1)
+	if err != nil {
+		return nil, err
+	}
+	return singleFile(obj, files)
+}
+
+func (r *FSCSVReader) ReadTransactionsFromObjectParallel(
+	ctx context.Context,
+	obj domain.ObjectRef,
+) ([]domain.Transaction, error) {
+	files, err := r.readObjectFiles(ctx, obj, workerCount)
+	if err != nil {
+		return nil, err
+	}
+	return singleFile(obj, files)
+}
+
+func (r *FSCSVReader) ReadObjectFiles(
+	ctx context.Context,
+	obj domain.ObjectRef,
+) ([]domain.TransactionFile, error) {
+	return r.readObjectFiles(ctx, obj, workerCount)
+}
+
+func (r *FSCSVReader) readObjectFiles(
+	ctx context.Context,
+	obj domain.ObjectRef,
+	workers int,
+) ([]domain.TransactionFile, error) {
+	if obj.VersionID != "" {
+		return nil, fmt.Errorf("el lector de disco no soporta versiones: %s", obj)
+	}
+	f, err := r.open(obj)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readFiles(ctx, obj, f, "", r.opts.parseConfig(workers))
+}
+
+// openUnderRoot abre el archivo a través de os.Root, así una key con ".." o
+// un symlink no puede salir del directorio raíz.
+func openUnderRoot(dir string, obj domain.ObjectRef) (*os.File, error) {
+	if obj.Bucket == "" || obj.Key == "" {
+		return nil, fmt.Errorf("objeto inválido: %s", obj)
+	}
+
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	return root.Open(filepath.Join(obj.Bucket, filepath.FromSlash(obj.Key)))
+}
@@ -0,0 +1,84 @@
+package csvreader
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("write file: %v", err)
+	}
+}
+
+const fsCSV = "Id,Date,Transaction\n0,7/15,+60.5\n1,7/28,-10.3\n2,8/2,-20.46\n"
+
+func TestFSCSVReader_ReadsBucketKeyUnderRoot(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "bucket", "input", "acc-1", "txns.csv"), fsCSV)
+
+	r := NewFSCSVReader(root)
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/txns.csv"}
+
+	txs, err := r.ReadTransactionsFromObject(context.Background(), obj)
+	if err != nil {
+		t.Fatalf("ReadTransactionsFromObject returned error: %v", err)
+	}
+	if len(txs) != 3 {
+		t.Fatalf("expected 3 transactions, got %d", len(txs))
+	}
+	assertDecEq2(t, txs[0].Amount, dec("60.5"), "first amount")
+
+	par, err := r.ReadTransactionsFromObjectParallel(context.Background(), obj)
+	if err != nil {
+		t.Fatalf("ReadTransactionsFromObjectParallel returned error: %v", err)
+	}
+	if len(par) != 3 {
+		t.Fatalf("expected 3 transactions from the parallel reader, got %d", len(par))
+	}
+}
+
+func TestFSCSVReader_MissingFile(t *testing.T) {
+	r := NewFSCSVReader(t.TempDir())
+
+	_, err := r.ReadTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "nope.csv"})
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("expected fs.ErrNotExist, got %v", err)
+	}
+}
+
+func TestFSCSVReader_RejectsKeysOutsideRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "secret.csv"), fsCSV)
+	root := filepath.Join(dir, "root")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	r := NewFSCSVReader(root)
+	_, err := r.ReadTransactionsFromObjectParallel(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "../../secret.csv"})
+	if err == nil {
+		t.Fatalf("expected an error for a key escaping the root")
+	}
+}
+
+func TestFSCSVReader_RejectsVersions(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "bucket", "txns.csv"), fsCSV)
+
+	r := NewFSCSVReader(root)
+	_, err := r.ReadTransactionsFromObject(context.Background(), domain.ObjectRef{Bucket: "bucket", Key: "txns.csv", VersionID: "v1"})
+	if err == nil {
+		t.Fatalf("expected an error when a version is requested")
+	}
+}
@@ -0,0 +1,52 @@
+package csvreader
+
+import (
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+)
+
+// ReaderOption ajusta S3CSVReader y FSCSVReader.
+type ReaderOption func(*readerOptions)
+
+type readerOptions struct {
+	mapping fieldmap.Mapping
+	amounts amountparse.Rules
+	ranged  rangeOptions
+}
+
+// WithFieldMapping ubica las columnas de CSV, JSON Lines y Parquet con un
+// mapeo distinto del CSV original.
+func WithFieldMapping(m fieldmap.Mapping) ReaderOption {
+	return func(o *readerOptions) {
+		o.mapping = m
+	}
+}
+
+// WithAmountRules lee los montos de texto con un formato regional distinto
+// de amountparse.Default.
+func WithAmountRules(r amountparse.Rules) ReaderOption {
+	return func(o *readerOptions) {
+		o.amounts = r
+	}
+}
+
+// WithRangedReads hace que S3CSVReader lea los CSV más grandes que partSize
+// pidiendo rangos de bytes en paralelo, hasta concurrency a la vez. Solo
+// aplica a las lecturas paralelas; los demás lectores la ignoran.
+func WithRangedReads(partSize int64, concurrency int) ReaderOption {
+	return func(o *readerOptions) {
+		o.ranged = rangeOptions{partSize: partSize, concurrency: max(concurrency, 1)}
+	}
+}
+
+func newReaderOptions(opts []ReaderOption) readerOptions {
+	var o readerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (o readerOptions) parseConfig(workers int) parseConfig {
+	return parseConfig{workers: workers, mapping: o.mapping, amounts: o.amounts}
+}
@@ -0,0 +1,346 @@
+package csvreader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/csvparse"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"golang.org/x/sync/errgroup"
+)
+
+type rangeOptions struct {
+	partSize    int64
+	concurrency int
+}
+
+func (o rangeOptions) enabled() bool {
+	return o.partSize > 0
+}
+
+// readRanged lee un CSV grande por rangos: el primer pedido trae partSize
+// bytes y el tamaño del objeto; el resto se pide en paralelo. ok es false si
+// el objeto no se puede leer así (no es un CSV plano, está comprimido o en
+// UTF-16) y hay que leerlo entero.
+func (r *S3CSVReader) readRanged(
+	ctx context.Context,
+	obj domain.ObjectRef,
+	cfg parseConfig,
+) (files []domain.TransactionFile, ok bool, err error) {
+	partSize := r.opts.ranged.partSize
+
+	in := getObjectInput(obj)
+	in.Range = aws.String(byteRange(0, partSize))
+	resp, err := r.s3Client.GetObject(ctx, in)
+	if err != nil {
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "InvalidRange" {
+			// Un objeto vacío no admite rangos.
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	size, known := objectSize(aws.ToString(resp.ContentRange))
+	if !known || size <= partSize {
+		// El servidor ignoró el rango o el objeto entero entró en el primer
+		// pedido: se lee como siempre.
+		files, err := readFiles(ctx, obj, resp.Body, aws.ToString(resp.ContentEncoding), cfg)
+		return files, true, err
+	}
+
+	first := make([]byte, partSize)
+	if _, err := io.ReadFull(resp.Body, first); err != nil {
+		return nil, false, fmt.Errorf("%s: %w", obj, err)
+	}
+	if aws.ToString(resp.ContentEncoding) != "" ||
+		detectFormat(obj.Key, "", first) != formatCSV ||
+		detectStatement(obj.Key, first[:min(len(first), sniffSize)]) != statementCSV ||
+		csvparse.HeaderEnd(first) < 0 {
+		return nil, false, nil
+	}
+
+	opts := cfg.csvOptions()
+	layout, report, err := csvparse.ParseHeader(first, opts)
+	if err != nil || !report.HeaderValid {
+		return []domain.TransactionFile{{Dialect: report.Dialect, Err: err}}, true, nil
+	}
+	if !layout.Splittable() {
+		return nil, false, nil
+	}
+
+	// Todos los rangos deben ser de la misma versión del objeto. Los tramos ya
+	// se parsean en paralelo, así que cada uno lo hace de corrido.
+	opts.Workers = 1
+	ranges := rangedObject{reader: r, obj: obj, size: size, buffered: &bufferedBytes{}}
+	if obj.VersionID == "" {
+		ranges.etag = resp.ETag
+	}
+	txs, parseErr, err := ranges.parse(ctx, first, layout, opts)
+	if err != nil {
+		return nil, false, fmt.Errorf("%s: %w", obj, err)
+	}
+	return []domain.TransactionFile{{Transactions: txs, Dialect: layout.Dialect, Err: parseErr}}, true, nil
+}
+
+// byteRange arma el header Range de [start, end).
+func byteRange(start, end int64) string {
+	return fmt.Sprintf("bytes=%d-%d", start, end-1)
+}
+
+// objectSize lee el tamaño total de un Content-Range ("bytes 0-99/1234").
+func objectSize(contentRange string) (int64, bool) {
+	_, total, ok := strings.Cut(contentRange, "/")
+	if !ok {
+		return 0, false
+	}
+	size, err := strconv.ParseInt(total, 10, 64)
+	return size, err == nil
+}
+
+type rangedObject struct {
+	reader   *S3CSVReader
+	obj      domain.ObjectRef
+	etag     *string
+	size     int64
+	buffered *bufferedBytes
+}
+
+// fetchedPart es un rango ya descargado y escaneado.
+type fetchedPart struct {
+	data []byte
+	scan csvparse.RecordScan
+}
+
+// segment es un tramo de filas completas y su resultado parcial.
+type segment struct {
+	data      []byte
+	firstLine int
+	txs       []domain.Transaction
+	err       error
+}
+
+// parse descarga los rangos que siguen a first y los corta en tramos de
+// filas completas que se parsean en paralelo; los resultados parciales se
+// unen en el orden del archivo. parseErr es el error de la primera fila
+// inválida, como en la lectura secuencial; err, el de la descarga.
+//
+// Hay dos etapas acotadas por concurrency: descargas (cada una escanea su
+// rango con csvparse.ScanRecords) y parseos. Entre ellas, un único costurero
+// recorre los rangos en orden: con la paridad de comillas acumulada toma del
+// escaneo el último salto de línea fuera de comillas, arma el tramo con lo
+// que sobró del rango anterior y pasa el resto al siguiente.
+//
+// El texto crudo retenido queda acotado: hasta concurrency+1 partes sin
+// coser, concurrency+1 tramos sin parsear (cada uno de a lo sumo dos partes
+// si ninguna fila es más larga que una parte) y el sobrante, o sea unas
+// 3×concurrency+4 partes sin importar el tamaño del archivo. Las
+// transacciones, en cambio, se devuelven todas: el pipeline guarda cada fila,
+// así que esa parte de la memoria crece con el archivo.
+func (o rangedObject) parse(
+	ctx context.Context,
+	first []byte,
+	layout csvparse.Layout,
+	opts csvparse.Options,
+) ([]domain.Transaction, error, error) {
+	ranged := o.reader.opts.ranged
+	partSize := ranged.partSize
+	parts := int((o.size + partSize - 1) / partSize)
+
+	g, gctx := errgroup.WithContext(ctx)
+	fetched := make([]chan fetchedPart, parts)
+	for i := range fetched {
+		fetched[i] = make(chan fetchedPart, 1)
+	}
+	fetchSlots := make(chan struct{}, ranged.concurrency)
+	parseSlots := make(chan struct{}, ranged.concurrency)
+
+	g.Go(func() error {
+		for i := 1; i < parts; i++ {
+			select {
+			case fetchSlots <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+			start := int64(i) * partSize
+			end := min(start+partSize, o.size)
+			g.Go(func() error {
+				data, err := o.fetch(gctx, start, end)
+				if err != nil {
+					return err
+				}
+				o.buffered.add(len(data))
+				fetched[i] <- fetchedPart{data: data, scan: csvparse.ScanRecords(data)}
+				return nil
+			})
+		}
+		return nil
+	})
+
+	var (
+		segments []*segment
+		failures failedSegments
+	)
+	g.Go(func() error {
+		line := 1 + bytes.Count(first[:layout.HeaderEnd], []byte("\n"))
+		var (
+			carry      []byte
+			carryLines int
+			parity     int
+		)
+
+		emit := func(data []byte, lines int) error {
+			select {
+			case parseSlots <- struct{}{}:
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+			seg := &segment{data: data, firstLine: line}
+			index := len(segments)
+			segments = append(segments, seg)
+			line += lines
+			g.Go(func() error {
+				defer func() { <-parseSlots }()
+				if failures.before(index) {
+					o.buffered.add(-len(seg.data))
+					return nil
+				}
+				seg.txs, seg.err = csvparse.ParseSegment(gctx, seg.data, layout, seg.firstLine, opts)
+				o.buffered.add(-len(seg.data))
+				seg.data = nil
+				if seg.err != nil {
+					failures.add(index)
+				}
+				return nil
+			})
+			return nil
+		}
+
+		// stitch copia data al tramo y al sobrante, así la parte se libera.
+		stitch := func(data []byte, scan csvparse.RecordScan) error {
+			last := scan.Last[parity]
+			if last < 0 {
+				// Una fila más larga que la parte: se junta con la siguiente.
+				carry = append(carry, data...)
+				carryLines += scan.Newlines
+				parity = (parity + scan.Quotes) % 2
+				return nil
+			}
+			seg := make([]byte, 0, len(carry)+last+1)
+			seg = append(append(seg, carry...), data[:last+1]...)
+			lines := carryLines + scan.LinesThrough[parity]
+
+			prevCarry := len(carry)
+			carry = append([]byte(nil), data[last+1:]...)
+			carryLines = scan.Newlines - scan.LinesThrough[parity]
+			parity = (parity + scan.Quotes) % 2
+			o.buffered.add(len(seg) + len(carry) - prevCarry - len(data))
+			return emit(seg, lines)
+		}
+
+		body := first[layout.HeaderEnd:]
+		o.buffered.add(len(body))
+		if err := stitch(body, csvparse.ScanRecords(body)); err != nil {
+			return err
+		}
+		for i := 1; i < parts; i++ {
+			select {
+			case part := <-fetched[i]:
+				<-fetchSlots
+				if err := stitch(part.data, part.scan); err != nil {
+					return err
+				}
+			case <-gctx.Done():
+				return gctx.Err()
+			}
+		}
+		if len(carry) > 0 {
+			// La última fila, sin salto de línea final.
+			return emit(carry, carryLines)
+		}
+		return nil
+	})
+
+	if err := g.Wait(); err != nil {
+		return nil, nil, err
+	}
+
+	total := 0
+	for _, seg := range segments {
+		if seg.err != nil {
+			return nil, seg.err, nil
+		}
+		total += len(seg.txs)
+	}
+	txs := make([]domain.Transaction, 0, total)
+	for _, seg := range segments {
+		txs = append(txs, seg.txs...)
+		seg.txs = nil
+	}
+	return txs, nil, nil
+}
+
+// fetch descarga el rango [start, end) completo.
+func (o rangedObject) fetch(ctx context.Context, start, end int64) ([]byte, error) {
+	in := getObjectInput(o.obj)
+	in.Range = aws.String(byteRange(start, end))
+	in.IfMatch = o.etag
+	resp, err := o.reader.s3Client.GetObject(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	data := make([]byte, end-start)
+	if _, err := io.ReadFull(resp.Body, data); err != nil {
+		return nil, fmt.Errorf("rango %s: %w", byteRange(start, end), err)
+	}
+	return data, nil
+}
+
+// bufferedBytes cuenta el texto crudo retenido (partes sin coser, tramos sin
+// parsear y el sobrante) y su pico, para verificar la cota de parse.
+type bufferedBytes struct {
+	mu   sync.Mutex
+	cur  int64
+	peak int64
+}
+
+func (b *bufferedBytes) add(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.cur += int64(n)
+	b.peak = max(b.peak, b.cur)
+}
+
+// failedSegments recuerda el primer tramo con error: los posteriores ya no
+// cambian el resultado y no hace falta parsearlos.
+type failedSegments struct {
+	mu    sync.Mutex
+	first int
+	set   bool
+}
+
+func (f *failedSegments) add(index int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.set || index < f.first {
+		f.first, f.set = index, true
+	}
+}
+
+func (f *failedSegments) before(index int) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.set && f.first < index
+}
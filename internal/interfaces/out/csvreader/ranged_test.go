@@ -0,0 +1,358 @@
+package csvreader
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/csvparse"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/smithy-go"
+)
+
+// rangedS3Client sirve un objeto en memoria respetando Range e If-Match como
+// S3. latency y bytesPerSecond simulan la red por pedido.
+type rangedS3Client struct {
+	data            []byte
+	etag            string
+	contentEncoding string
+	// failAt hace fallar los pedidos que empiezan en ese offset (si es > 0).
+	failAt         int64
+	latency        time.Duration
+	bytesPerSecond int64
+
+	requests atomic.Int32
+	ranged   atomic.Int32
+}
+
+func (f *rangedS3Client) GetObject(
+	ctx context.Context,
+	in *s3.GetObjectInput,
+	_ ...func(*s3.Options),
+) (*s3.GetObjectOutput, error) {
+	f.requests.Add(1)
+	if in.IfMatch != nil && *in.IfMatch != f.etag {
+		return nil, &smithy.GenericAPIError{Code: "PreconditionFailed"}
+	}
+
+	out := &s3.GetObjectOutput{ETag: aws.String(f.etag)}
+	if f.contentEncoding != "" {
+		out.ContentEncoding = aws.String(f.contentEncoding)
+	}
+	body := f.data
+	if in.Range != nil {
+		f.ranged.Add(1)
+		var start, end int64
+		if _, err := fmt.Sscanf(*in.Range, "bytes=%d-%d", &start, &end); err != nil {
+			return nil, err
+		}
+		size := int64(len(f.data))
+		if start >= size {
+			return nil, &smithy.GenericAPIError{Code: "InvalidRange"}
+		}
+		if f.failAt > 0 && start == f.failAt {
+			return nil, errors.New("connection reset")
+		}
+		end = min(end, size-1)
+		body = f.data[start : end+1]
+		out.ContentRange = aws.String("bytes " + strconv.FormatInt(start, 10) + "-" +
+			strconv.FormatInt(end, 10) + "/" + strconv.FormatInt(size, 10))
+	}
+
+	if f.latency > 0 || f.bytesPerSecond > 0 {
+		delay := f.latency
+		if f.bytesPerSecond > 0 {
+			delay += time.Duration(int64(len(body)) * int64(time.Second) / f.bytesPerSecond)
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	return out, nil
+}
+
+// largeCSV arma un CSV de n filas con categorías entre comillas que incluyen
+// saltos de línea, comillas escapadas y separadores.
+func largeCSV(n int) []byte {
+	var b bytes.Buffer
+	b.WriteString("Id,Date,Transaction,Category\r\n")
+	for i := range n {
+		amount := fmt.Sprintf("%d.%02d", i%997, i%100)
+		if i%3 == 0 {
+			amount = "-" + amount
+		}
+		category := "groceries"
+		switch i % 5 {
+		case 1:
+			category = `"food ""and""` + "\ndrinks\""
+		case 2:
+			category = `"rent, utilities"`
+		case 3:
+			category = ""
+		}
+		fmt.Fprintf(&b, "%d,%d/%d,%s,%s\r\n", i, i%12+1, i%28+1, amount, category)
+	}
+	return b.Bytes()
+}
+
+func rangedReader(client s3GetObjectAPI, partSize int64) *S3CSVReader {
+	return NewS3CSVReader(client, WithRangedReads(partSize, 4))
+}
+
+func TestS3CSVReader_RangedMatchesSingleStream(t *testing.T) {
+	data := largeCSV(2000)
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "big.csv"}
+
+	want, err := NewS3CSVReader(&rangedS3Client{data: data}).ReadTransactionsFromObject(context.Background(), obj)
+	if err != nil {
+		t.Fatalf("single stream: %v", err)
+	}
+
+	// Partes chicas, para que haya filas (y campos entre comillas) cortadas
+	// en cualquier lugar, incluso más largas que una parte.
+	for _, partSize := range []int64{32, 64, 1000, 4096} {
+		t.Run(strconv.FormatInt(partSize, 10), func(t *testing.T) {
+			client := &rangedS3Client{data: data, etag: `"v1"`}
+			got, err := rangedReader(client, partSize).ReadTransactionsFromObjectParallel(context.Background(), obj)
+			if err != nil {
+				t.Fatalf("ranged: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("ranged read differs: got %d transactions, want %d", len(got), len(want))
+			}
+			parts := (int64(len(data)) + partSize - 1) / partSize
+			if n := int64(client.ranged.Load()); n != parts {
+				t.Errorf("ranged requests = %d, want %d", n, parts)
+			}
+		})
+	}
+}
+
+func TestRangedObject_BoundsBufferedBytes(t *testing.T) {
+	const partSize, concurrency = 1024, 4
+	data := largeCSV(20000)
+	client := &rangedS3Client{data: data, etag: `"v1"`}
+	reader := NewS3CSVReader(client, WithRangedReads(partSize, concurrency))
+
+	first := data[:partSize]
+	layout, _, err := csvparse.ParseHeader(first, csvparse.Options{})
+	if err != nil {
+		t.Fatalf("ParseHeader: %v", err)
+	}
+	ranges := rangedObject{
+		reader:   reader,
+		obj:      domain.ObjectRef{Bucket: "bucket", Key: "big.csv"},
+		size:     int64(len(data)),
+		buffered: &bufferedBytes{},
+	}
+	txs, parseErr, err := ranges.parse(context.Background(), first, layout, csvparse.Options{Workers: 1})
+	if err != nil || parseErr != nil {
+		t.Fatalf("parse: %v, %v", err, parseErr)
+	}
+	if len(txs) != 20000 {
+		t.Fatalf("got %d transactions, want 20000", len(txs))
+	}
+
+	// La cota de parse no depende del tamaño del archivo.
+	bound := int64((3*concurrency + 4) * partSize)
+	if peak := ranges.buffered.peak; peak > bound || peak*10 > int64(len(data)) {
+		t.Errorf("peak buffered = %d bytes, want <= %d for a %d byte file", peak, bound, len(data))
+	}
+	if ranges.buffered.cur != 0 {
+		t.Errorf("%d bytes still buffered after parse", ranges.buffered.cur)
+	}
+}
+
+func TestS3CSVReader_RangedReportsSameErrorLine(t *testing.T) {
+	// La fila 400 empieza varias líneas después de la 401 del archivo por los
+	// saltos de línea entre comillas.
+	data := bytes.Replace(largeCSV(500), []byte("\r\n400,5/9,"), []byte("\r\n400,2021-05-09,"), 1)
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "big.csv"}
+
+	_, want := NewS3CSVReader(&rangedS3Client{data: data}).ReadTransactionsFromObject(context.Background(), obj)
+	if want == nil {
+		t.Fatal("single stream: expected an error")
+	}
+	_, got := rangedReader(&rangedS3Client{data: data}, 256).ReadTransactionsFromObjectParallel(context.Background(), obj)
+	if got == nil || got.Error() != want.Error() {
+		t.Fatalf("ranged error = %v, want %v", got, want)
+	}
+}
+
+func TestS3CSVReader_RangedInvalidHeader(t *testing.T) {
+	data := append([]byte("Id,Fecha,Monto\n"), bytes.Repeat([]byte("0,7/15,+60.5\n"), 100)...)
+	client := &rangedS3Client{data: data}
+
+	files, err := rangedReader(client, 64).ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "b", Key: "k.csv"})
+	if err != nil {
+		t.Fatalf("ReadObjectFiles error: %v", err)
+	}
+	if len(files) != 1 || len(files[0].Transactions) != 0 || files[0].Err != nil {
+		t.Fatalf("files = %+v, want one empty file", files)
+	}
+	if files[0].Dialect.Delimiter != ',' {
+		t.Errorf("Dialect = %+v", files[0].Dialect)
+	}
+	if n := client.requests.Load(); n != 1 {
+		t.Errorf("requests = %d, want 1", n)
+	}
+}
+
+func TestS3CSVReader_RangedWindows1252Semicolons(t *testing.T) {
+	var b bytes.Buffer
+	b.WriteString("Id;Date;Transaction;Category\n")
+	for i := range 200 {
+		fmt.Fprintf(&b, "%d;7/15;-10.3;caf\xe9\n", i)
+	}
+	obj := domain.ObjectRef{Bucket: "b", Key: "k.csv"}
+
+	files, err := rangedReader(&rangedS3Client{data: b.Bytes()}, 100).ReadObjectFiles(context.Background(), obj)
+	if err != nil {
+		t.Fatalf("ReadObjectFiles error: %v", err)
+	}
+	file := files[0]
+	if file.Err != nil || len(file.Transactions) != 200 {
+		t.Fatalf("file: %d transactions, err %v", len(file.Transactions), file.Err)
+	}
+	if file.Transactions[199].Category != "café" {
+		t.Errorf("Category = %q, want %q", file.Transactions[199].Category, "café")
+	}
+	want := domain.Dialect{Encoding: "windows-1252", Delimiter: ';'}
+	if file.Dialect != want {
+		t.Errorf("Dialect = %+v, want %+v", file.Dialect, want)
+	}
+}
+
+func TestS3CSVReader_RangedFallsBackToFullRead(t *testing.T) {
+	csvBody := "Id,Date,Transaction\n" + strings.Repeat("0,7/15,+60.5\n", 50)
+	utf16 := []byte{0xff, 0xfe}
+	for _, c := range csvBody {
+		utf16 = append(utf16, byte(c), 0)
+	}
+
+	cases := []struct {
+		name   string
+		client *rangedS3Client
+		key    string
+		want   int
+	}{
+		{"gzip", &rangedS3Client{data: gzipBytes(t, csvBody)}, "k.csv.gz", 50},
+		{"content encoding", &rangedS3Client{data: []byte(csvBody), contentEncoding: "identity"}, "k.csv", 50},
+		{"utf-16", &rangedS3Client{data: utf16}, "k.csv", 50},
+		{"jsonl", &rangedS3Client{data: bytes.Repeat([]byte(`{"date":"2021-07-15","transaction":"+60.5"}`+"\n"), 20)}, "k.jsonl", 20},
+		{"empty", &rangedS3Client{}, "k.csv", 0},
+		{"small", &rangedS3Client{data: []byte(csvBody)}, "k.csv", 50},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			partSize := int64(64)
+			if tc.name == "small" {
+				partSize = 1 << 20
+			}
+			files, err := rangedReader(tc.client, partSize).ReadObjectFiles(context.Background(), domain.ObjectRef{Bucket: "b", Key: tc.key})
+			if err != nil {
+				t.Fatalf("ReadObjectFiles error: %v", err)
+			}
+			if len(files) != 1 || files[0].Err != nil || len(files[0].Transactions) != tc.want {
+				t.Fatalf("files = %+v, want %d transactions", files, tc.want)
+			}
+			// Como mucho el primer rango y la lectura completa.
+			if n := tc.client.requests.Load(); n > 2 {
+				t.Errorf("requests = %d, want at most 2", n)
+			}
+		})
+	}
+}
+
+func TestS3CSVReader_RangedFetchErrorFails(t *testing.T) {
+	data := largeCSV(500)
+	client := &rangedS3Client{data: data, failAt: 512 * 3}
+
+	_, err := rangedReader(client, 512).ReadTransactionsFromObjectParallel(context.Background(), domain.ObjectRef{Bucket: "b", Key: "k.csv"})
+	if err == nil || !strings.Contains(err.Error(), "connection reset") {
+		t.Fatalf("err = %v, want the fetch error", err)
+	}
+}
+
+func TestS3CSVReader_RangedPinsObjectVersion(t *testing.T) {
+	data := largeCSV(100)
+	client := &rangedS3Client{data: data, etag: `"v1"`}
+	reader := rangedReader(&etagSwapClient{rangedS3Client: client}, 512)
+
+	_, err := reader.ReadTransactionsFromObjectParallel(context.Background(), domain.ObjectRef{Bucket: "b", Key: "k.csv"})
+	var apiErr smithy.APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrorCode() != "PreconditionFailed" {
+		t.Fatalf("err = %v, want PreconditionFailed", err)
+	}
+}
+
+// etagSwapClient simula que el objeto se sobrescribe después del primer
+// pedido.
+type etagSwapClient struct {
+	*rangedS3Client
+	swapped atomic.Bool
+}
+
+func (c *etagSwapClient) GetObject(ctx context.Context, in *s3.GetObjectInput, opts ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	out, err := c.rangedS3Client.GetObject(ctx, in, opts...)
+	if c.swapped.CompareAndSwap(false, true) {
+		c.etag = `"v2"`
+	}
+	return out, err
+}
+
+func benchmarkRead(b *testing.B, client *rangedS3Client, reader *S3CSVReader) {
+	obj := domain.ObjectRef{Bucket: "bucket", Key: "big.csv"}
+	b.SetBytes(int64(len(client.data)))
+	b.ReportAllocs()
+	for b.Loop() {
+		txs, err := reader.ReadTransactionsFromObjectParallel(context.Background(), obj)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(txs) == 0 {
+			b.Fatal("no transactions")
+		}
+	}
+}
+
+// Los benchmarks comparan la lectura de un solo stream con la de rangos, en
+// memoria (costo de CPU) y con una red simulada de 20 ms por pedido y
+// 50 MB/s por conexión, donde los rangos paralelos suman ancho de banda.
+func BenchmarkS3CSVReader(b *testing.B) {
+	data := largeCSV(200_000)
+	const partSize = 1 << 20
+
+	networks := []struct {
+		name           string
+		latency        time.Duration
+		bytesPerSecond int64
+	}{
+		{"memory", 0, 0},
+		{"network", 20 * time.Millisecond, 50 << 20},
+	}
+	for _, nw := range networks {
+		b.Run(nw.name+"/single-stream", func(b *testing.B) {
+			client := &rangedS3Client{data: data, latency: nw.latency, bytesPerSecond: nw.bytesPerSecond}
+			benchmarkRead(b, client, NewS3CSVReader(client))
+		})
+		b.Run(nw.name+"/ranged", func(b *testing.B) {
+			client := &rangedS3Client{data: data, etag: `"v1"`, latency: nw.latency, bytesPerSecond: nw.bytesPerSecond}
+			benchmarkRead(b, client, NewS3CSVReader(client, WithRangedReads(partSize, 8)))
+		})
+	}
+}
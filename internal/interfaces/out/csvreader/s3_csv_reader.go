@@ -2,17 +2,12 @@ package csvreader
 
 import (
 	"context"
-	"encoding/csv"
-	"io"
-	"strings"
-	"sync"
-	"time"
 
 	"stori-challenge/internal/core/domain"
 	"stori-challenge/internal/core/ports/out"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
-	"github.com/shopspring/decimal"
 )
 
 type s3GetObjectAPI interface {
@@ -25,203 +20,74 @@ type s3GetObjectAPI interface {
 
 type S3CSVReader struct {
 	s3Client s3GetObjectAPI
+	opts     readerOptions
 }
 
 var _ out.TransactionFileReader = (*S3CSVReader)(nil)
 
-func NewS3CSVReader(s3Client s3GetObjectAPI) *S3CSVReader {
-	return &S3CSVReader{s3Client: s3Client}
+func NewS3CSVReader(s3Client s3GetObjectAPI, opts ...ReaderOption) *S3CSVReader {
+	return &S3CSVReader{s3Client: s3Client, opts: newReaderOptions(opts)}
 }
 
 func (r *S3CSVReader) ReadTransactionsFromObject(
 	ctx context.Context,
-	bucket, key string,
+	obj domain.ObjectRef,
 ) ([]domain.Transaction, error) {
-	resp, err := r.s3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-	})
+	files, err := r.readObjectFiles(ctx, obj, 1)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
-
-	reader := csv.NewReader(resp.Body)
-
-	var txs []domain.Transaction
-
-	header, err := reader.Read()
-	if err == io.EOF {
-		return txs, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	if len(header) < 3 ||
-		!strings.EqualFold(header[0], "Id") ||
-		!strings.EqualFold(header[1], "Date") {
-		return nil, nil
-	}
-
-	for {
-		record, err := reader.Read()
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-
-		if len(record) < 3 {
-			continue
-		}
-
-		dateStr := strings.TrimSpace(record[1])
-		amountStr := strings.TrimSpace(record[2])
-
-		dMD, err := time.Parse("1/2", dateStr)
-		if err != nil {
-			return nil, err
-		}
-
-		d := time.Date(2021, dMD.Month(), dMD.Day(), 0, 0, 0, 0, time.UTC)
-
-		amount, err := decimal.NewFromString(amountStr)
-		if err != nil {
-			return nil, err
-		}
-
-		txs = append(txs, domain.Transaction{
-			Date:   d,
-			Amount: amount,
-		})
-	}
-
-	return txs, nil
+	return singleFile(obj, files)
 }
 
 func (r *S3CSVReader) ReadTransactionsFromObjectParallel(
 	ctx context.Context,
-	bucket, key string,
+	obj domain.ObjectRef,
 ) ([]domain.Transaction, error) {
-	resp, err := r.s3Client.GetObject(ctx, &s3.GetObjectInput{
-		Bucket: &bucket,
-		Key:    &key,
-	})
+	files, err := r.readObjectFiles(ctx, obj, workerCount)
 	if err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return singleFile(obj, files)
+}
 
-	reader := csv.NewReader(resp.Body)
+func (r *S3CSVReader) ReadObjectFiles(
+	ctx context.Context,
+	obj domain.ObjectRef,
+) ([]domain.TransactionFile, error) {
+	return r.readObjectFiles(ctx, obj, workerCount)
+}
 
-	header, err := reader.Read()
-	if err == io.EOF {
-		return nil, nil
+func (r *S3CSVReader) readObjectFiles(
+	ctx context.Context,
+	obj domain.ObjectRef,
+	workers int,
+) ([]domain.TransactionFile, error) {
+	cfg := r.opts.parseConfig(workers)
+	if workers > 1 && r.opts.ranged.enabled() && obj.Entry == "" {
+		files, ok, err := r.readRanged(ctx, obj, cfg)
+		if ok || err != nil {
+			return files, err
+		}
 	}
+
+	resp, err := r.s3Client.GetObject(ctx, getObjectInput(obj))
 	if err != nil {
 		return nil, err
 	}
-	if len(header) < 3 ||
-		!strings.EqualFold(header[0], "Id") ||
-		!strings.EqualFold(header[1], "Date") {
-		return nil, nil
-	}
-
-	records := make(chan []string)
-	results := make(chan domain.Transaction)
-	errs := make(chan error, 1)
-
-	const workerCount = 5
-	var wg sync.WaitGroup
-	wg.Add(workerCount)
-
-	for i := 0; i < workerCount; i++ {
-		go func() {
-			defer wg.Done()
-			for record := range records {
-				tx, err := parseRecord(record)
-				if err != nil {
-					select {
-					case errs <- err:
-					default:
-					}
-					return
-				}
-				select {
-				case results <- tx:
-				case <-ctx.Done():
-					return
-				}
-			}
-		}()
-	}
-
-	go func() {
-		defer close(records)
-		for {
-			record, err := reader.Read()
-			if err == io.EOF {
-				return
-			}
-			if err != nil {
-				select {
-				case errs <- err:
-				default:
-				}
-				return
-			}
-			if len(record) < 3 {
-				continue
-			}
-			select {
-			case records <- record:
-			case <-ctx.Done():
-				return
-			}
-		}
-	}()
-
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
+	defer resp.Body.Close()
 
-	var txs []domain.Transaction
-	for {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		case err := <-errs:
-			return nil, err
-		case tx, ok := <-results:
-			if !ok {
-				return txs, nil
-			}
-			txs = append(txs, tx)
-		}
-	}
+	return readFiles(ctx, obj, resp.Body, aws.ToString(resp.ContentEncoding), cfg)
 }
 
-func parseRecord(record []string) (domain.Transaction, error) {
-	dateStr := strings.TrimSpace(record[1])
-	amountStr := strings.TrimSpace(record[2])
-
-	dMD, err := time.Parse("1/2", dateStr)
-	if err != nil {
-		return domain.Transaction{}, err
+// getObjectInput pide la versión exacta del evento cuando viene informada.
+func getObjectInput(obj domain.ObjectRef) *s3.GetObjectInput {
+	in := &s3.GetObjectInput{
+		Bucket: &obj.Bucket,
+		Key:    &obj.Key,
 	}
-
-	d := time.Date(2021, dMD.Month(), dMD.Day(), 0, 0, 0, 0, time.UTC)
-
-	amount, err := decimal.NewFromString(amountStr)
-	if err != nil {
-		return domain.Transaction{}, err
+	if obj.VersionID != "" {
+		in.VersionId = &obj.VersionID
 	}
-
-	return domain.Transaction{
-		Date:   d,
-		Amount: amount,
-	}, nil
+	return in
 }
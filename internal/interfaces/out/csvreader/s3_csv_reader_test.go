@@ -9,6 +9,10 @@ import (
 	"testing"
 	"time"
 
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/shopspring/decimal"
 )
@@ -36,6 +40,7 @@ type fakeS3Client struct {
 	getErr     error
 	lastBucket *string
 	lastKey    *string
+	lastVer    *string
 }
 
 func (f *fakeS3Client) GetObject(
@@ -45,6 +50,7 @@ func (f *fakeS3Client) GetObject(
 ) (*s3.GetObjectOutput, error) {
 	f.lastBucket = in.Bucket
 	f.lastKey = in.Key
+	f.lastVer = in.VersionId
 
 	if f.getErr != nil {
 		return nil, f.getErr
@@ -69,7 +75,7 @@ func TestReadTransactionsFromObject_HappyPath(t *testing.T) {
 	bucket := "stori-transactions-local"
 	key := "input/txns.csv"
 
-	txs, err := reader.ReadTransactionsFromObject(ctx, bucket, key)
+	txs, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: bucket, Key: key})
 	if err != nil {
 		t.Fatalf("ReadTransactionsFromObject error: %v", err)
 	}
@@ -104,7 +110,7 @@ func TestReadTransactionsFromObject_EmptyFile(t *testing.T) {
 	fake := &fakeS3Client{body: ``}
 	reader := NewS3CSVReader(fake)
 
-	txs, err := reader.ReadTransactionsFromObject(ctx, "bucket", "key")
+	txs, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -120,7 +126,7 @@ func TestReadTransactionsFromObject_S3Error(t *testing.T) {
 	fake := &fakeS3Client{getErr: expErr}
 	reader := NewS3CSVReader(fake)
 
-	_, err := reader.ReadTransactionsFromObject(ctx, "bucket", "key")
+	_, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("expected error from S3, got nil")
 	}
@@ -138,7 +144,7 @@ func TestReadTransactionsFromObject_InvalidDate(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	_, err := reader.ReadTransactionsFromObject(ctx, "bucket", "key")
+	_, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("expected error for invalid date format, got nil")
 	}
@@ -153,7 +159,7 @@ func TestReadTransactionsFromObject_InvalidAmount(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	_, err := reader.ReadTransactionsFromObject(ctx, "bucket", "key")
+	_, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("expected error for invalid amount, got nil")
 	}
@@ -168,7 +174,7 @@ func TestReadTransactionsFromObject_InvalidHeader(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	txs, err := reader.ReadTransactionsFromObject(ctx, "bucket", "key")
+	txs, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err != nil {
 		t.Fatalf("unexpected err = %v", err)
 	}
@@ -188,7 +194,7 @@ func TestReadTransactionsFromObjectParallel_HappyPath(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	txs, err := reader.ReadTransactionsFromObjectParallel(ctx, "bucket", "key")
+	txs, err := reader.ReadTransactionsFromObjectParallel(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err != nil {
 		t.Fatalf("ReadTransactionsFromObjectParallel error: %v", err)
 	}
@@ -223,7 +229,7 @@ func TestReadTransactionsFromObjectParallel_S3Error(t *testing.T) {
 	fake := &fakeS3Client{getErr: expErr}
 	reader := NewS3CSVReader(fake)
 
-	_, err := reader.ReadTransactionsFromObjectParallel(ctx, "bucket", "key")
+	_, err := reader.ReadTransactionsFromObjectParallel(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("expected error from S3, got nil")
 	}
@@ -241,7 +247,7 @@ func TestReadTransactionsFromObjectParallel_InvalidDate(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	_, err := reader.ReadTransactionsFromObjectParallel(ctx, "bucket", "key")
+	_, err := reader.ReadTransactionsFromObjectParallel(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("expected error for invalid date format, got nil")
 	}
@@ -256,7 +262,7 @@ func TestReadTransactionsFromObjectParallel_InvalidAmount(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	_, err := reader.ReadTransactionsFromObjectParallel(ctx, "bucket", "key")
+	_, err := reader.ReadTransactionsFromObjectParallel(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("expected error for invalid amount, got nil")
 	}
@@ -271,7 +277,7 @@ func TestReadTransactionsFromObjectParallel_InvalidHeader(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	txs, err := reader.ReadTransactionsFromObjectParallel(ctx, "bucket", "key")
+	txs, err := reader.ReadTransactionsFromObjectParallel(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err != nil {
 		t.Fatalf("unexpected err = %v", err)
 	}
@@ -293,7 +299,7 @@ func TestReadTransactionsFromObjectParallel_ContextCanceledEarly(t *testing.T) {
 	fake := &fakeS3Client{body: csvBody}
 	reader := NewS3CSVReader(fake)
 
-	_, err := reader.ReadTransactionsFromObjectParallel(cancelled, "bucket", "key")
+	_, err := reader.ReadTransactionsFromObjectParallel(cancelled, domain.ObjectRef{Bucket: "bucket", Key: "key"})
 	if err == nil {
 		t.Fatalf("expected context error, got nil")
 	}
@@ -301,3 +307,168 @@ func TestReadTransactionsFromObjectParallel_ContextCanceledEarly(t *testing.T) {
 		t.Fatalf("expected ctx canceled/deadline, got %v", err)
 	}
 }
+
+func TestReadTransactionsFromObject_CategoryColumn(t *testing.T) {
+	ctx := context.Background()
+
+	csvBody := `Id,Date,Transaction,Category
+0,7/15,+60.5,groceries
+1,7/28,-10.3,
+`
+	fake := &fakeS3Client{body: csvBody}
+	reader := NewS3CSVReader(fake)
+
+	txs, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
+	if err != nil {
+		t.Fatalf("ReadTransactionsFromObject error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("len(txs) = %d, want 2", len(txs))
+	}
+	if txs[0].Category != "groceries" {
+		t.Errorf("txs[0].Category = %q, want %q", txs[0].Category, "groceries")
+	}
+	if txs[1].Category != "" {
+		t.Errorf("txs[1].Category = %q, want empty", txs[1].Category)
+	}
+
+	parTxs, err := reader.ReadTransactionsFromObjectParallel(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"})
+	if err != nil {
+		t.Fatalf("ReadTransactionsFromObjectParallel error: %v", err)
+	}
+	categories := map[string]int{}
+	for _, tx := range parTxs {
+		categories[tx.Category]++
+	}
+	if categories["groceries"] != 1 || categories[""] != 1 {
+		t.Errorf("categorías en lectura paralela = %v", categories)
+	}
+}
+
+func TestCSVParser_ParseTransactions(t *testing.T) {
+	body := "Id,Date,Transaction,Category\n0,7/15,+60.5,salary\n1,7/28,-10.3,groceries\n"
+
+	txs, err := NewCSVParser().ParseTransactions(context.Background(), strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("ParseTransactions returned error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(txs))
+	}
+	assertDecEq2(t, txs[1].Amount, dec("-10.3"), "amount[1]")
+	if txs[1].Category != "groceries" || !txs[0].Date.Equal(time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected transactions: %+v", txs)
+	}
+
+	if _, err := NewCSVParser().ParseTransactions(context.Background(), strings.NewReader("Id,Date,Transaction\n0,13/45,1\n")); err == nil {
+		t.Errorf("expected error for invalid date")
+	}
+}
+
+func TestCSVParser_UsesFieldMapping(t *testing.T) {
+	mapping, err := fieldmap.Parse("date=fecha,amount=monto")
+	if err != nil {
+		t.Fatalf("fieldmap.Parse returned error: %v", err)
+	}
+	parser := NewCSVParser(WithFieldMapping(mapping))
+	body := "fecha,monto\n7/15,+60.5\n7/28,-10.3\n"
+
+	txs, err := parser.ParseTransactions(context.Background(), strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("ParseTransactions returned error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(txs))
+	}
+	assertDecEq2(t, txs[1].Amount, dec("-10.3"), "amount[1]")
+
+	report, err := parser.Validate(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if !report.OK() || report.ValidRows != 2 {
+		t.Errorf("report = %+v, want 2 valid rows", report)
+	}
+}
+
+func TestCSVParser_UsesAmountRules(t *testing.T) {
+	rules, err := amountparse.ParseRules("es-ES")
+	if err != nil {
+		t.Fatalf("ParseRules returned error: %v", err)
+	}
+	body := "Id;Date;Transaction\n0;7/15;1.234,56\n"
+
+	txs, err := NewCSVParser(WithAmountRules(rules)).ParseTransactions(context.Background(), strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("ParseTransactions returned error: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(txs))
+	}
+	assertDecEq2(t, txs[0].Amount, dec("1234.56"), "amount")
+
+	if _, err := NewCSVParser().ParseTransactions(context.Background(), strings.NewReader(body)); err == nil {
+		t.Errorf("expected the default rules to reject 1.234,56")
+	}
+}
+
+func TestCSVParser_Validate_ReportsEveryRow(t *testing.T) {
+	body := "Id,Date,Transaction\n" +
+		"0,7/15,+60.5\n" +
+		"1,13/45,-10\n" +
+		"2,7/28\n" +
+		"3,7/30,abc\n" +
+		"4,8/2,-20.46\n"
+
+	report, err := NewCSVParser().Validate(strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+
+	if !report.HeaderValid || report.OK() {
+		t.Fatalf("HeaderValid = %v, OK = %v", report.HeaderValid, report.OK())
+	}
+	if report.TotalRows != 5 || report.ValidRows != 2 || report.SkippedRows != 1 {
+		t.Errorf("rows total/valid/skipped = %d/%d/%d, want 5/2/1", report.TotalRows, report.ValidRows, report.SkippedRows)
+	}
+
+	wantLines := []int{3, 4, 5}
+	if len(report.Issues) != len(wantLines) {
+		t.Fatalf("issues = %+v", report.Issues)
+	}
+	for i, line := range wantLines {
+		if report.Issues[i].Line != line {
+			t.Errorf("issue %d line = %d, want %d", i, report.Issues[i].Line, line)
+		}
+	}
+}
+
+func TestCSVParser_Validate_InvalidHeader(t *testing.T) {
+	report, err := NewCSVParser().Validate(strings.NewReader("Fecha,Monto\n7/15,1\n"))
+	if err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if report.HeaderValid || len(report.Issues) != 1 || report.Issues[0].Line != 1 {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestS3CSVReader_RequestsEventVersion(t *testing.T) {
+	client := &fakeS3Client{body: "Id,Date,Transaction\n0,7/15,+60.5\n"}
+	reader := NewS3CSVReader(client)
+	ctx := context.Background()
+
+	if _, err := reader.ReadTransactionsFromObjectParallel(ctx, domain.ObjectRef{Bucket: "bucket", Key: "estado de cuenta.csv", VersionID: "v-42"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *client.lastKey != "estado de cuenta.csv" || client.lastVer == nil || *client.lastVer != "v-42" {
+		t.Errorf("GetObject key/version = %q/%v", *client.lastKey, client.lastVer)
+	}
+
+	if _, err := reader.ReadTransactionsFromObject(ctx, domain.ObjectRef{Bucket: "bucket", Key: "key"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.lastVer != nil {
+		t.Errorf("VersionId should be omitted when empty, got %q", *client.lastVer)
+	}
+}
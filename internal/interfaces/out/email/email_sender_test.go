@@ -189,3 +189,165 @@ func TestNoopEmailSender_SendSummaryEmail_NoError(t *testing.T) {
 		t.Fatalf("NoopEmailSender.SendSummaryEmail devolvió error: %v", err)
 	}
 }
+
+func TestBuildPlainBody_WithRewards(t *testing.T) {
+	summary := domain.AccountSummary{
+		TotalBalance: dec("100"),
+		Rewards: &domain.RewardsSummary{
+			PointsEarned:     dec("120"),
+			CashbackEarned:   dec("1.2"),
+			LifetimePoints:   dec("1500"),
+			LifetimeCashback: dec("15"),
+		},
+	}
+
+	body := buildPlainBody(summary)
+
+	expected := "" +
+		"Total balance: 100.00\n" +
+		"\n" +
+		"\n" +
+		"Points earned: 120.00\n" +
+		"Cashback earned: 1.20\n" +
+		"Lifetime points: 1500.00\n" +
+		"Lifetime cashback: 15.00\n"
+
+	if body != expected {
+		t.Fatalf("buildPlainBody() = \n%q\nwant\n%q", body, expected)
+	}
+
+	html := buildHTMLBody(summary, "")
+	if !strings.Contains(html, "Rewards") || !strings.Contains(html, "1500.00") || !strings.Contains(html, "15.00 MXN") {
+		t.Errorf("HTML body no contiene la sección de rewards: %q", html)
+	}
+}
+
+func TestBuildBodies_WithForecast(t *testing.T) {
+	summary := domain.AccountSummary{
+		TotalBalance: dec("100"),
+		Forecast: &domain.Forecast{
+			Month:            "2021-09",
+			SpentToDate:      dec("40"),
+			ProjectedSpend:   dec("95.5"),
+			ProjectedBalance: dec("60.25"),
+			ByCategory: []domain.CategoryForecast{
+				{Category: "groceries", SpentToDate: dec("40"), ProjectedSpend: dec("95.5")},
+			},
+		},
+	}
+
+	body := buildPlainBody(summary)
+	if !strings.Contains(body, "Projected spend for 2021-09: 95.50\n") ||
+		!strings.Contains(body, "Projected balance at end of 2021-09: 60.25\n") {
+		t.Errorf("plain body sin cifras proyectadas: %q", body)
+	}
+
+	html := buildHTMLBody(summary, "")
+	if !strings.Contains(html, "Projected for 2021-09") ||
+		!strings.Contains(html, "groceries") ||
+		!strings.Contains(html, "60.25 MXN") {
+		t.Errorf("HTML body sin sección de proyección: %q", html)
+	}
+}
+
+func TestBuildHTMLBody_EscapesForecastCategory(t *testing.T) {
+	summary := domain.AccountSummary{
+		Forecast: &domain.Forecast{
+			Month: "2021-09",
+			ByCategory: []domain.CategoryForecast{
+				{Category: `<img src="x" onerror="alert(1)">`, SpentToDate: dec("40"), ProjectedSpend: dec("95.5")},
+			},
+		},
+	}
+
+	html := buildHTMLBody(summary, "")
+	if strings.Contains(html, "<img") {
+		t.Fatalf("la categoría se insertó como HTML: %q", html)
+	}
+	if !strings.Contains(html, "&lt;img src=&#34;x&#34; onerror=&#34;alert(1)&#34;&gt;") {
+		t.Errorf("HTML body sin la categoría escapada: %q", html)
+	}
+}
+
+func TestBuildHTMLBody_ComparisonIndicators(t *testing.T) {
+	summary := domain.AccountSummary{
+		TotalBalance: dec("10"),
+		ByMonth: []domain.MonthlySummary{
+			{
+				MonthName:         "2021-08",
+				TransactionsCount: 3,
+				VsPreviousMonth: &domain.MonthlyDelta{
+					BaselineMonth:      "2021-07",
+					TransactionsCount:  2,
+					AverageDebitAmount: dec("-4.5"),
+				},
+			},
+		},
+	}
+
+	html := buildHTMLBody(summary, "")
+
+	if !strings.Contains(html, "vs prev. month") || !strings.Contains(html, "vs last year") {
+		t.Errorf("HTML body sin columnas de comparación: %q", html)
+	}
+	if !strings.Contains(html, "&#9650; 2") {
+		t.Errorf("HTML body sin indicador de subida en conteo: %q", html)
+	}
+	if !strings.Contains(html, "&#9660; 4.50") {
+		t.Errorf("HTML body sin indicador de bajada en promedio de débito: %q", html)
+	}
+	if !strings.Contains(html, "&mdash;") {
+		t.Errorf("HTML body debe marcar comparaciones sin datos: %q", html)
+	}
+
+	plain := domain.AccountSummary{ByMonth: []domain.MonthlySummary{{MonthName: "2021-08"}}}
+	if strings.Contains(buildHTMLBody(plain, ""), "vs prev. month") {
+		t.Errorf("sin comparaciones no deben agregarse columnas")
+	}
+}
+
+func TestSummaryRenderer_MatchesSentEmail(t *testing.T) {
+	summary := domain.AccountSummary{
+		TotalBalance: dec("39.74"),
+		ByMonth: []domain.MonthlySummary{
+			{MonthName: "2021-07", TransactionsCount: 2, AverageDebitAmount: dec("-10.3"), AverageCreditAmount: dec("60.5")},
+		},
+	}
+
+	rendered, err := NewSummaryRenderer("https://logo.example/logo.png").RenderSummary(context.Background(), summary)
+	if err != nil {
+		t.Fatalf("RenderSummary returned error: %v", err)
+	}
+
+	if rendered.Subject != summarySubject {
+		t.Errorf("Subject = %q, want %q", rendered.Subject, summarySubject)
+	}
+	if rendered.Plain != buildPlainBody(summary) {
+		t.Errorf("Plain body differs from the one sent by SES")
+	}
+	if rendered.HTML != buildHTMLBody(summary, "https://logo.example/logo.png") {
+		t.Errorf("HTML body differs from the one sent by SES")
+	}
+	if !strings.Contains(rendered.HTML, "https://logo.example/logo.png") {
+		t.Errorf("HTML does not include the logo URL")
+	}
+}
+
+func TestBuildBodies_WithStatementBalances(t *testing.T) {
+	opening, closing := dec("1000"), dec("1050.5")
+	summary := domain.AccountSummary{
+		TotalBalance:   dec("50.5"),
+		OpeningBalance: &opening,
+		ClosingBalance: &closing,
+	}
+
+	plain := buildPlainBody(summary)
+	if !strings.HasPrefix(plain, "Total balance: 50.50\nOpening balance: 1000.00\nClosing balance: 1050.50\n") {
+		t.Errorf("plain body does not list the statement balances: %q", plain)
+	}
+
+	html := buildHTMLBody(summary, "")
+	if !strings.Contains(html, "Opening balance: 1000.00 MXN") || !strings.Contains(html, "Closing balance: 1050.50 MXN") {
+		t.Errorf("HTML body does not list the statement balances")
+	}
+}
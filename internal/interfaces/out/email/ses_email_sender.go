@@ -3,6 +3,7 @@ package email
 import (
 	"context"
 	"fmt"
+	"html"
 	"strings"
 
 	"stori-challenge/internal/core/domain"
@@ -36,9 +37,7 @@ func NewSESEmailSender(client sesClient, cfg *config.Config) *SESEmailSender {
 var _ out.EmailSender = (*SESEmailSender)(nil)
 
 func (s *SESEmailSender) SendSummaryEmail(ctx context.Context, summary domain.AccountSummary) error {
-	subject := "Stori - Account Summary"
-	bodyText := buildPlainBody(summary)
-	bodyHTML := buildHTMLBody(summary, s.cfg.StoriLogoURL)
+	rendered := renderSummary(summary, s.cfg.StoriLogoURL)
 
 	_, err := s.client.SendEmail(ctx, &sesv2.SendEmailInput{
 		FromEmailAddress: &s.cfg.SESFrom,
@@ -47,10 +46,10 @@ func (s *SESEmailSender) SendSummaryEmail(ctx context.Context, summary domain.Ac
 		},
 		Content: &types.EmailContent{
 			Simple: &types.Message{
-				Subject: &types.Content{Data: &subject},
+				Subject: &types.Content{Data: &rendered.Subject},
 				Body: &types.Body{
-					Text: &types.Content{Data: &bodyText},
-					Html: &types.Content{Data: &bodyHTML},
+					Text: &types.Content{Data: &rendered.Plain},
+					Html: &types.Content{Data: &rendered.HTML},
 				},
 			},
 		},
@@ -65,6 +64,12 @@ func money(d decimal.Decimal) string {
 func buildPlainBody(summary domain.AccountSummary) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "Total balance: %s\n", money(summary.TotalBalance))
+	if summary.OpeningBalance != nil {
+		fmt.Fprintf(&b, "Opening balance: %s\n", money(*summary.OpeningBalance))
+	}
+	if summary.ClosingBalance != nil {
+		fmt.Fprintf(&b, "Closing balance: %s\n", money(*summary.ClosingBalance))
+	}
 
 	for _, m := range summary.ByMonth {
 		fmt.Fprintf(&b, "Transactions in %s: %d\n", m.MonthName, m.TransactionsCount)
@@ -75,6 +80,20 @@ func buildPlainBody(summary domain.AccountSummary) string {
 		fmt.Fprintf(&b, "Average debit in %s: %s\n", m.MonthName, money(m.AverageDebitAmount))
 		fmt.Fprintf(&b, "Average credit in %s: %s\n", m.MonthName, money(m.AverageCreditAmount))
 	}
+
+	if f := summary.Forecast; f != nil {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Projected spend for %s: %s\n", f.Month, money(f.ProjectedSpend))
+		fmt.Fprintf(&b, "Projected balance at end of %s: %s\n", f.Month, money(f.ProjectedBalance))
+	}
+
+	if r := summary.Rewards; r != nil {
+		b.WriteString("\n")
+		fmt.Fprintf(&b, "Points earned: %s\n", money(r.PointsEarned))
+		fmt.Fprintf(&b, "Cashback earned: %s\n", money(r.CashbackEarned))
+		fmt.Fprintf(&b, "Lifetime points: %s\n", money(r.LifetimePoints))
+		fmt.Fprintf(&b, "Lifetime cashback: %s\n", money(r.LifetimeCashback))
+	}
 	return b.String()
 }
 
@@ -119,7 +138,9 @@ func buildHTMLBody(summary domain.AccountSummary, logoURL string) string {
 `)
 	fmt.Fprintf(&b, "                  %s MXN\n", money(summary.TotalBalance))
 	b.WriteString(`                </p>
-              </td>
+`)
+	writeBalancesHTML(&b, summary)
+	b.WriteString(`              </td>
             </tr>
 
             <tr>
@@ -135,7 +156,14 @@ func buildHTMLBody(summary domain.AccountSummary, logoURL string) string {
                       <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">Transactions</th>
                       <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">Avg debit</th>
                       <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">Avg credit</th>
-                    </tr>
+`)
+	withComparisons := hasComparisons(summary.ByMonth)
+	if withComparisons {
+		b.WriteString(`                      <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">vs prev. month</th>
+                      <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">vs last year</th>
+`)
+	}
+	b.WriteString(`                    </tr>
                   </thead>
                   <tbody>
 `)
@@ -145,6 +173,10 @@ func buildHTMLBody(summary domain.AccountSummary, logoURL string) string {
 		fmt.Fprintf(&b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:13px;color:#111827;border-bottom:1px solid #f3f4f6;\">%d</td>\n", m.TransactionsCount)
 		fmt.Fprintf(&b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:13px;color:#d32f2f;border-bottom:1px solid #f3f4f6;\">%s</td>\n", money(m.AverageDebitAmount))
 		fmt.Fprintf(&b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:13px;color:#2e7d32;border-bottom:1px solid #f3f4f6;\">%s</td>\n", money(m.AverageCreditAmount))
+		if withComparisons {
+			fmt.Fprintf(&b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:12px;color:#6b7280;border-bottom:1px solid #f3f4f6;\">%s</td>\n", comparisonCell(m.VsPreviousMonth))
+			fmt.Fprintf(&b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:12px;color:#6b7280;border-bottom:1px solid #f3f4f6;\">%s</td>\n", comparisonCell(m.VsPreviousYear))
+		}
 		b.WriteString("                    </tr>\n")
 	}
 
@@ -152,7 +184,14 @@ func buildHTMLBody(summary domain.AccountSummary, logoURL string) string {
                 </table>
               </td>
             </tr>
-
+`)
+	if summary.Forecast != nil {
+		writeForecastHTML(&b, *summary.Forecast)
+	}
+	if summary.Rewards != nil {
+		writeRewardsHTML(&b, *summary.Rewards)
+	}
+	b.WriteString(`
             <tr>
               <td style="padding:16px 24px 20px 24px;">
                 <p style="margin:0;font-size:12px;color:#9ca3af;line-height:1.5;">
@@ -174,3 +213,133 @@ func buildHTMLBody(summary domain.AccountSummary, logoURL string) string {
 
 	return b.String()
 }
+
+func writeRewardsHTML(b *strings.Builder, r domain.RewardsSummary) {
+	b.WriteString(`
+            <tr>
+              <td style="padding:12px 24px 8px 24px;">
+                <p style="margin:0 0 8px 0;font-size:14px;font-weight:600;color:#111827;">
+                  Rewards
+                </p>
+                <table width="100%" cellpadding="0" cellspacing="0" role="presentation"
+                       style="border-collapse:collapse;border-radius:10px;overflow:hidden;border:1px solid #e5e7eb;">
+                  <thead>
+                    <tr style="background-color:#e6f9f0;">
+                      <th align="left" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;"></th>
+                      <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">This statement</th>
+                      <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">Lifetime balance</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+`)
+	writeRewardsRow(b, "Points", money(r.PointsEarned), money(r.LifetimePoints))
+	writeRewardsRow(b, "Cashback", money(r.CashbackEarned)+" MXN", money(r.LifetimeCashback)+" MXN")
+	b.WriteString(`                  </tbody>
+                </table>
+              </td>
+            </tr>
+`)
+}
+
+func writeRewardsRow(b *strings.Builder, label, earned, lifetime string) {
+	b.WriteString("                    <tr>\n")
+	fmt.Fprintf(b, "                      <td style=\"padding:8px 10px;font-size:13px;color:#111827;border-bottom:1px solid #f3f4f6;\">%s</td>\n", label)
+	fmt.Fprintf(b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:13px;color:%s;border-bottom:1px solid #f3f4f6;\">%s</td>\n", storiDarkGreen, earned)
+	fmt.Fprintf(b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:13px;color:#111827;border-bottom:1px solid #f3f4f6;\">%s</td>\n", lifetime)
+	b.WriteString("                    </tr>\n")
+}
+
+// writeBalancesHTML agrega los saldos que declara el extracto, si los hay.
+func writeBalancesHTML(b *strings.Builder, summary domain.AccountSummary) {
+	if summary.OpeningBalance != nil {
+		fmt.Fprintf(b, "                <p style=\"margin:8px 0 0 0;font-size:13px;color:#6b7280;\">Opening balance: %s MXN</p>\n", money(*summary.OpeningBalance))
+	}
+	if summary.ClosingBalance != nil {
+		fmt.Fprintf(b, "                <p style=\"margin:4px 0 0 0;font-size:13px;color:#6b7280;\">Closing balance: %s MXN</p>\n", money(*summary.ClosingBalance))
+	}
+}
+
+func writeForecastHTML(b *strings.Builder, f domain.Forecast) {
+	b.WriteString(`
+            <tr>
+              <td style="padding:12px 24px 8px 24px;">
+                <p style="margin:0 0 8px 0;font-size:14px;font-weight:600;color:#111827;">
+`)
+	fmt.Fprintf(b, "                  Projected for %s\n", f.Month)
+	b.WriteString(`                </p>
+                <p style="margin:0 0 8px 0;font-size:13px;color:#6b7280;">
+`)
+	fmt.Fprintf(b, "                  Projected balance at month end: <strong style=\"color:%s;\">%s MXN</strong>\n", storiDarkGreen, money(f.ProjectedBalance))
+	b.WriteString(`                </p>
+                <table width="100%" cellpadding="0" cellspacing="0" role="presentation"
+                       style="border-collapse:collapse;border-radius:10px;overflow:hidden;border:1px solid #e5e7eb;">
+                  <thead>
+                    <tr style="background-color:#e6f9f0;">
+                      <th align="left" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">Category</th>
+                      <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">Spent so far</th>
+                      <th align="right" style="padding:8px 10px;font-size:12px;color:#047857;font-weight:600;">Projected spend</th>
+                    </tr>
+                  </thead>
+                  <tbody>
+`)
+	for _, c := range f.ByCategory {
+		// La categoría viene tal cual del archivo subido.
+		writeForecastRow(b, html.EscapeString(c.Category), money(c.SpentToDate), money(c.ProjectedSpend))
+	}
+	writeForecastRow(b, "<strong>Total</strong>", money(f.SpentToDate), money(f.ProjectedSpend))
+	b.WriteString(`                  </tbody>
+                </table>
+              </td>
+            </tr>
+`)
+}
+
+// writeForecastRow escribe label como HTML: el llamador lo escapa.
+func writeForecastRow(b *strings.Builder, label, spent, projected string) {
+	b.WriteString("                    <tr>\n")
+	fmt.Fprintf(b, "                      <td style=\"padding:8px 10px;font-size:13px;color:#111827;border-bottom:1px solid #f3f4f6;\">%s</td>\n", label)
+	fmt.Fprintf(b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:13px;color:#111827;border-bottom:1px solid #f3f4f6;\">%s</td>\n", spent)
+	fmt.Fprintf(b, "                      <td align=\"right\" style=\"padding:8px 10px;font-size:13px;color:#d32f2f;border-bottom:1px solid #f3f4f6;\">%s</td>\n", projected)
+	b.WriteString("                    </tr>\n")
+}
+
+func hasComparisons(months []domain.MonthlySummary) bool {
+	for _, m := range months {
+		if m.VsPreviousMonth != nil || m.VsPreviousYear != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func comparisonCell(d *domain.MonthlyDelta) string {
+	if d == nil {
+		return "&mdash;"
+	}
+	lines := []string{
+		fmt.Sprintf("Txns %s", trendIndicator(decimal.NewFromInt(int64(d.TransactionsCount)), fmt.Sprintf("%d", abs(d.TransactionsCount)))),
+		fmt.Sprintf("Avg debit %s", trendIndicator(d.AverageDebitAmount, money(d.AverageDebitAmount.Abs()))),
+		fmt.Sprintf("Avg credit %s", trendIndicator(d.AverageCreditAmount, money(d.AverageCreditAmount.Abs()))),
+		fmt.Sprintf("Debits %s", trendIndicator(d.TotalDebitAmount, money(d.TotalDebitAmount.Abs()))),
+		fmt.Sprintf("Credits %s", trendIndicator(d.TotalCreditAmount, money(d.TotalCreditAmount.Abs()))),
+	}
+	return strings.Join(lines, "<br/>")
+}
+
+func trendIndicator(delta decimal.Decimal, label string) string {
+	switch delta.Sign() {
+	case 1:
+		return fmt.Sprintf(`<span style="color:#2e7d32;">&#9650; %s</span>`, label)
+	case -1:
+		return fmt.Sprintf(`<span style="color:#d32f2f;">&#9660; %s</span>`, label)
+	default:
+		return `<span style="color:#9ca3af;">&#9679; 0</span>`
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
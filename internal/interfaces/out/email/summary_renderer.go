@@ -0,0 +1,33 @@
+package email
+
+import (
+	"context"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+)
+
+const summarySubject = "Stori - Account Summary"
+
+// SummaryRenderer arma el mismo correo que SESEmailSender sin enviarlo.
+type SummaryRenderer struct {
+	logoURL string
+}
+
+var _ out.SummaryRenderer = (*SummaryRenderer)(nil)
+
+func NewSummaryRenderer(logoURL string) *SummaryRenderer {
+	return &SummaryRenderer{logoURL: logoURL}
+}
+
+func (r *SummaryRenderer) RenderSummary(_ context.Context, summary domain.AccountSummary) (domain.RenderedEmail, error) {
+	return renderSummary(summary, r.logoURL), nil
+}
+
+func renderSummary(summary domain.AccountSummary, logoURL string) domain.RenderedEmail {
+	return domain.RenderedEmail{
+		Subject: summarySubject,
+		HTML:    buildHTMLBody(summary, logoURL),
+		Plain:   buildPlainBody(summary),
+	}
+}
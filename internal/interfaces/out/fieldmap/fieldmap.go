@@ -0,0 +1,270 @@
+// Package fieldmap describe qué columna de un archivo de entrada alimenta cada
+// campo de una transacción. Lo comparten los lectores de CSV, JSON Lines y
+// Parquet, así un mismo FILE_FIELD_MAP sirve para los tres.
+package fieldmap
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/amountparse"
+
+	"github.com/shopspring/decimal"
+)
+
+type Field int
+
+const (
+	Date Field = iota
+	Amount
+	Category
+	Reference
+	Description
+	Memo
+	Currency
+	// Debit y Credit son columnas separadas de cargo y abono; se usan cuando
+	// la fila no trae Amount.
+	Debit
+	Credit
+
+	fieldCount
+)
+
+var fieldNames = [fieldCount]string{
+	Date:        "date",
+	Amount:      "amount",
+	Category:    "category",
+	Reference:   "reference",
+	Description: "description",
+	Memo:        "memo",
+	Currency:    "currency",
+	Debit:       "debit",
+	Credit:      "credit",
+}
+
+// Fields devuelve todos los campos, en orden.
+func Fields() []Field {
+	fields := make([]Field, fieldCount)
+	for i := range fields {
+		fields[i] = Field(i)
+	}
+	return fields
+}
+
+func (f Field) String() string {
+	return fieldNames[f]
+}
+
+// Mapping guarda el nombre de columna de cada campo. El valor cero equivale a
+// Default.
+type Mapping struct {
+	columns [fieldCount]string
+}
+
+// Default es el esquema del CSV original (Id,Date,Transaction[,Category]) más
+// los campos opcionales con su propio nombre.
+func Default() Mapping {
+	return Mapping{columns: [fieldCount]string{
+		Date:        "Date",
+		Amount:      "Transaction",
+		Category:    "Category",
+		Reference:   "Reference",
+		Description: "Description",
+		Memo:        "Memo",
+		Currency:    "Currency",
+		Debit:       "Debit",
+		Credit:      "Credit",
+	}}
+}
+
+// Parse lee una lista "campo=columna" separada por comas (p. ej.
+// "date=fecha,amount=monto") sobre Default. Una cadena vacía devuelve Default.
+func Parse(spec string) (Mapping, error) {
+	m := Default()
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, column, ok := strings.Cut(pair, "=")
+		name, column = strings.TrimSpace(name), strings.TrimSpace(column)
+		if !ok || column == "" {
+			return Mapping{}, fmt.Errorf("mapeo de columnas inválido %q, se espera campo=columna", pair)
+		}
+		field, ok := lookupField(name)
+		if !ok {
+			return Mapping{}, fmt.Errorf("campo desconocido %q en el mapeo de columnas", name)
+		}
+		m.columns[field] = column
+	}
+	return m, nil
+}
+
+func lookupField(name string) (Field, bool) {
+	for f, n := range fieldNames {
+		if strings.EqualFold(n, name) {
+			return Field(f), true
+		}
+	}
+	return 0, false
+}
+
+// Column devuelve la columna configurada para el campo.
+func (m Mapping) Column(f Field) string {
+	if m.columns[Date] == "" {
+		return Default().columns[f]
+	}
+	return m.columns[f]
+}
+
+// Match informa si un nombre de columna de la fuente corresponde al campo;
+// la comparación ignora mayúsculas y espacios alrededor.
+func (m Mapping) Match(f Field, name string) bool {
+	return strings.EqualFold(strings.TrimSpace(name), m.Column(f))
+}
+
+// Columns guarda la posición de cada campo en una cabecera; -1 si no está.
+type Columns [fieldCount]int
+
+// Resolve ubica los campos en la cabecera. Date es obligatorio, y Amount o
+// alguna de Debit y Credit.
+func (m Mapping) Resolve(header []string) (Columns, error) {
+	var cols Columns
+	for f := range cols {
+		cols[f] = -1
+		for i, name := range header {
+			if m.Match(Field(f), name) {
+				cols[f] = i
+				break
+			}
+		}
+	}
+	return cols, m.Required(func(f Field) bool { return cols[f] >= 0 })
+}
+
+// Required informa qué campo obligatorio falta, según found.
+func (m Mapping) Required(found func(Field) bool) error {
+	if !found(Date) {
+		return fmt.Errorf("falta la columna %q (%s)", m.Column(Date), Date)
+	}
+	if !found(Amount) && !found(Debit) && !found(Credit) {
+		return fmt.Errorf("falta la columna %q (%s) o las de cargo y abono %q/%q",
+			m.Column(Amount), Amount, m.Column(Debit), m.Column(Credit))
+	}
+	return nil
+}
+
+// Width es la cantidad mínima de columnas que debe tener una fila para traer
+// fecha y monto.
+func (c Columns) Width() int {
+	return max(c[Date], c[Amount], c[Debit], c[Credit]) + 1
+}
+
+// Value devuelve el valor del campo en la fila, o el Value cero si la fila no
+// lo trae.
+func (c Columns) Value(record []string, f Field) Value {
+	if i := c[f]; i >= 0 && i < len(record) {
+		return Text(record[i])
+	}
+	return Value{}
+}
+
+// DateParser convierte el texto de la columna de fecha; cada formato de
+// archivo tiene el suyo.
+type DateParser func(string) (time.Time, error)
+
+// MonthDay es la fecha del CSV: "M/D", siempre en 2021.
+func MonthDay(value string) (time.Time, error) {
+	d, err := time.Parse("1/2", value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(2021, d.Month(), d.Day(), 0, 0, 0, 0, time.UTC), nil
+}
+
+// ISODate acepta YYYY-MM-DD o un timestamp RFC 3339, del que se toma solo el
+// día. La usan JSON Lines y Parquet.
+func ISODate(value string) (time.Time, error) {
+	if len(value) > len("2006-01-02") {
+		t, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// Value es el texto de un campo en la fila. Number indica que la fuente ya lo
+// trae tipado como número (JSON, Parquet): no se le aplica el formato regional
+// de los montos.
+type Value struct {
+	Text   string
+	Number bool
+}
+
+// Text es el Value de una fuente que solo trae texto, como el CSV.
+func Text(s string) Value {
+	return Value{Text: s}
+}
+
+// Transaction arma una transacción con el valor de cada campo; value devuelve
+// el Value cero para los campos que la fila no trae. Si Amount viene vacío,
+// el monto es el abono menos el cargo (este siempre resta, venga o no con
+// signo).
+func Transaction(value func(Field) Value, parseDate DateParser, amounts amountparse.Rules) (domain.Transaction, error) {
+	text := func(f Field) string { return strings.TrimSpace(value(f).Text) }
+
+	date, err := parseDate(text(Date))
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	amount, err := parseAmount(value, amounts)
+	if err != nil {
+		return domain.Transaction{}, err
+	}
+	return domain.Transaction{
+		Date:        date,
+		Amount:      amount,
+		Category:    text(Category),
+		Reference:   text(Reference),
+		Description: text(Description),
+		Memo:        text(Memo),
+		Currency:    text(Currency),
+	}, nil
+}
+
+func parseAmount(value func(Field) Value, amounts amountparse.Rules) (decimal.Decimal, error) {
+	parse := func(f Field) (decimal.Decimal, bool, error) {
+		v := value(f)
+		text := strings.TrimSpace(v.Text)
+		if text == "" {
+			return decimal.Decimal{}, false, nil
+		}
+		if v.Number {
+			d, err := decimal.NewFromString(text)
+			return d, true, err
+		}
+		d, err := amounts.Parse(text)
+		return d, true, err
+	}
+
+	if amount, ok, err := parse(Amount); ok || err != nil {
+		return amount, err
+	}
+	debit, hasDebit, err := parse(Debit)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("cargo: %w", err)
+	}
+	credit, hasCredit, err := parse(Credit)
+	if err != nil {
+		return decimal.Decimal{}, fmt.Errorf("abono: %w", err)
+	}
+	if !hasDebit && !hasCredit {
+		return decimal.Decimal{}, errors.New("la fila no trae monto, cargo ni abono")
+	}
+	return credit.Sub(debit.Abs()), nil
+}
@@ -0,0 +1,124 @@
+package fieldmap
+
+import (
+	"testing"
+	"time"
+
+	"stori-challenge/internal/interfaces/out/amountparse"
+)
+
+func TestParse(t *testing.T) {
+	m, err := Parse(" date = fecha , AMOUNT=monto,category=")
+	if err == nil {
+		t.Fatalf("expected an error for an empty column, got mapping %+v", m)
+	}
+
+	m, err = Parse("date=fecha, AMOUNT=monto")
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if m.Column(Date) != "fecha" || m.Column(Amount) != "monto" || m.Column(Category) != "Category" {
+		t.Errorf("unexpected mapping: %+v", m)
+	}
+
+	if _, err := Parse("balance=saldo"); err == nil {
+		t.Errorf("expected an error for an unknown field")
+	}
+	if m, err := Parse(""); err != nil || m != Default() {
+		t.Errorf("empty spec should be the default mapping, got %+v, %v", m, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	cols, err := Mapping{}.Resolve([]string{"Id", " date ", "Transaction", "Memo"})
+	if err != nil {
+		t.Fatalf("Resolve returned error: %v", err)
+	}
+	if cols[Date] != 1 || cols[Amount] != 2 || cols[Memo] != 3 || cols[Category] != -1 {
+		t.Errorf("unexpected columns: %v", cols)
+	}
+	if cols.Width() != 3 {
+		t.Errorf("Width = %d, want 3", cols.Width())
+	}
+	if got := cols.Value([]string{"0", "7/15"}, Amount); got != (Value{}) {
+		t.Errorf("a short row should not have an amount, got %+v", got)
+	}
+
+	if _, err := Default().Resolve([]string{"Id", "Date"}); err == nil {
+		t.Errorf("expected an error when the amount column is missing")
+	}
+
+	cols, err = Default().Resolve([]string{"Date", "Debit", "Credit"})
+	if err != nil {
+		t.Fatalf("debit and credit columns should stand in for the amount: %v", err)
+	}
+	if cols.Width() != 3 {
+		t.Errorf("Width = %d, want 3", cols.Width())
+	}
+}
+
+func TestTransaction_Amounts(t *testing.T) {
+	es, err := amountparse.ParseRules("es-ES")
+	if err != nil {
+		t.Fatalf("ParseRules returned error: %v", err)
+	}
+
+	for _, tc := range []struct {
+		name   string
+		values map[Field]Value
+		rules  amountparse.Rules
+		want   string
+	}{
+		{"amount", map[Field]Value{Amount: Text("$1,234.50")}, amountparse.Rules{}, "1234.5"},
+		{"locale amount", map[Field]Value{Amount: Text("(1.234,50)")}, es, "-1234.5"},
+		{"typed number skips the locale", map[Field]Value{Amount: {Text: "60.5", Number: true}}, es, "60.5"},
+		{"debit", map[Field]Value{Debit: Text("10.30"), Credit: Text("")}, amountparse.Rules{}, "-10.3"},
+		{"signed debit", map[Field]Value{Debit: Text("-10.30")}, amountparse.Rules{}, "-10.3"},
+		{"credit", map[Field]Value{Debit: Text(" "), Credit: Text("60.5")}, amountparse.Rules{}, "60.5"},
+		{"amount wins", map[Field]Value{Amount: Text("1"), Debit: Text("5")}, amountparse.Rules{}, "1"},
+	} {
+		tc.values[Date] = Text("7/15")
+		tx, err := Transaction(func(f Field) Value { return tc.values[f] }, MonthDay, tc.rules)
+		if err != nil {
+			t.Errorf("%s: Transaction returned error: %v", tc.name, err)
+			continue
+		}
+		if tx.Amount.String() != tc.want {
+			t.Errorf("%s: amount = %s, want %s", tc.name, tx.Amount, tc.want)
+		}
+	}
+
+	for name, values := range map[string]map[Field]Value{
+		"no amount":   {Date: Text("7/15")},
+		"bad debit":   {Date: Text("7/15"), Debit: Text("abc")},
+		"bad decimal": {Date: Text("7/15"), Amount: Text("1,5")},
+	} {
+		if _, err := Transaction(func(f Field) Value { return values[f] }, MonthDay, amountparse.Rules{}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestDateParsers(t *testing.T) {
+	want := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		parse DateParser
+		value string
+	}{
+		{MonthDay, "7/15"},
+		{ISODate, "2021-07-15"},
+		{ISODate, "2021-07-15T23:30:00-05:00"},
+	} {
+		got, err := tc.parse(tc.value)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("parse(%q) = %v, %v; want %v", tc.value, got, err, want)
+		}
+	}
+
+	if _, err := MonthDay("2021-07-15"); err == nil {
+		t.Errorf("MonthDay should reject ISO dates")
+	}
+	if _, err := ISODate("7/15"); err == nil {
+		t.Errorf("ISODate should reject M/D dates")
+	}
+}
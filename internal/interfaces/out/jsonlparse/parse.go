@@ -0,0 +1,110 @@
+// Package jsonlparse lee transacciones en JSON Lines: un objeto por línea,
+// con los campos ubicados por el mismo mapeo de columnas que el CSV.
+package jsonlparse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+)
+
+// Looks informa si el contenido empieza con un objeto JSON.
+func Looks(head []byte) bool {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	return bytes.HasPrefix(bytes.TrimSpace(head), []byte("{"))
+}
+
+// Parse lee línea a línea, sin cargar el archivo completo. Las líneas vacías
+// se ignoran; una línea inválida corta el parseo con error. Las fechas van
+// como YYYY-MM-DD o RFC 3339.
+func Parse(ctx context.Context, r io.Reader, mapping fieldmap.Mapping, amounts amountparse.Rules) ([]domain.Transaction, error) {
+	br := bufio.NewReader(r)
+
+	var txs []domain.Transaction
+	for line := 1; ; line++ {
+		raw, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, readErr
+		}
+
+		raw = bytes.TrimSpace(raw)
+		if line == 1 {
+			raw = bytes.TrimPrefix(raw, []byte("\xef\xbb\xbf"))
+		}
+		if len(raw) > 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			tx, err := parseLine(raw, mapping, amounts)
+			if err != nil {
+				return nil, fmt.Errorf("línea %d: %w", line, err)
+			}
+			txs = append(txs, tx)
+		}
+
+		if readErr == io.EOF {
+			return txs, nil
+		}
+	}
+}
+
+func parseLine(raw []byte, mapping fieldmap.Mapping, amounts amountparse.Rules) (domain.Transaction, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var obj map[string]any
+	if err := dec.Decode(&obj); err != nil {
+		return domain.Transaction{}, err
+	}
+
+	var fieldErr error
+	tx, err := fieldmap.Transaction(func(f fieldmap.Field) fieldmap.Value {
+		value, err := fieldValue(obj, mapping, f)
+		if err != nil && fieldErr == nil {
+			fieldErr = err
+		}
+		return value
+	}, fieldmap.ISODate, amounts)
+	if fieldErr != nil {
+		return domain.Transaction{}, fieldErr
+	}
+	return tx, err
+}
+
+// fieldValue busca la clave del campo (primero exacta, luego sin distinguir
+// mayúsculas) y devuelve su valor escalar como texto; los números JSON quedan
+// marcados como tales.
+func fieldValue(obj map[string]any, mapping fieldmap.Mapping, f fieldmap.Field) (fieldmap.Value, error) {
+	value, ok := obj[mapping.Column(f)]
+	if !ok {
+		for key, v := range obj {
+			if mapping.Match(f, key) {
+				value, ok = v, true
+				break
+			}
+		}
+	}
+	if !ok {
+		return fieldmap.Value{}, nil
+	}
+
+	switch v := value.(type) {
+	case nil:
+		return fieldmap.Value{}, nil
+	case string:
+		return fieldmap.Text(v), nil
+	case json.Number:
+		return fieldmap.Value{Text: v.String(), Number: true}, nil
+	case bool:
+		return fieldmap.Text(strconv.FormatBool(v)), nil
+	default:
+		return fieldmap.Value{}, fmt.Errorf("el campo %q no es un valor escalar", mapping.Column(f))
+	}
+}
@@ -0,0 +1,70 @@
+package jsonlparse
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestParse_DefaultMapping(t *testing.T) {
+	body := "\xef\xbb\xbf{\"Date\":\"2021-07-15\",\"Transaction\":60.5,\"Category\":\"salary\"}\n" +
+		"\n" +
+		"{\"date\":\"2021-07-28T10:00:00Z\",\"transaction\":\"-10.30\",\"reference\":\"tx-2\",\"Memo\":null}"
+
+	txs, err := Parse(context.Background(), strings.NewReader(body), fieldmap.Mapping{}, amountparse.Rules{})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(txs))
+	}
+	if !txs[0].Date.Equal(time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)) || !txs[0].Amount.Equal(decimal.RequireFromString("60.5")) || txs[0].Category != "salary" {
+		t.Errorf("unexpected first transaction: %+v", txs[0])
+	}
+	if !txs[1].Amount.Equal(decimal.RequireFromString("-10.3")) || txs[1].Reference != "tx-2" || txs[1].Memo != "" {
+		t.Errorf("unexpected second transaction: %+v", txs[1])
+	}
+}
+
+func TestParse_CustomMapping(t *testing.T) {
+	mapping, err := fieldmap.Parse("date=fecha,amount=monto")
+	if err != nil {
+		t.Fatalf("fieldmap.Parse returned error: %v", err)
+	}
+
+	txs, err := Parse(context.Background(), strings.NewReader(`{"fecha":"2021-08-02","monto":-20.46}`), mapping, amountparse.Rules{})
+	if err != nil || len(txs) != 1 || !txs[0].Amount.Equal(decimal.RequireFromString("-20.46")) {
+		t.Fatalf("Parse = %+v, %v", txs, err)
+	}
+}
+
+func TestParse_InvalidLine(t *testing.T) {
+	cases := map[string]string{
+		"bad json":       "{\"Date\":\"2021-07-15\",\"Transaction\":1}\n{oops}\n",
+		"missing amount": "{\"Date\":\"2021-07-15\",\"Transaction\":1}\n{\"Date\":\"2021-07-16\"}\n",
+		"nested value":   "{\"Date\":\"2021-07-15\",\"Transaction\":1}\n{\"Date\":\"2021-07-16\",\"Transaction\":{\"v\":1}}\n",
+	}
+	for name, body := range cases {
+		_, err := Parse(context.Background(), strings.NewReader(body), fieldmap.Mapping{}, amountparse.Rules{})
+		if err == nil || !strings.HasPrefix(err.Error(), "línea 2:") {
+			t.Errorf("%s: expected an error on line 2, got %v", name, err)
+		}
+	}
+}
+
+func TestParse_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := Parse(ctx, strings.NewReader(`{"Date":"2021-07-15","Transaction":1}`), fieldmap.Mapping{}, amountparse.Rules{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+// Package ofxparse lee extractos OFX/QFX, tanto 1.x (SGML, sin etiquetas de
+// cierre en los valores) como 2.x (XML), y los traduce a transacciones.
+package ofxparse
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/shopspring/decimal"
+)
+
+// ErrNotOFX indica que el contenido no tiene un elemento <OFX>.
+var ErrNotOFX = errors.New("el archivo no es un extracto OFX")
+
+// Looks informa si el inicio del contenido parece OFX: la cabecera SGML
+// (OFXHEADER:100), la instrucción <?OFX ...?> de 2.x o directamente <OFX>.
+func Looks(head []byte) bool {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	upper := bytes.ToUpper(bytes.TrimSpace(head))
+	return bytes.HasPrefix(upper, []byte("OFXHEADER")) ||
+		bytes.HasPrefix(upper, []byte("<OFX>")) ||
+		bytes.Contains(upper, []byte("<?OFX"))
+}
+
+// Parse devuelve los STMTTRN del extracto en el orden del archivo. Una
+// transacción sin DTPOSTED o TRNAMT válidos corta el parseo con error.
+//
+// El escáner no distingue SGML de XML: el valor de un elemento es el texto
+// que sigue a su etiqueta de apertura hasta la siguiente etiqueta, lo que
+// cubre los valores sin cierre de 1.x y los cerrados de 2.x.
+func Parse(ctx context.Context, r io.Reader) ([]domain.Transaction, error) {
+	br := bufio.NewReader(r)
+
+	var (
+		txs     []domain.Transaction
+		current *statementTx
+		open    string
+		text    strings.Builder
+		seenOFX bool
+	)
+
+	for {
+		chunk, err := br.ReadString('<')
+		if open != "" {
+			text.WriteString(strings.TrimSuffix(chunk, "<"))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		tag, err := br.ReadString('>')
+		if err != nil {
+			return nil, fmt.Errorf("etiqueta sin cerrar: %w", io.ErrUnexpectedEOF)
+		}
+		tag = strings.TrimSuffix(tag, ">")
+
+		// El valor pendiente pertenece al último elemento abierto.
+		if current != nil && open != "" {
+			current.set(open, html.UnescapeString(strings.TrimSpace(text.String())))
+		}
+		open = ""
+		text.Reset()
+
+		if tag == "" || tag[0] == '?' || tag[0] == '!' {
+			continue
+		}
+
+		closing := tag[0] == '/'
+		name := strings.ToUpper(strings.TrimPrefix(tag, "/"))
+		if i := strings.IndexAny(name, " \t\r\n"); i >= 0 {
+			name = name[:i]
+		}
+		name = strings.TrimSuffix(name, "/")
+
+		switch {
+		case name == "OFX":
+			seenOFX = true
+		case name == "STMTTRN" && !closing:
+			current = &statementTx{}
+		case name == "STMTTRN" && closing:
+			if current == nil {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			tx, err := current.transaction()
+			if err != nil {
+				return nil, fmt.Errorf("STMTTRN %d: %w", len(txs)+1, err)
+			}
+			txs = append(txs, tx)
+			current = nil
+		case !closing:
+			open = name
+		}
+	}
+
+	if !seenOFX {
+		return nil, ErrNotOFX
+	}
+	return txs, nil
+}
+
+// statementTx junta los campos de un STMTTRN mientras se lee.
+type statementTx struct {
+	posted, amount, fitID, name, memo string
+}
+
+func (s *statementTx) set(element, value string) {
+	switch element {
+	case "DTPOSTED":
+		s.posted = value
+	case "TRNAMT":
+		s.amount = value
+	case "FITID":
+		s.fitID = value
+	case "NAME":
+		// NAME puede venir también dentro de PAYEE; se queda el primero.
+		if s.name == "" {
+			s.name = value
+		}
+	case "MEMO":
+		s.memo = value
+	}
+}
+
+func (s *statementTx) transaction() (domain.Transaction, error) {
+	date, err := parseDate(s.posted)
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("DTPOSTED inválido %q: %w", s.posted, err)
+	}
+	amount, err := decimal.NewFromString(strings.TrimPrefix(s.amount, "+"))
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("TRNAMT inválido %q: %w", s.amount, err)
+	}
+	return domain.Transaction{
+		Date:        date,
+		Amount:      amount,
+		Reference:   s.fitID,
+		Description: s.name,
+		Memo:        s.memo,
+	}, nil
+}
+
+// parseDate toma solo el día de un DTPOSTED (YYYYMMDD[HHMMSS[.XXX]][[offset:TZ]]),
+// que es lo que guarda una transacción del CSV.
+func parseDate(value string) (time.Time, error) {
+	if len(value) < 8 {
+		return time.Time{}, errors.New("se esperaba YYYYMMDD")
+	}
+	return time.Parse("20060102", value[:8])
+}
@@ -0,0 +1,150 @@
+package ofxparse
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+SECURITY:NONE
+ENCODING:USASCII
+CHARSET:1252
+COMPRESSION:NONE
+OLDFILEUID:NONE
+NEWFILEUID:NONE
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>USD
+<BANKTRANLIST>
+<DTSTART>20210701
+<DTEND>20210731
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20210715120000[-5:EST]
+<TRNAMT>+60.50
+<FITID>2021071501
+<NAME>Payroll
+<MEMO>July salary
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20210728
+<TRNAMT>-10.3
+<FITID>2021072802
+<NAME>Tom &amp; Jerry's
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>
+`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8" standalone="no"?>
+<?OFX OFXHEADER="200" VERSION="220" SECURITY="NONE" OLDFILEUID="NONE" NEWFILEUID="NONE"?>
+<OFX>
+  <CREDITCARDMSGSRSV1>
+    <CCSTMTTRNRS>
+      <CCSTMTRS>
+        <BANKTRANLIST>
+          <STMTTRN>
+            <TRNTYPE>DEBIT</TRNTYPE>
+            <DTPOSTED>20210802000000.000</DTPOSTED>
+            <TRNAMT>-25.00</TRNAMT>
+            <FITID>abc-1</FITID>
+            <PAYEE><NAME>Coffee &lt;Shop&gt;</NAME></PAYEE>
+            <MEMO/>
+          </STMTTRN>
+        </BANKTRANLIST>
+      </CCSTMTRS>
+    </CCSTMTTRNRS>
+  </CREDITCARDMSGSRSV1>
+</OFX>
+`
+
+func TestParse_SGML(t *testing.T) {
+	txs, err := Parse(context.Background(), strings.NewReader(sgmlStatement))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Fatalf("expected 2 transactions, got %d", len(txs))
+	}
+
+	first := txs[0]
+	if !first.Date.Equal(time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date = %v", first.Date)
+	}
+	if !first.Amount.Equal(decimal.RequireFromString("60.5")) {
+		t.Errorf("amount = %s", first.Amount)
+	}
+	if first.Reference != "2021071501" || first.Description != "Payroll" || first.Memo != "July salary" {
+		t.Errorf("unexpected fields: %+v", first)
+	}
+	if txs[1].Description != "Tom & Jerry's" || txs[1].Memo != "" {
+		t.Errorf("second transaction = %+v", txs[1])
+	}
+}
+
+func TestParse_XML(t *testing.T) {
+	txs, err := Parse(context.Background(), strings.NewReader(xmlStatement))
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(txs))
+	}
+	tx := txs[0]
+	if !tx.Date.Equal(time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC)) || !tx.Amount.Equal(decimal.RequireFromString("-25")) {
+		t.Errorf("unexpected date or amount: %+v", tx)
+	}
+	if tx.Reference != "abc-1" || tx.Description != "Coffee <Shop>" || tx.Memo != "" {
+		t.Errorf("unexpected fields: %+v", tx)
+	}
+}
+
+func TestParse_InvalidTransaction(t *testing.T) {
+	body := "<OFX><STMTTRN><DTPOSTED>20210715<TRNAMT>12.00</STMTTRN><STMTTRN><DTPOSTED>2021<TRNAMT>1</STMTTRN></OFX>"
+
+	_, err := Parse(context.Background(), strings.NewReader(body))
+	if err == nil || !strings.Contains(err.Error(), "STMTTRN 2") {
+		t.Fatalf("expected an error naming the second STMTTRN, got %v", err)
+	}
+}
+
+func TestParse_NotOFX(t *testing.T) {
+	_, err := Parse(context.Background(), strings.NewReader("Id,Date,Transaction\n0,7/15,+60.5\n"))
+	if !errors.Is(err, ErrNotOFX) {
+		t.Fatalf("expected ErrNotOFX, got %v", err)
+	}
+}
+
+func TestParse_CanceledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := Parse(ctx, strings.NewReader(sgmlStatement)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestLooks(t *testing.T) {
+	cases := map[string]bool{
+		sgmlStatement:                       true,
+		xmlStatement:                        true,
+		"\xef\xbb\xbf<OFX><SIGNONMSGSRSV1>": true,
+		"Id,Date,Transaction\n":             false,
+		"<?xml version=\"1.0\"?><doc/>":     false,
+	}
+	for content, want := range cases {
+		if got := Looks([]byte(content)); got != want {
+			t.Errorf("Looks(%.20q) = %v, want %v", content, got, want)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package parquetparse lee transacciones de archivos Parquet. Recorre los
+// row groups por lotes de filas, así un archivo grande no se carga completo en
+// memoria; los campos se ubican con el mismo mapeo de columnas que el CSV.
+package parquetparse
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/format"
+	"github.com/shopspring/decimal"
+)
+
+// batchSize es cuántas filas se leen de un row group por vez.
+const batchSize = 512
+
+var magic = []byte("PAR1")
+
+// Looks informa si el contenido empieza con los magic bytes de Parquet.
+func Looks(head []byte) bool {
+	return bytes.HasPrefix(head, magic)
+}
+
+// column es una columna hoja del esquema con su tipo, para convertir los
+// valores a texto.
+type column struct {
+	index int
+	typ   parquet.Type
+}
+
+// Parse necesita acceso aleatorio: el esquema y los row groups se leen del
+// footer. Las fechas pueden ser DATE, TIMESTAMP o texto (YYYY-MM-DD o RFC
+// 3339); los montos, DECIMAL, enteros, flotantes o texto.
+func Parse(ctx context.Context, r io.ReaderAt, size int64, mapping fieldmap.Mapping, amounts amountparse.Rules) ([]domain.Transaction, error) {
+	file, err := parquet.OpenFile(r, size)
+	if err != nil {
+		return nil, err
+	}
+
+	cols, err := resolve(file.Schema(), mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	var txs []domain.Transaction
+	rowNumber := 0
+	buf := make([]parquet.Row, batchSize)
+	for _, group := range file.RowGroups() {
+		rows := group.Rows()
+		for {
+			if err := ctx.Err(); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			n, readErr := rows.ReadRows(buf)
+			for _, row := range buf[:n] {
+				rowNumber++
+				tx, err := transaction(row, cols, amounts)
+				if err != nil {
+					rows.Close()
+					return nil, fmt.Errorf("fila %d: %w", rowNumber, err)
+				}
+				txs = append(txs, tx)
+			}
+			if errors.Is(readErr, io.EOF) {
+				break
+			}
+			if readErr != nil {
+				rows.Close()
+				return nil, readErr
+			}
+		}
+		if err := rows.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return txs, nil
+}
+
+// resolve ubica cada campo entre las columnas de primer nivel del esquema.
+func resolve(schema *parquet.Schema, mapping fieldmap.Mapping) (map[fieldmap.Field]column, error) {
+	cols := map[fieldmap.Field]column{}
+	for _, path := range schema.Columns() {
+		if len(path) != 1 {
+			continue
+		}
+		leaf, ok := schema.Lookup(path...)
+		if !ok {
+			continue
+		}
+		for _, f := range fieldmap.Fields() {
+			if _, taken := cols[f]; !taken && mapping.Match(f, path[0]) {
+				cols[f] = column{index: leaf.ColumnIndex, typ: leaf.Node.Type()}
+			}
+		}
+	}
+	err := mapping.Required(func(f fieldmap.Field) bool {
+		_, ok := cols[f]
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+func transaction(row parquet.Row, cols map[fieldmap.Field]column, amounts amountparse.Rules) (domain.Transaction, error) {
+	return fieldmap.Transaction(func(f fieldmap.Field) fieldmap.Value {
+		col, ok := cols[f]
+		if !ok {
+			return fieldmap.Value{}
+		}
+		for _, v := range row {
+			if v.Column() == col.index {
+				return value(v, col.typ)
+			}
+		}
+		return fieldmap.Value{}
+	}, fieldmap.ISODate, amounts)
+}
+
+// value lleva un valor a la forma que entiende fieldmap: fechas ISO y montos
+// decimales exactos. Solo el texto pasa por el formato regional de montos.
+func value(v parquet.Value, typ parquet.Type) fieldmap.Value {
+	if v.IsNull() {
+		return fieldmap.Value{}
+	}
+	text := text(v, typ)
+	if v.Kind() == parquet.ByteArray || v.Kind() == parquet.FixedLenByteArray {
+		_, isDecimal := logicalType(typ).(*format.DecimalType)
+		return fieldmap.Value{Text: text, Number: isDecimal}
+	}
+	return fieldmap.Value{Text: text, Number: true}
+}
+
+func logicalType(typ parquet.Type) any {
+	if lt := typ.LogicalType(); lt != nil {
+		return lt.Value
+	}
+	return nil
+}
+
+// text convierte según el tipo lógico de la columna o, si no tiene, según el
+// físico.
+func text(v parquet.Value, typ parquet.Type) string {
+	if lt := typ.LogicalType(); lt != nil {
+		switch logical := lt.Value.(type) {
+		case *format.DateType:
+			return time.Unix(int64(v.Int32())*86400, 0).UTC().Format("2006-01-02")
+		case *format.TimestampType:
+			unit := time.Millisecond
+			if logical.Unit.Value != nil {
+				unit = logical.Unit.Value.Duration()
+			}
+			return time.Unix(0, v.Int64()*int64(unit)).UTC().Format(time.RFC3339)
+		case *format.DecimalType:
+			return decimal.NewFromBigInt(unscaled(v), -logical.Scale).String()
+		}
+	}
+
+	switch v.Kind() {
+	case parquet.Boolean:
+		return strconv.FormatBool(v.Boolean())
+	case parquet.Int32:
+		return strconv.FormatInt(int64(v.Int32()), 10)
+	case parquet.Int64:
+		return strconv.FormatInt(v.Int64(), 10)
+	case parquet.Float:
+		return strconv.FormatFloat(float64(v.Float()), 'f', -1, 32)
+	case parquet.Double:
+		return strconv.FormatFloat(v.Double(), 'f', -1, 64)
+	default:
+		return strings.ToValidUTF8(string(v.ByteArray()), "")
+	}
+}
+
+// unscaled lee el entero sin escala de un DECIMAL: INT32, INT64 o bytes en
+// complemento a dos big-endian.
+func unscaled(v parquet.Value) *big.Int {
+	switch v.Kind() {
+	case parquet.Int32:
+		return big.NewInt(int64(v.Int32()))
+	case parquet.Int64:
+		return big.NewInt(v.Int64())
+	}
+	b := v.ByteArray()
+	n := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return n
+}
@@ -0,0 +1,114 @@
+package parquetparse
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/interfaces/out/amountparse"
+	"stori-challenge/internal/interfaces/out/fieldmap"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/shopspring/decimal"
+)
+
+type typedRow struct {
+	Date     int32   `parquet:"Date,date"`
+	Amount   int64   `parquet:"Transaction,decimal(2:18)"`
+	Category *string `parquet:"Category,optional"`
+}
+
+type looseRow struct {
+	Fecha  time.Time `parquet:"fecha,timestamp(millisecond)"`
+	Monto  float64   `parquet:"monto"`
+	Ref    string    `parquet:"reference"`
+	Nested struct {
+		Memo string `parquet:"memo"`
+	} `parquet:"nested"`
+}
+
+func writeParquet[T any](t *testing.T, rows []T, opts ...parquet.WriterOption) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := parquet.NewGenericWriter[T](&buf, opts...)
+	if _, err := w.Write(rows); err != nil {
+		t.Fatalf("write parquet: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close parquet: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParse_TypedColumnsAcrossRowGroups(t *testing.T) {
+	salary := "salary"
+	july := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	var rows []typedRow
+	for i := range 5 {
+		days := int32(july.AddDate(0, 0, i).Unix() / 86400)
+		rows = append(rows, typedRow{Date: days, Amount: int64(-1000 + i)})
+	}
+	rows[0].Category = &salary
+	body := writeParquet(t, rows, parquet.MaxRowsPerRowGroup(2))
+
+	txs, err := Parse(context.Background(), bytes.NewReader(body), int64(len(body)), fieldmap.Mapping{}, amountparse.Rules{})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != len(rows) {
+		t.Fatalf("expected %d transactions, got %d", len(rows), len(txs))
+	}
+	for i, tx := range txs {
+		if !tx.Date.Equal(july.AddDate(0, 0, i)) {
+			t.Errorf("row %d date = %v", i, tx.Date)
+		}
+		if want := decimal.New(int64(-1000+i), -2); !tx.Amount.Equal(want) {
+			t.Errorf("row %d amount = %s, want %s", i, tx.Amount, want)
+		}
+	}
+	if txs[0].Category != "salary" || txs[1].Category != "" {
+		t.Errorf("categories = %q, %q", txs[0].Category, txs[1].Category)
+	}
+}
+
+func TestParse_CustomMappingWithTimestampAndFloat(t *testing.T) {
+	row := looseRow{Fecha: time.Date(2021, 8, 2, 18, 30, 0, 0, time.UTC), Monto: -20.46, Ref: "r-1"}
+	row.Nested.Memo = "ignored"
+	body := writeParquet(t, []looseRow{row})
+
+	mapping, err := fieldmap.Parse("date=fecha,amount=monto,memo=memo")
+	if err != nil {
+		t.Fatalf("fieldmap.Parse returned error: %v", err)
+	}
+	txs, err := Parse(context.Background(), bytes.NewReader(body), int64(len(body)), mapping, amountparse.Rules{})
+	if err != nil {
+		t.Fatalf("Parse returned error: %v", err)
+	}
+	if len(txs) != 1 {
+		t.Fatalf("expected 1 transaction, got %d", len(txs))
+	}
+	tx := txs[0]
+	if !tx.Date.Equal(time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC)) || !tx.Amount.Equal(decimal.RequireFromString("-20.46")) {
+		t.Errorf("unexpected date or amount: %+v", tx)
+	}
+	if tx.Reference != "r-1" || tx.Memo != "" {
+		t.Errorf("nested columns must not be mapped: %+v", tx)
+	}
+}
+
+func TestParse_MissingColumn(t *testing.T) {
+	body := writeParquet(t, []looseRow{{}})
+
+	_, err := Parse(context.Background(), bytes.NewReader(body), int64(len(body)), fieldmap.Mapping{}, amountparse.Rules{})
+	if err == nil || !strings.Contains(err.Error(), "Date") {
+		t.Fatalf("expected a missing column error, got %v", err)
+	}
+}
+
+func TestLooks(t *testing.T) {
+	if !Looks([]byte("PAR1")) || Looks([]byte("Id,Date")) {
+		t.Errorf("Looks should only accept the Parquet magic bytes")
+	}
+}
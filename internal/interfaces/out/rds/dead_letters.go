@@ -0,0 +1,28 @@
+package rds
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+	"stori-challenge/internal/interfaces/out/rds/mappers"
+
+	"gorm.io/gorm"
+)
+
+type DeadLetterRepo struct {
+	db *gorm.DB
+}
+
+var _ out.DeadLetterRepo = (*DeadLetterRepo)(nil)
+
+func NewDeadLetterRepo(db *gorm.DB) *DeadLetterRepo {
+	return &DeadLetterRepo{db: db}
+}
+
+func (r *DeadLetterRepo) SaveDeadLetter(ctx context.Context, dl domain.DeadLetter) (domain.DeadLetter, error) {
+	record := mappers.ToDeadLetterModel(dl)
+	if err := r.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return domain.DeadLetter{}, err
+	}
+	return mappers.ToDeadLetterDomain(record), nil
+}
@@ -0,0 +1,31 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func TestDeadLetterRepo_SaveDeadLetter(t *testing.T) {
+	repo := NewDeadLetterRepo(setupTestDB(t))
+
+	saved, err := repo.SaveDeadLetter(context.Background(), domain.DeadLetter{
+		Source:       "sqs",
+		MessageID:    "msg-1",
+		Bucket:       "bucket",
+		ObjectKey:    "input/acc-1/a.csv",
+		Payload:      `{"Records":[]}`,
+		Error:        "boom",
+		ReceiveCount: 5,
+	})
+	if err != nil {
+		t.Fatalf("SaveDeadLetter returned error: %v", err)
+	}
+	if saved.ID == 0 || saved.CreatedAt.IsZero() {
+		t.Errorf("expected ID and CreatedAt to be set, got %+v", saved)
+	}
+	if saved.MessageID != "msg-1" || saved.ObjectKey != "input/acc-1/a.csv" || saved.ReceiveCount != 5 {
+		t.Errorf("unexpected dead letter: %+v", saved)
+	}
+}
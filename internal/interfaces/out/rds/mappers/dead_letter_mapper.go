@@ -0,0 +1,34 @@
+package mappers
+
+import (
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func ToDeadLetterModel(dl domain.DeadLetter) models.DeadLetter {
+	return models.DeadLetter{
+		ID:           dl.ID,
+		Source:       dl.Source,
+		MessageID:    dl.MessageID,
+		Bucket:       dl.Bucket,
+		ObjectKey:    dl.ObjectKey,
+		Payload:      dl.Payload,
+		Error:        dl.Error,
+		ReceiveCount: dl.ReceiveCount,
+		CreatedAt:    dl.CreatedAt,
+	}
+}
+
+func ToDeadLetterDomain(record models.DeadLetter) domain.DeadLetter {
+	return domain.DeadLetter{
+		ID:           record.ID,
+		Source:       record.Source,
+		MessageID:    record.MessageID,
+		Bucket:       record.Bucket,
+		ObjectKey:    record.ObjectKey,
+		Payload:      record.Payload,
+		Error:        record.Error,
+		ReceiveCount: record.ReceiveCount,
+		CreatedAt:    record.CreatedAt,
+	}
+}
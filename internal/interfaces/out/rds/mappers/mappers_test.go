@@ -1,7 +1,7 @@
 package mappers
 
 import (
-	"encoding/json"
+	"reflect"
 	"testing"
 	"time"
 
@@ -39,6 +39,9 @@ func TestToTransactionModels_FillsFieldsCorrectly(t *testing.T) {
 		if m.ObjectKey != key {
 			t.Errorf("modelsTx[%d].ObjectKey = %q, want %q", i, m.ObjectKey, key)
 		}
+		if m.AccountID != "input" {
+			t.Errorf("modelsTx[%d].AccountID = %q, want %q", i, m.AccountID, "input")
+		}
 		if !m.Date.Equal(txs[i].Date) {
 			t.Errorf("modelsTx[%d].Date = %v, want %v", i, m.Date, txs[i].Date)
 		}
@@ -64,7 +67,7 @@ func TestToTransactionModels_EmptySlice(t *testing.T) {
 	}
 }
 
-func TestToAccountSummaryModel_MapsFieldsAndJSON(t *testing.T) {
+func TestToAccountSummaryModel_MapsFieldsAndMonths(t *testing.T) {
 	bucket := "stori-transactions-local"
 	key := "input/txns.csv"
 
@@ -86,10 +89,7 @@ func TestToAccountSummaryModel_MapsFieldsAndJSON(t *testing.T) {
 		},
 	}
 
-	model, err := ToAccountSummaryModel(bucket, key, summary)
-	if err != nil {
-		t.Fatalf("ToAccountSummaryModel returned error: %v", err)
-	}
+	model := ToAccountSummaryModel(bucket, key, summary)
 
 	if model.Bucket != bucket {
 		t.Errorf("Bucket = %q, want %q", model.Bucket, bucket)
@@ -97,34 +97,91 @@ func TestToAccountSummaryModel_MapsFieldsAndJSON(t *testing.T) {
 	if model.ObjectKey != key {
 		t.Errorf("ObjectKey = %q, want %q", model.ObjectKey, key)
 	}
+	if model.AccountID != "input" {
+		t.Errorf("AccountID = %q, want %q (derived from key)", model.AccountID, "input")
+	}
 	if !model.TotalBalance.Equal(summary.TotalBalance) {
 		t.Errorf("TotalBalance = %v, want %v", model.TotalBalance, summary.TotalBalance)
 	}
 
-	var decoded []domain.MonthlySummary
-	if err := json.Unmarshal([]byte(model.RawSummary), &decoded); err != nil {
-		t.Fatalf("RawSummary no es JSON válido: %v", err)
+	if len(model.Months) != len(summary.ByMonth) {
+		t.Fatalf("len(Months) = %d, want %d", len(model.Months), len(summary.ByMonth))
 	}
 
-	if len(decoded) != len(summary.ByMonth) {
-		t.Fatalf("len(decoded) = %d, want %d", len(decoded), len(summary.ByMonth))
-	}
-
-	for i := range decoded {
-		got := decoded[i]
+	for i := range model.Months {
+		got := model.Months[i]
 		want := summary.ByMonth[i]
 
-		if got.MonthName != want.MonthName {
-			t.Errorf("ByMonth[%d].MonthName = %q, want %q", i, got.MonthName, want.MonthName)
+		if got.Month != want.MonthName {
+			t.Errorf("Months[%d].Month = %q, want %q", i, got.Month, want.MonthName)
 		}
 		if got.TransactionsCount != want.TransactionsCount {
-			t.Errorf("ByMonth[%d].TransactionsCount = %d, want %d", i, got.TransactionsCount, want.TransactionsCount)
+			t.Errorf("Months[%d].TransactionsCount = %d, want %d", i, got.TransactionsCount, want.TransactionsCount)
 		}
 		if !got.AverageDebitAmount.Equal(want.AverageDebitAmount) {
-			t.Errorf("ByMonth[%d].AverageDebitAmount = %v, want %v", i, got.AverageDebitAmount, want.AverageDebitAmount)
+			t.Errorf("Months[%d].AverageDebitAmount = %v, want %v", i, got.AverageDebitAmount, want.AverageDebitAmount)
 		}
 		if !got.AverageCreditAmount.Equal(want.AverageCreditAmount) {
-			t.Errorf("ByMonth[%d].AverageCreditAmount = %v, want %v", i, got.AverageCreditAmount, want.AverageCreditAmount)
+			t.Errorf("Months[%d].AverageCreditAmount = %v, want %v", i, got.AverageCreditAmount, want.AverageCreditAmount)
+		}
+		if len(got.Comparisons) != 0 {
+			t.Errorf("Months[%d].Comparisons = %v, want none", i, got.Comparisons)
+		}
+	}
+}
+
+func TestAccountSummaryMapping_RoundTripIsLossless(t *testing.T) {
+	summary := domain.AccountSummary{
+		AccountID:    "acc-1",
+		TotalBalance: dec("39.74"),
+		ByMonth: []domain.MonthlySummary{
+			{
+				MonthName:           "2021-07",
+				TransactionsCount:   3,
+				AverageDebitAmount:  dec("-15.3833333333333333"),
+				AverageCreditAmount: dec("35.25"),
+				TotalDebitAmount:    dec("-46.15"),
+				TotalCreditAmount:   dec("70.5"),
+			},
+			{
+				MonthName:         "2021-08",
+				TransactionsCount: 1,
+				TotalCreditAmount: dec("10"),
+				VsPreviousMonth: &domain.MonthlyDelta{
+					BaselineMonth:       "2021-07",
+					TransactionsCount:   -2,
+					AverageDebitAmount:  dec("15.3833333333333333"),
+					AverageCreditAmount: dec("-25.25"),
+					TotalDebitAmount:    dec("46.15"),
+					TotalCreditAmount:   dec("-60.5"),
+				},
+				VsPreviousYear: &domain.MonthlyDelta{
+					BaselineMonth:     "2020-08",
+					TransactionsCount: 1,
+				},
+			},
+		},
+	}
+
+	got := ToAccountSummaryDomain(ToAccountSummaryModel("bucket", "input/acc-1/txns.csv", summary))
+
+	if !reflect.DeepEqual(got, summary) {
+		t.Fatalf("round trip mismatch:\n got  %+v\n want %+v", got, summary)
+	}
+}
+
+func TestToTransactionModels_ZipEntryAccount(t *testing.T) {
+	cases := map[string]string{
+		"input/acc-1/export.zip#txns.csv":        "acc-1",
+		"input/acc-1/export.zip#acc-2/txns.csv":  "acc-2",
+		"processed/input/acc-1/EXPORT.ZIP#a.csv": "acc-1",
+		"input/acc#1/txns.csv":                   "acc#1",
+	}
+
+	for key, want := range cases {
+		models := ToTransactionModels("bucket", key, []domain.Transaction{{Amount: dec("1")}})
+		if models[0].AccountID != want {
+			t.Errorf("%s: AccountID = %q, want %q", key, models[0].AccountID, want)
 		}
 	}
 }
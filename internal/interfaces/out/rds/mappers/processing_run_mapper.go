@@ -0,0 +1,61 @@
+package mappers
+
+import (
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func ToProcessingRunModel(run domain.ProcessingRun) models.ProcessingRun {
+	return models.ProcessingRun{
+		ID:                run.ID,
+		AccountID:         run.AccountID,
+		Bucket:            run.Bucket,
+		ObjectKey:         run.ObjectKey,
+		VersionID:         run.VersionID,
+		Status:            string(run.Status),
+		Error:             run.Error,
+		TransactionsCount: run.TransactionsCount,
+		Encoding:          run.Dialect.Encoding,
+		HasBOM:            run.Dialect.BOM,
+		Delimiter:         delimiterString(run.Dialect.Delimiter),
+		CreatedAt:         run.CreatedAt,
+		StartedAt:         run.StartedAt,
+		FinishedAt:        run.FinishedAt,
+	}
+}
+
+func ToProcessingRunDomain(record models.ProcessingRun) domain.ProcessingRun {
+	return domain.ProcessingRun{
+		ID:                record.ID,
+		AccountID:         record.AccountID,
+		Bucket:            record.Bucket,
+		ObjectKey:         record.ObjectKey,
+		VersionID:         record.VersionID,
+		Status:            domain.RunStatus(record.Status),
+		Error:             record.Error,
+		TransactionsCount: record.TransactionsCount,
+		Dialect: domain.Dialect{
+			Encoding:  record.Encoding,
+			BOM:       record.HasBOM,
+			Delimiter: delimiterRune(record.Delimiter),
+		},
+		CreatedAt:  record.CreatedAt,
+		StartedAt:  record.StartedAt,
+		FinishedAt: record.FinishedAt,
+	}
+}
+
+// El separador se guarda como texto; una corrida sin dialecto lo deja vacío.
+func delimiterString(d rune) string {
+	if d == 0 {
+		return ""
+	}
+	return string(d)
+}
+
+func delimiterRune(s string) rune {
+	for _, r := range s {
+		return r
+	}
+	return 0
+}
@@ -0,0 +1,17 @@
+package mappers
+
+import (
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func ToReplayCheckpointModel(cp domain.ReplayCheckpoint) models.ReplayCheckpoint {
+	return models.ReplayCheckpoint{
+		ReplayID:  cp.ReplayID,
+		Bucket:    cp.Bucket,
+		ObjectKey: cp.ObjectKey,
+		Status:    string(cp.Status),
+		Error:     cp.Error,
+		UpdatedAt: cp.UpdatedAt,
+	}
+}
@@ -0,0 +1,24 @@
+package mappers
+
+import (
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func ToRewardEntryModels(bucket, key string, entries []domain.RewardEntry) []models.RewardEntry {
+	result := make([]models.RewardEntry, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, models.RewardEntry{
+			AccountID:       e.AccountID,
+			Cycle:           e.Cycle,
+			Bucket:          bucket,
+			ObjectKey:       key,
+			Category:        e.Category,
+			TransactionDate: e.Date,
+			Amount:          e.Amount,
+			Points:          e.Points,
+			Cashback:        e.Cashback,
+		})
+	}
+	return result
+}
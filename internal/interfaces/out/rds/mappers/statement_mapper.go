@@ -0,0 +1,19 @@
+package mappers
+
+import (
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func ToStatementDomain(record models.Statement) domain.Statement {
+	return domain.Statement{
+		ID:                record.ID,
+		AccountID:         record.AccountID,
+		Period:            record.Period,
+		Status:            domain.StatementStatus(record.Status),
+		Error:             record.Error,
+		TransactionsCount: record.TransactionsCount,
+		CreatedAt:         record.CreatedAt,
+		SentAt:            record.SentAt,
+	}
+}
@@ -0,0 +1,113 @@
+package mappers
+
+import (
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+// ToAccountSummaryModel mapea el balance y el desglose mensual (incluidos los
+// comparativos). Rewards y Forecast son derivados y no se persisten aquí.
+func ToAccountSummaryModel(bucket, key string, summary domain.AccountSummary) models.AccountSummary {
+	accountID := summary.AccountID
+	if accountID == "" {
+		accountID = domain.AccountIDFromObjectKey(key)
+	}
+
+	months := make([]models.MonthlySummary, 0, len(summary.ByMonth))
+	for _, m := range summary.ByMonth {
+		months = append(months, toMonthlySummaryModel(m))
+	}
+
+	return models.AccountSummary{
+		AccountID:      accountID,
+		Bucket:         bucket,
+		ObjectKey:      key,
+		TotalBalance:   summary.TotalBalance,
+		OpeningBalance: summary.OpeningBalance,
+		ClosingBalance: summary.ClosingBalance,
+		Months:         months,
+	}
+}
+
+func ToAccountSummaryDomain(record models.AccountSummary) domain.AccountSummary {
+	var byMonth []domain.MonthlySummary
+	for _, m := range record.Months {
+		byMonth = append(byMonth, ToMonthlySummaryDomain(m))
+	}
+
+	return domain.AccountSummary{
+		AccountID:      record.AccountID,
+		TotalBalance:   record.TotalBalance,
+		OpeningBalance: record.OpeningBalance,
+		ClosingBalance: record.ClosingBalance,
+		ByMonth:        byMonth,
+	}
+}
+
+func ToMonthlySummaryDomain(m models.MonthlySummary) domain.MonthlySummary {
+	ms := domain.MonthlySummary{
+		MonthName:           m.Month,
+		TransactionsCount:   m.TransactionsCount,
+		AverageDebitAmount:  m.AverageDebitAmount,
+		AverageCreditAmount: m.AverageCreditAmount,
+		TotalDebitAmount:    m.TotalDebitAmount,
+		TotalCreditAmount:   m.TotalCreditAmount,
+	}
+	for _, c := range m.Comparisons {
+		delta := &domain.MonthlyDelta{
+			BaselineMonth:       c.BaselineMonth,
+			TransactionsCount:   c.TransactionsCountDelta,
+			AverageDebitAmount:  c.AverageDebitDelta,
+			AverageCreditAmount: c.AverageCreditDelta,
+			TotalDebitAmount:    c.TotalDebitDelta,
+			TotalCreditAmount:   c.TotalCreditDelta,
+		}
+		switch c.Baseline {
+		case models.BaselinePreviousMonth:
+			ms.VsPreviousMonth = delta
+		case models.BaselinePreviousYear:
+			ms.VsPreviousYear = delta
+		}
+	}
+	return ms
+}
+
+func toMonthlySummaryModel(m domain.MonthlySummary) models.MonthlySummary {
+	record := models.MonthlySummary{
+		Month:               m.MonthName,
+		TransactionsCount:   m.TransactionsCount,
+		AverageDebitAmount:  m.AverageDebitAmount,
+		AverageCreditAmount: m.AverageCreditAmount,
+		TotalDebitAmount:    m.TotalDebitAmount,
+		TotalCreditAmount:   m.TotalCreditAmount,
+	}
+	if m.VsPreviousMonth != nil {
+		record.Comparisons = append(record.Comparisons, toComparisonModel(models.BaselinePreviousMonth, *m.VsPreviousMonth))
+	}
+	if m.VsPreviousYear != nil {
+		record.Comparisons = append(record.Comparisons, toComparisonModel(models.BaselinePreviousYear, *m.VsPreviousYear))
+	}
+	return record
+}
+
+func toComparisonModel(baseline string, d domain.MonthlyDelta) models.MonthlyComparison {
+	return models.MonthlyComparison{
+		Baseline:               baseline,
+		BaselineMonth:          d.BaselineMonth,
+		TransactionsCountDelta: d.TransactionsCount,
+		AverageDebitDelta:      d.AverageDebitAmount,
+		AverageCreditDelta:     d.AverageCreditAmount,
+		TotalDebitDelta:        d.TotalDebitAmount,
+		TotalCreditDelta:       d.TotalCreditAmount,
+	}
+}
+
+func ToStoredSummaryDomain(record models.AccountSummary) domain.StoredSummary {
+	return domain.StoredSummary{
+		ID:        uint64(record.ID),
+		Bucket:    record.Bucket,
+		ObjectKey: record.ObjectKey,
+		CreatedAt: record.CreatedAt,
+		Summary:   ToAccountSummaryDomain(record),
+	}
+}
@@ -1,34 +1,61 @@
 package mappers
 
 import (
-	"encoding/json"
 	"stori-challenge/internal/core/domain"
 	"stori-challenge/internal/interfaces/out/rds/models"
 )
 
 func ToTransactionModels(bucket, key string, txs []domain.Transaction) []models.Transaction {
+	accountID := domain.AccountIDFromObjectKey(key)
 	result := make([]models.Transaction, 0, len(txs))
 	for _, t := range txs {
 		result = append(result, models.Transaction{
-			Bucket:    bucket,
-			ObjectKey: key,
-			Date:      t.Date,
-			Amount:    t.Amount,
+			AccountID:   accountID,
+			Bucket:      bucket,
+			ObjectKey:   key,
+			Date:        t.Date,
+			Amount:      t.Amount,
+			Category:    t.Category,
+			Reference:   t.Reference,
+			Description: t.Description,
+			Memo:        t.Memo,
+			Currency:    t.Currency,
 		})
 	}
 	return result
 }
 
-func ToAccountSummaryModel(bucket, key string, summary domain.AccountSummary) (models.AccountSummary, error) {
-	raw, err := json.Marshal(summary.ByMonth)
-	if err != nil {
-		return models.AccountSummary{}, err
+func ToTransactionDomains(records []models.Transaction) []domain.Transaction {
+	result := make([]domain.Transaction, 0, len(records))
+	for _, r := range records {
+		result = append(result, domain.Transaction{
+			Date:        r.Date,
+			Amount:      r.Amount,
+			Category:    r.Category,
+			Reference:   r.Reference,
+			Description: r.Description,
+			Memo:        r.Memo,
+			Currency:    r.Currency,
+		})
 	}
+	return result
+}
 
-	return models.AccountSummary{
-		Bucket:       bucket,
-		ObjectKey:    key,
-		TotalBalance: summary.TotalBalance,
-		RawSummary:   string(raw),
-	}, nil
+func ToTransactionRecordDomain(r models.Transaction) domain.TransactionRecord {
+	return domain.TransactionRecord{
+		Transaction: domain.Transaction{
+			Date:        r.Date,
+			Amount:      r.Amount,
+			Category:    r.Category,
+			Reference:   r.Reference,
+			Description: r.Description,
+			Memo:        r.Memo,
+			Currency:    r.Currency,
+		},
+		ID:        uint64(r.ID),
+		AccountID: r.AccountID,
+		Bucket:    r.Bucket,
+		ObjectKey: r.ObjectKey,
+		CreatedAt: r.CreatedAt,
+	}
 }
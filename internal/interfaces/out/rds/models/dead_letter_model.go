@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type DeadLetter struct {
+	ID           uint64 `gorm:"primaryKey"`
+	Source       string `gorm:"size:32;not null"`
+	MessageID    string `gorm:"size:255;index"`
+	Bucket       string `gorm:"size:255;index:idx_dead_letters_object"`
+	ObjectKey    string `gorm:"size:512;index:idx_dead_letters_object"`
+	Payload      string `gorm:"type:text"`
+	Error        string `gorm:"type:text;not null"`
+	ReceiveCount int    `gorm:"not null;default:0"`
+	CreatedAt    time.Time
+}
+
+func (dl *DeadLetter) TableName() string {
+	return "transactions.dead_letters"
+}
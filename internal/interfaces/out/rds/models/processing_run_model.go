@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type ProcessingRun struct {
+	ID                uint64 `gorm:"primaryKey"`
+	AccountID         string `gorm:"size:255;index"`
+	Bucket            string `gorm:"size:255;index:idx_processing_runs_object"`
+	ObjectKey         string `gorm:"size:512;index:idx_processing_runs_object"`
+	VersionID         string `gorm:"size:1024"`
+	Status            string `gorm:"size:16;not null;index"`
+	Error             string `gorm:"type:text"`
+	TransactionsCount int    `gorm:"not null;default:0"`
+	Encoding          string `gorm:"size:32"`
+	HasBOM            bool   `gorm:"not null;default:false"`
+	Delimiter         string `gorm:"size:4"`
+	CreatedAt         time.Time
+	StartedAt         *time.Time
+	FinishedAt        *time.Time
+}
+
+func (pr *ProcessingRun) TableName() string {
+	return "transactions.processing_runs"
+}
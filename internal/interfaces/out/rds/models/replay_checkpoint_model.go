@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+type ReplayCheckpoint struct {
+	ID        uint64 `gorm:"primaryKey"`
+	ReplayID  string `gorm:"size:64;not null;uniqueIndex:idx_replay_checkpoints_object"`
+	Bucket    string `gorm:"size:255;not null;uniqueIndex:idx_replay_checkpoints_object"`
+	ObjectKey string `gorm:"size:512;not null;uniqueIndex:idx_replay_checkpoints_object"`
+	Status    string `gorm:"size:16;not null"`
+	Error     string `gorm:"type:text"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (rc *ReplayCheckpoint) TableName() string {
+	return "transactions.replay_checkpoints"
+}
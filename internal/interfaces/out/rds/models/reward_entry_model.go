@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type RewardEntry struct {
+	ID              uint   `gorm:"primaryKey"`
+	AccountID       string `gorm:"size:255;index:idx_reward_entries_account_cycle"`
+	Cycle           string `gorm:"size:7;index:idx_reward_entries_account_cycle"`
+	Bucket          string `gorm:"size:255"`
+	ObjectKey       string `gorm:"size:512"`
+	Category        string `gorm:"size:64"`
+	TransactionDate time.Time
+	Amount          decimal.Decimal `gorm:"type:numeric(15,2)"`
+	Points          decimal.Decimal `gorm:"type:numeric(15,2)"`
+	Cashback        decimal.Decimal `gorm:"type:numeric(15,2)"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime"`
+}
+
+func (re *RewardEntry) TableName() string {
+	return "transactions.reward_entries"
+}
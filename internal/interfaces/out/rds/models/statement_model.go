@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+type Statement struct {
+	ID                uint64 `gorm:"primaryKey"`
+	AccountID         string `gorm:"size:255;not null;uniqueIndex:idx_statements_account_period"`
+	Period            string `gorm:"size:7;not null;uniqueIndex:idx_statements_account_period"`
+	Status            string `gorm:"size:16;not null"`
+	Error             string `gorm:"type:text"`
+	TransactionsCount int    `gorm:"not null;default:0"`
+	CreatedAt         time.Time
+	ClaimedAt         time.Time
+	SentAt            *time.Time
+}
+
+func (s *Statement) TableName() string {
+	return "transactions.statements"
+}
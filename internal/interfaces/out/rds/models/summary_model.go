@@ -7,12 +7,16 @@ import (
 )
 
 type AccountSummary struct {
-	ID           uint   `gorm:"primaryKey"`
-	Bucket       string `gorm:"size:255;index"`
-	ObjectKey    string `gorm:"size:512;index"`
-	TotalBalance decimal.Decimal
+	ID           uint            `gorm:"primaryKey"`
+	AccountID    string          `gorm:"size:255;index"`
+	Bucket       string          `gorm:"size:255;index"`
+	ObjectKey    string          `gorm:"size:512;index"`
+	TotalBalance decimal.Decimal `gorm:"type:numeric"`
+	// Saldos declarados por el extracto; NULL si el archivo no los trae.
+	OpeningBalance *decimal.Decimal `gorm:"type:numeric"`
+	ClosingBalance *decimal.Decimal `gorm:"type:numeric"`
 
-	RawSummary string `gorm:"type:text"`
+	Months []MonthlySummary `gorm:"foreignKey:AccountSummaryID;constraint:OnDelete:CASCADE"`
 
 	CreatedAt time.Time
 }
@@ -20,3 +24,41 @@ type AccountSummary struct {
 func (as *AccountSummary) TableName() string {
 	return "transactions.account_summaries"
 }
+
+type MonthlySummary struct {
+	ID                  uint            `gorm:"primaryKey"`
+	AccountSummaryID    uint            `gorm:"not null;uniqueIndex:uq_monthly_summaries_summary_month"`
+	Month               string          `gorm:"size:7;not null;index;uniqueIndex:uq_monthly_summaries_summary_month"`
+	TransactionsCount   int             `gorm:"not null"`
+	AverageDebitAmount  decimal.Decimal `gorm:"type:numeric;not null"`
+	AverageCreditAmount decimal.Decimal `gorm:"type:numeric;not null"`
+	TotalDebitAmount    decimal.Decimal `gorm:"type:numeric;not null"`
+	TotalCreditAmount   decimal.Decimal `gorm:"type:numeric;not null"`
+
+	Comparisons []MonthlyComparison `gorm:"foreignKey:MonthlySummaryID;constraint:OnDelete:CASCADE"`
+}
+
+func (ms *MonthlySummary) TableName() string {
+	return "transactions.monthly_summaries"
+}
+
+const (
+	BaselinePreviousMonth = "previous_month"
+	BaselinePreviousYear  = "previous_year"
+)
+
+type MonthlyComparison struct {
+	ID                     uint            `gorm:"primaryKey"`
+	MonthlySummaryID       uint            `gorm:"not null;uniqueIndex:uq_monthly_comparisons_summary_baseline"`
+	Baseline               string          `gorm:"size:16;not null;uniqueIndex:uq_monthly_comparisons_summary_baseline"`
+	BaselineMonth          string          `gorm:"size:7;not null"`
+	TransactionsCountDelta int             `gorm:"not null"`
+	AverageDebitDelta      decimal.Decimal `gorm:"type:numeric;not null"`
+	AverageCreditDelta     decimal.Decimal `gorm:"type:numeric;not null"`
+	TotalDebitDelta        decimal.Decimal `gorm:"type:numeric;not null"`
+	TotalCreditDelta       decimal.Decimal `gorm:"type:numeric;not null"`
+}
+
+func (mc *MonthlyComparison) TableName() string {
+	return "transactions.monthly_comparisons"
+}
@@ -7,12 +7,18 @@ import (
 )
 
 type Transaction struct {
-	ID        uint            `gorm:"primaryKey"`
-	Bucket    string          `gorm:"size:255;index"`
-	ObjectKey string          `gorm:"size:512;index"`
-	Date      time.Time       `gorm:"index"`
-	Amount    decimal.Decimal `gorm:"type:numeric(15,2)"`
-	CreatedAt time.Time       `gorm:"autoCreateTime"`
+	ID          uint            `gorm:"primaryKey"`
+	AccountID   string          `gorm:"size:255;index"`
+	Bucket      string          `gorm:"size:255;index"`
+	ObjectKey   string          `gorm:"size:512;index"`
+	Date        time.Time       `gorm:"index"`
+	Amount      decimal.Decimal `gorm:"type:numeric(15,2)"`
+	Category    string          `gorm:"size:64"`
+	Reference   string          `gorm:"size:255"`
+	Description string          `gorm:"type:text"`
+	Memo        string          `gorm:"type:text"`
+	Currency    string          `gorm:"size:3"`
+	CreatedAt   time.Time       `gorm:"autoCreateTime"`
 }
 
 func (tx *Transaction) TableName() string {
@@ -0,0 +1,137 @@
+package rds
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/mappers"
+	"stori-challenge/internal/interfaces/out/rds/models"
+	"time"
+)
+
+func (r *TransactionRepo) CreateProcessingRun(
+	ctx context.Context,
+	run domain.ProcessingRun,
+) (domain.ProcessingRun, error) {
+	if run.AccountID == "" {
+		run.AccountID = domain.AccountIDFromObjectKey(run.ObjectKey)
+	}
+	if run.Status == "" {
+		run.Status = domain.RunStatusPending
+	}
+	record := mappers.ToProcessingRunModel(run)
+	if err := r.db.WithContext(ctx).Create(&record).Error; err != nil {
+		return domain.ProcessingRun{}, err
+	}
+	return mappers.ToProcessingRunDomain(record), nil
+}
+
+// StartProcessingRun toma la corrida pendiente más reciente del objeto (la
+// que crea una subida, por ejemplo) o abre una nueva si no hay ninguna. La
+// versión del evento se guarda en la corrida; una entrada de zip se registra
+// con su key lógica.
+func (r *TransactionRepo) StartProcessingRun(
+	ctx context.Context,
+	obj domain.ObjectRef,
+) (domain.ProcessingRun, error) {
+	now := time.Now().UTC()
+	key := obj.FileKey()
+
+	var record models.ProcessingRun
+	res := r.db.WithContext(ctx).
+		Where("bucket = ? AND object_key = ? AND status = ?", obj.Bucket, key, string(domain.RunStatusPending)).
+		Order("id DESC").
+		Limit(1).
+		Find(&record)
+	if res.Error != nil {
+		return domain.ProcessingRun{}, res.Error
+	}
+
+	if res.RowsAffected == 0 {
+		return r.CreateProcessingRun(ctx, domain.ProcessingRun{
+			Bucket:    obj.Bucket,
+			ObjectKey: key,
+			VersionID: obj.VersionID,
+			Status:    domain.RunStatusProcessing,
+			StartedAt: &now,
+		})
+	}
+
+	record.Status = string(domain.RunStatusProcessing)
+	record.StartedAt = &now
+	updates := map[string]any{"status": record.Status, "started_at": now}
+	if obj.VersionID != "" {
+		record.VersionID = obj.VersionID
+		updates["version_id"] = obj.VersionID
+	}
+	err := r.db.WithContext(ctx).
+		Model(&record).
+		Updates(updates).Error
+	if err != nil {
+		return domain.ProcessingRun{}, err
+	}
+	return mappers.ToProcessingRunDomain(record), nil
+}
+
+func (r *TransactionRepo) FinishProcessingRun(ctx context.Context, run domain.ProcessingRun) error {
+	finished := time.Now().UTC()
+	if run.FinishedAt != nil {
+		finished = *run.FinishedAt
+	}
+	record := mappers.ToProcessingRunModel(run)
+	return r.db.WithContext(ctx).
+		Model(&models.ProcessingRun{ID: run.ID}).
+		Updates(map[string]any{
+			"status":             string(run.Status),
+			"error":              run.Error,
+			"transactions_count": run.TransactionsCount,
+			"encoding":           record.Encoding,
+			"has_bom":            record.HasBOM,
+			"delimiter":          record.Delimiter,
+			"finished_at":        finished,
+		}).Error
+}
+
+func (r *TransactionRepo) GetProcessingRun(ctx context.Context, id uint64) (domain.ProcessingRun, error) {
+	var record models.ProcessingRun
+	res := r.db.WithContext(ctx).Where("id = ?", id).Limit(1).Find(&record)
+	if res.Error != nil {
+		return domain.ProcessingRun{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ProcessingRun{}, domain.ErrNotFound
+	}
+	return mappers.ToProcessingRunDomain(record), nil
+}
+
+func (r *TransactionRepo) ListProcessingRuns(
+	ctx context.Context,
+	q domain.RunQuery,
+) ([]domain.ProcessingRun, error) {
+	query := r.db.WithContext(ctx).Model(&models.ProcessingRun{})
+	if q.AccountID != "" {
+		query = query.Where("account_id = ?", q.AccountID)
+	}
+	if q.Bucket != "" {
+		query = query.Where("bucket = ?", q.Bucket)
+	}
+	if q.ObjectKey != "" {
+		query = query.Where("object_key = ?", q.ObjectKey)
+	}
+	if q.Status != "" {
+		query = query.Where("status = ?", string(q.Status))
+	}
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+
+	var records []models.ProcessingRun
+	if err := query.Order("created_at DESC, id DESC").Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.ProcessingRun, 0, len(records))
+	for _, rec := range records {
+		result = append(result, mappers.ToProcessingRunDomain(rec))
+	}
+	return result, nil
+}
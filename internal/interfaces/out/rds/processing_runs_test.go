@@ -0,0 +1,184 @@
+package rds
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func TestTransactionRepo_StartProcessingRun_CreatesRunWhenNonePending(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	run, err := repo.StartProcessingRun(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/a.csv"})
+	if err != nil {
+		t.Fatalf("StartProcessingRun returned error: %v", err)
+	}
+	if run.ID == 0 || run.Status != domain.RunStatusProcessing || run.StartedAt == nil {
+		t.Errorf("unexpected run: %+v", run)
+	}
+	if run.AccountID != "acc-1" {
+		t.Errorf("AccountID = %q, want acc-1", run.AccountID)
+	}
+}
+
+func TestTransactionRepo_StartProcessingRun_ReusesPendingRun(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	pending, err := repo.CreateProcessingRun(ctx, domain.ProcessingRun{Bucket: "bucket", ObjectKey: "a.csv"})
+	if err != nil {
+		t.Fatalf("CreateProcessingRun returned error: %v", err)
+	}
+	if pending.Status != domain.RunStatusPending || pending.AccountID != domain.DefaultAccountID {
+		t.Fatalf("unexpected pending run: %+v", pending)
+	}
+
+	started, err := repo.StartProcessingRun(ctx, domain.ObjectRef{Bucket: "bucket", Key: "a.csv"})
+	if err != nil {
+		t.Fatalf("StartProcessingRun returned error: %v", err)
+	}
+	if started.ID != pending.ID || started.Status != domain.RunStatusProcessing {
+		t.Errorf("started = %+v, want pending run %d in processing", started, pending.ID)
+	}
+
+	started.Status = domain.RunStatusFailed
+	started.Error = "boom"
+	started.TransactionsCount = 4
+	if err := repo.FinishProcessingRun(ctx, started); err != nil {
+		t.Fatalf("FinishProcessingRun returned error: %v", err)
+	}
+
+	runs, err := repo.ListProcessingRuns(ctx, domain.RunQuery{ObjectKey: "a.csv"})
+	if err != nil {
+		t.Fatalf("ListProcessingRuns returned error: %v", err)
+	}
+	if len(runs) != 1 {
+		t.Fatalf("expected 1 run, got %d", len(runs))
+	}
+	got := runs[0]
+	if got.Status != domain.RunStatusFailed || got.Error != "boom" || got.TransactionsCount != 4 || got.FinishedAt == nil {
+		t.Errorf("finished run = %+v", got)
+	}
+}
+
+func TestTransactionRepo_ListProcessingRuns_Filters(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	for _, run := range []domain.ProcessingRun{
+		{Bucket: "bucket", ObjectKey: "input/acc-1/a.csv", Status: domain.RunStatusSucceeded},
+		{Bucket: "bucket", ObjectKey: "input/acc-1/b.csv", Status: domain.RunStatusFailed},
+		{Bucket: "bucket", ObjectKey: "input/acc-2/c.csv", Status: domain.RunStatusSucceeded},
+	} {
+		if _, err := repo.CreateProcessingRun(ctx, run); err != nil {
+			t.Fatalf("CreateProcessingRun returned error: %v", err)
+		}
+	}
+
+	runs, err := repo.ListProcessingRuns(ctx, domain.RunQuery{AccountID: "acc-1", Status: domain.RunStatusSucceeded})
+	if err != nil {
+		t.Fatalf("ListProcessingRuns returned error: %v", err)
+	}
+	if len(runs) != 1 || runs[0].ObjectKey != "input/acc-1/a.csv" {
+		t.Errorf("runs = %+v", runs)
+	}
+
+	limited, err := repo.ListProcessingRuns(ctx, domain.RunQuery{Limit: 2})
+	if err != nil {
+		t.Fatalf("ListProcessingRuns returned error: %v", err)
+	}
+	if len(limited) != 2 || limited[0].ObjectKey != "input/acc-2/c.csv" {
+		t.Errorf("limited = %+v, want newest two", limited)
+	}
+}
+
+func TestTransactionRepo_GetProcessingRun(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	created, err := repo.CreateProcessingRun(ctx, domain.ProcessingRun{Bucket: "bucket", ObjectKey: "input/acc-1/a.csv"})
+	if err != nil {
+		t.Fatalf("CreateProcessingRun returned error: %v", err)
+	}
+
+	got, err := repo.GetProcessingRun(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("GetProcessingRun returned error: %v", err)
+	}
+	if got.ObjectKey != "input/acc-1/a.csv" || got.Status != domain.RunStatusPending {
+		t.Errorf("run = %+v", got)
+	}
+
+	if _, err := repo.GetProcessingRun(ctx, created.ID+100); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTransactionRepo_StartProcessingRun_RecordsVersion(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	created, err := repo.StartProcessingRun(ctx, domain.ObjectRef{Bucket: "bucket", Key: "v1.csv", VersionID: "v-1"})
+	if err != nil {
+		t.Fatalf("StartProcessingRun returned error: %v", err)
+	}
+	if created.VersionID != "v-1" {
+		t.Errorf("VersionID = %q, want v-1", created.VersionID)
+	}
+
+	pending, err := repo.CreateProcessingRun(ctx, domain.ProcessingRun{Bucket: "bucket", ObjectKey: "v2.csv"})
+	if err != nil {
+		t.Fatalf("CreateProcessingRun returned error: %v", err)
+	}
+	if _, err := repo.StartProcessingRun(ctx, domain.ObjectRef{Bucket: "bucket", Key: "v2.csv", VersionID: "v-2"}); err != nil {
+		t.Fatalf("StartProcessingRun returned error: %v", err)
+	}
+
+	got, err := repo.GetProcessingRun(ctx, pending.ID)
+	if err != nil {
+		t.Fatalf("GetProcessingRun returned error: %v", err)
+	}
+	if got.VersionID != "v-2" {
+		t.Errorf("pending run VersionID = %q, want v-2", got.VersionID)
+	}
+}
+
+func TestTransactionRepo_StartProcessingRun_ZipEntryUsesFileKey(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	entry := domain.ObjectRef{Bucket: "bucket", Key: "input/export.zip"}.WithEntry("acc-2/txns.csv")
+	run, err := repo.StartProcessingRun(ctx, entry)
+	if err != nil {
+		t.Fatalf("StartProcessingRun returned error: %v", err)
+	}
+	if run.ObjectKey != "input/export.zip#acc-2/txns.csv" || run.AccountID != "acc-2" {
+		t.Errorf("ObjectKey/AccountID = %q/%q", run.ObjectKey, run.AccountID)
+	}
+}
+
+func TestTransactionRepo_FinishProcessingRun_PersistsDialect(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	run, err := repo.StartProcessingRun(ctx, domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/a.csv"})
+	if err != nil {
+		t.Fatalf("StartProcessingRun returned error: %v", err)
+	}
+	run.Status = domain.RunStatusSucceeded
+	run.Dialect = domain.Dialect{Encoding: "windows-1252", BOM: true, Delimiter: '\t'}
+	if err := repo.FinishProcessingRun(ctx, run); err != nil {
+		t.Fatalf("FinishProcessingRun returned error: %v", err)
+	}
+
+	got, err := repo.GetProcessingRun(ctx, run.ID)
+	if err != nil {
+		t.Fatalf("GetProcessingRun returned error: %v", err)
+	}
+	if got.Dialect != run.Dialect {
+		t.Errorf("Dialect = %+v, want %+v", got.Dialect, run.Dialect)
+	}
+}
@@ -0,0 +1,58 @@
+package rds
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+	"stori-challenge/internal/interfaces/out/rds/mappers"
+	"stori-challenge/internal/interfaces/out/rds/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReplayCheckpointRepo struct {
+	db *gorm.DB
+}
+
+var _ out.ReplayCheckpointStore = (*ReplayCheckpointRepo)(nil)
+
+func NewReplayCheckpointRepo(db *gorm.DB) *ReplayCheckpointRepo {
+	return &ReplayCheckpointRepo{db: db}
+}
+
+func (r *ReplayCheckpointRepo) CompletedReplayObjects(
+	ctx context.Context,
+	replayID, bucket string,
+) (map[string]bool, error) {
+	var keys []string
+	err := r.db.WithContext(ctx).
+		Model(&models.ReplayCheckpoint{}).
+		Where("replay_id = ? AND bucket = ? AND status = ?", replayID, bucket, string(domain.RunStatusSucceeded)).
+		Pluck("object_key", &keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	done := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		done[k] = true
+	}
+	return done, nil
+}
+
+// SaveReplayCheckpoint guarda el último resultado de cada objeto: un reintento
+// sobrescribe el estado anterior.
+func (r *ReplayCheckpointRepo) SaveReplayCheckpoint(ctx context.Context, cp domain.ReplayCheckpoint) error {
+	if cp.UpdatedAt.IsZero() {
+		cp.UpdatedAt = time.Now().UTC()
+	}
+	record := mappers.ToReplayCheckpointModel(cp)
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "replay_id"}, {Name: "bucket"}, {Name: "object_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{"status", "error", "updated_at"}),
+		}).
+		Create(&record).Error
+}
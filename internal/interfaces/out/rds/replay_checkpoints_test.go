@@ -0,0 +1,60 @@
+package rds
+
+import (
+	"context"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func TestReplayCheckpointRepo_CompletedReplayObjects_OnlySucceeded(t *testing.T) {
+	repo := NewReplayCheckpointRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	for _, cp := range []domain.ReplayCheckpoint{
+		{ReplayID: "r-1", Bucket: "bucket", ObjectKey: "input/a.csv", Status: domain.RunStatusSucceeded},
+		{ReplayID: "r-1", Bucket: "bucket", ObjectKey: "input/b.csv", Status: domain.RunStatusFailed, Error: "boom"},
+		{ReplayID: "r-1", Bucket: "other", ObjectKey: "input/c.csv", Status: domain.RunStatusSucceeded},
+		{ReplayID: "r-2", Bucket: "bucket", ObjectKey: "input/d.csv", Status: domain.RunStatusSucceeded},
+	} {
+		if err := repo.SaveReplayCheckpoint(ctx, cp); err != nil {
+			t.Fatalf("SaveReplayCheckpoint returned error: %v", err)
+		}
+	}
+
+	done, err := repo.CompletedReplayObjects(ctx, "r-1", "bucket")
+	if err != nil {
+		t.Fatalf("CompletedReplayObjects returned error: %v", err)
+	}
+	if len(done) != 1 || !done["input/a.csv"] {
+		t.Errorf("done = %v, want only input/a.csv", done)
+	}
+}
+
+func TestReplayCheckpointRepo_SaveReplayCheckpoint_OverwritesPreviousAttempt(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewReplayCheckpointRepo(db)
+	ctx := context.Background()
+
+	cp := domain.ReplayCheckpoint{ReplayID: "r-3", Bucket: "bucket", ObjectKey: "input/a.csv", Status: domain.RunStatusFailed, Error: "boom"}
+	if err := repo.SaveReplayCheckpoint(ctx, cp); err != nil {
+		t.Fatalf("SaveReplayCheckpoint returned error: %v", err)
+	}
+	cp.Status = domain.RunStatusSucceeded
+	cp.Error = ""
+	if err := repo.SaveReplayCheckpoint(ctx, cp); err != nil {
+		t.Fatalf("SaveReplayCheckpoint (retry) returned error: %v", err)
+	}
+
+	var records []models.ReplayCheckpoint
+	if err := db.Where("replay_id = ?", "r-3").Find(&records).Error; err != nil {
+		t.Fatalf("failed to load checkpoints: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected 1 checkpoint, got %d", len(records))
+	}
+	if records[0].Status != string(domain.RunStatusSucceeded) || records[0].Error != "" {
+		t.Errorf("unexpected checkpoint: %+v", records[0])
+	}
+}
@@ -0,0 +1,83 @@
+package rds
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+	"stori-challenge/internal/interfaces/out/rds/mappers"
+	"stori-challenge/internal/interfaces/out/rds/models"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type RewardsLedgerRepo struct {
+	db *gorm.DB
+}
+
+var _ out.RewardsLedger = (*RewardsLedgerRepo)(nil)
+
+func NewRewardsLedgerRepo(db *gorm.DB) *RewardsLedgerRepo {
+	return &RewardsLedgerRepo{db: db}
+}
+
+type rewardsTotals struct {
+	Cycle    string
+	Points   decimal.Decimal
+	Cashback decimal.Decimal
+}
+
+func (r *RewardsLedgerRepo) SaveRewardEntries(
+	ctx context.Context,
+	bucket, key string,
+	entries []domain.RewardEntry,
+) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	records := mappers.ToRewardEntryModels(bucket, key, entries)
+	return r.db.WithContext(ctx).Create(&records).Error
+}
+
+func (r *RewardsLedgerRepo) GetCycleBalances(
+	ctx context.Context,
+	accountID string,
+	cycles []string,
+) (map[string]domain.RewardsBalance, error) {
+	result := map[string]domain.RewardsBalance{}
+	if len(cycles) == 0 {
+		return result, nil
+	}
+
+	var rows []rewardsTotals
+	err := r.db.WithContext(ctx).
+		Model(&models.RewardEntry{}).
+		Select("cycle, COALESCE(SUM(points), 0) AS points, COALESCE(SUM(cashback), 0) AS cashback").
+		Where("account_id = ? AND cycle IN ?", accountID, cycles).
+		Group("cycle").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.Cycle] = domain.RewardsBalance{Points: row.Points, Cashback: row.Cashback}
+	}
+	return result, nil
+}
+
+func (r *RewardsLedgerRepo) GetLifetimeBalance(
+	ctx context.Context,
+	accountID string,
+) (domain.RewardsBalance, error) {
+	var row rewardsTotals
+	err := r.db.WithContext(ctx).
+		Model(&models.RewardEntry{}).
+		Select("COALESCE(SUM(points), 0) AS points, COALESCE(SUM(cashback), 0) AS cashback").
+		Where("account_id = ?", accountID).
+		Scan(&row).Error
+	if err != nil {
+		return domain.RewardsBalance{}, err
+	}
+	return domain.RewardsBalance{Points: row.Points, Cashback: row.Cashback}, nil
+}
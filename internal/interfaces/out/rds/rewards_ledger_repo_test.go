@@ -0,0 +1,73 @@
+package rds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func TestRewardsLedgerRepo_SaveRewardEntries_EmptySlice_NoInsert(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRewardsLedgerRepo(db)
+
+	if err := repo.SaveRewardEntries(context.Background(), "bucket", "key", nil); err != nil {
+		t.Fatalf("SaveRewardEntries with nil slice returned error: %v", err)
+	}
+
+	var count int64
+	if err := db.Model(&models.RewardEntry{}).Count(&count).Error; err != nil {
+		t.Fatalf("failed to count reward entries: %v", err)
+	}
+	if count != 0 {
+		t.Fatalf("expected 0 reward entries, got %d", count)
+	}
+}
+
+func TestRewardsLedgerRepo_Balances(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewRewardsLedgerRepo(db)
+	ctx := context.Background()
+
+	jul := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	aug := time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC)
+
+	entries := []domain.RewardEntry{
+		{AccountID: "acc-1", Cycle: "2021-07", Date: jul, Amount: dec("100"), Points: dec("100"), Cashback: dec("1")},
+		{AccountID: "acc-1", Cycle: "2021-07", Date: jul, Amount: dec("50"), Points: dec("50"), Cashback: dec("0.5")},
+		{AccountID: "acc-1", Cycle: "2021-08", Date: aug, Amount: dec("10"), Points: dec("10"), Cashback: dec("0.1")},
+		{AccountID: "acc-2", Cycle: "2021-07", Date: jul, Amount: dec("999"), Points: dec("999"), Cashback: dec("9.99")},
+	}
+	if err := repo.SaveRewardEntries(ctx, "bucket", "input/acc-1/txns.csv", entries); err != nil {
+		t.Fatalf("SaveRewardEntries returned error: %v", err)
+	}
+
+	cycles, err := repo.GetCycleBalances(ctx, "acc-1", []string{"2021-07", "2021-09"})
+	if err != nil {
+		t.Fatalf("GetCycleBalances returned error: %v", err)
+	}
+	if len(cycles) != 1 {
+		t.Fatalf("expected 1 cycle balance, got %d (%v)", len(cycles), cycles)
+	}
+	if got := cycles["2021-07"]; !got.Points.Equal(dec("150")) || !got.Cashback.Equal(dec("1.5")) {
+		t.Errorf("cycle 2021-07 = %v/%v, want 150/1.5", got.Points, got.Cashback)
+	}
+
+	lifetime, err := repo.GetLifetimeBalance(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("GetLifetimeBalance returned error: %v", err)
+	}
+	if !lifetime.Points.Equal(dec("160")) || !lifetime.Cashback.Equal(dec("1.6")) {
+		t.Errorf("lifetime = %v/%v, want 160/1.6", lifetime.Points, lifetime.Cashback)
+	}
+
+	empty, err := repo.GetLifetimeBalance(ctx, "unknown")
+	if err != nil {
+		t.Fatalf("GetLifetimeBalance returned error: %v", err)
+	}
+	if !empty.Points.IsZero() || !empty.Cashback.IsZero() {
+		t.Errorf("lifetime for unknown account = %v/%v, want 0/0", empty.Points, empty.Cashback)
+	}
+}
@@ -0,0 +1,87 @@
+package rds
+
+import (
+	"context"
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+	"stori-challenge/internal/interfaces/out/rds/mappers"
+	"stori-challenge/internal/interfaces/out/rds/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// staleClaimAfter es el tiempo tras el cual un envío que quedó en "sending"
+// (por ejemplo, la Lambda murió a mitad) se puede volver a reclamar.
+const staleClaimAfter = 15 * time.Minute
+
+type StatementLedgerRepo struct {
+	db *gorm.DB
+}
+
+var _ out.StatementLedger = (*StatementLedgerRepo)(nil)
+
+func NewStatementLedgerRepo(db *gorm.DB) *StatementLedgerRepo {
+	return &StatementLedgerRepo{db: db}
+}
+
+// ClaimStatement se apoya en el índice único (account_id, period): si dos
+// ejecuciones compiten, solo una inserta o actualiza la fila.
+func (r *StatementLedgerRepo) ClaimStatement(
+	ctx context.Context,
+	accountID, period string,
+) (domain.Statement, bool, error) {
+	now := time.Now().UTC()
+	db := r.db.WithContext(ctx)
+
+	record := models.Statement{
+		AccountID: accountID,
+		Period:    period,
+		Status:    string(domain.StatementStatusSending),
+		ClaimedAt: now,
+	}
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&record)
+	if res.Error != nil {
+		return domain.Statement{}, false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return mappers.ToStatementDomain(record), true, nil
+	}
+
+	res = db.Model(&models.Statement{}).
+		Where("account_id = ? AND period = ?", accountID, period).
+		Where("status = ? OR (status = ? AND claimed_at < ?)",
+			string(domain.StatementStatusFailed),
+			string(domain.StatementStatusSending),
+			now.Add(-staleClaimAfter),
+		).
+		Updates(map[string]any{
+			"status":     string(domain.StatementStatusSending),
+			"error":      "",
+			"claimed_at": now,
+		})
+	if res.Error != nil {
+		return domain.Statement{}, false, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.Statement{}, false, nil
+	}
+
+	var existing models.Statement
+	if err := db.Where("account_id = ? AND period = ?", accountID, period).Limit(1).Find(&existing).Error; err != nil {
+		return domain.Statement{}, false, err
+	}
+	return mappers.ToStatementDomain(existing), true, nil
+}
+
+func (r *StatementLedgerRepo) FinishStatement(ctx context.Context, st domain.Statement) error {
+	return r.db.WithContext(ctx).
+		Model(&models.Statement{ID: st.ID}).
+		Updates(map[string]any{
+			"status":             string(st.Status),
+			"error":              st.Error,
+			"transactions_count": st.TransactionsCount,
+			"sent_at":            st.SentAt,
+		}).Error
+}
@@ -0,0 +1,74 @@
+package rds
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/models"
+)
+
+func TestStatementLedgerRepo_ClaimStatement_OnlyOncePerPeriod(t *testing.T) {
+	repo := NewStatementLedgerRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	st, claimed, err := repo.ClaimStatement(ctx, "acc-1", "2024-03")
+	if err != nil || !claimed {
+		t.Fatalf("first claim: claimed=%v err=%v", claimed, err)
+	}
+	if st.ID == 0 || st.Status != domain.StatementStatusSending {
+		t.Errorf("unexpected statement: %+v", st)
+	}
+
+	if _, claimed, err := repo.ClaimStatement(ctx, "acc-1", "2024-03"); err != nil || claimed {
+		t.Errorf("second claim while sending: claimed=%v err=%v", claimed, err)
+	}
+
+	sent := time.Now().UTC()
+	st.Status = domain.StatementStatusSent
+	st.SentAt = &sent
+	st.TransactionsCount = 3
+	if err := repo.FinishStatement(ctx, st); err != nil {
+		t.Fatalf("FinishStatement returned error: %v", err)
+	}
+	if _, claimed, err := repo.ClaimStatement(ctx, "acc-1", "2024-03"); err != nil || claimed {
+		t.Errorf("claim after sent: claimed=%v err=%v", claimed, err)
+	}
+
+	if _, claimed, err := repo.ClaimStatement(ctx, "acc-1", "2024-04"); err != nil || !claimed {
+		t.Errorf("claim for another period: claimed=%v err=%v", claimed, err)
+	}
+}
+
+func TestStatementLedgerRepo_ClaimStatement_RetriesFailedAndStale(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewStatementLedgerRepo(db)
+	ctx := context.Background()
+
+	failed, _, err := repo.ClaimStatement(ctx, "acc-2", "2024-03")
+	if err != nil {
+		t.Fatalf("ClaimStatement returned error: %v", err)
+	}
+	failed.Status = domain.StatementStatusFailed
+	failed.Error = "boom"
+	if err := repo.FinishStatement(ctx, failed); err != nil {
+		t.Fatalf("FinishStatement returned error: %v", err)
+	}
+
+	retried, claimed, err := repo.ClaimStatement(ctx, "acc-2", "2024-03")
+	if err != nil || !claimed {
+		t.Fatalf("claim after failure: claimed=%v err=%v", claimed, err)
+	}
+	if retried.ID != failed.ID || retried.Status != domain.StatementStatusSending || retried.Error != "" {
+		t.Errorf("unexpected retried statement: %+v", retried)
+	}
+
+	stale := time.Now().UTC().Add(-time.Hour)
+	if err := db.Model(&models.Statement{}).Where("id = ?", retried.ID).Update("claimed_at", stale).Error; err != nil {
+		t.Fatalf("failed to age claim: %v", err)
+	}
+	if _, claimed, err := repo.ClaimStatement(ctx, "acc-2", "2024-03"); err != nil || !claimed {
+		t.Errorf("claim of stale sending: claimed=%v err=%v", claimed, err)
+	}
+}
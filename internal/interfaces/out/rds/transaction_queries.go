@@ -0,0 +1,192 @@
+package rds
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/mappers"
+	"stori-challenge/internal/interfaces/out/rds/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// ListTransactions pagina por (date, id). El cursor codifica la última fila
+// devuelta, así que las inserciones concurrentes no desplazan las páginas.
+func (r *TransactionRepo) ListTransactions(
+	ctx context.Context,
+	q domain.TransactionQuery,
+) (domain.TransactionPage, error) {
+	query := r.db.WithContext(ctx).Model(&models.Transaction{})
+	if q.AccountID != "" {
+		query = query.Where("account_id = ?", q.AccountID)
+	}
+	if !q.From.IsZero() {
+		query = query.Where("date >= ?", q.From)
+	}
+	if !q.To.IsZero() {
+		query = query.Where("date < ?", q.To)
+	}
+	if q.Cursor != "" {
+		date, id, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return domain.TransactionPage{}, err
+		}
+		query = query.Where("(date > ? OR (date = ? AND id > ?))", date, date, id)
+	}
+
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit + 1)
+	}
+
+	var records []models.Transaction
+	if err := query.Order("date, id").Find(&records).Error; err != nil {
+		return domain.TransactionPage{}, err
+	}
+
+	var page domain.TransactionPage
+	if q.Limit > 0 && len(records) > q.Limit {
+		records = records[:q.Limit]
+		last := records[len(records)-1]
+		page.NextCursor = encodeCursor(last.Date, uint64(last.ID))
+	}
+	page.Items = make([]domain.TransactionRecord, 0, len(records))
+	for _, rec := range records {
+		page.Items = append(page.Items, mappers.ToTransactionRecordDomain(rec))
+	}
+	return page, nil
+}
+
+func (r *TransactionRepo) GetSummaryByID(ctx context.Context, id uint64) (domain.StoredSummary, error) {
+	return r.findSummary(ctx, "id = ?", id)
+}
+
+func (r *TransactionRepo) GetLatestSummaryByObject(
+	ctx context.Context,
+	bucket, key string,
+) (domain.StoredSummary, error) {
+	return r.findSummary(ctx, "bucket = ? AND object_key = ?", bucket, key)
+}
+
+func (r *TransactionRepo) GetLatestSummaryByAccount(
+	ctx context.Context,
+	accountID string,
+) (domain.StoredSummary, error) {
+	return r.findSummary(ctx, "account_id = ?", accountID)
+}
+
+func (r *TransactionRepo) findSummary(
+	ctx context.Context,
+	where string,
+	args ...any,
+) (domain.StoredSummary, error) {
+	var record models.AccountSummary
+	res := r.db.WithContext(ctx).
+		Preload("Months", func(db *gorm.DB) *gorm.DB { return db.Order("month") }).
+		Preload("Months.Comparisons").
+		Where(where, args...).
+		Order("created_at DESC, id DESC").
+		Limit(1).
+		Find(&record)
+	if res.Error != nil {
+		return domain.StoredSummary{}, res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.StoredSummary{}, domain.ErrNotFound
+	}
+	return mappers.ToStoredSummaryDomain(record), nil
+}
+
+type monthAggregate struct {
+	Month             string
+	TransactionsCount int
+	DebitCount        int
+	CreditCount       int
+	TotalDebit        decimal.Decimal
+	TotalCredit       decimal.Decimal
+}
+
+// AggregateByMonth agrupa en la base las transacciones guardadas de la cuenta.
+// Los promedios se calculan aquí a partir de sumas y conteos para no depender
+// de la precisión de AVG en cada motor.
+func (r *TransactionRepo) AggregateByMonth(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+) ([]domain.MonthlySummary, error) {
+	month := r.monthExpr()
+	query := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Select(month+` AS month,
+			COUNT(*) AS transactions_count,
+			SUM(CASE WHEN amount < 0 THEN 1 ELSE 0 END) AS debit_count,
+			SUM(CASE WHEN amount < 0 THEN 0 ELSE 1 END) AS credit_count,
+			COALESCE(SUM(CASE WHEN amount < 0 THEN amount ELSE 0 END), 0) AS total_debit,
+			COALESCE(SUM(CASE WHEN amount < 0 THEN 0 ELSE amount END), 0) AS total_credit`).
+		Where("account_id = ?", accountID)
+	if !from.IsZero() {
+		query = query.Where("date >= ?", from)
+	}
+	if !to.IsZero() {
+		query = query.Where("date < ?", to)
+	}
+
+	var rows []monthAggregate
+	if err := query.Group(month).Order(month).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := make([]domain.MonthlySummary, 0, len(rows))
+	for _, row := range rows {
+		ms := domain.MonthlySummary{
+			MonthName:         row.Month,
+			TransactionsCount: row.TransactionsCount,
+			TotalDebitAmount:  row.TotalDebit,
+			TotalCreditAmount: row.TotalCredit,
+		}
+		if row.DebitCount > 0 {
+			ms.AverageDebitAmount = row.TotalDebit.Div(decimal.NewFromInt(int64(row.DebitCount)))
+		}
+		if row.CreditCount > 0 {
+			ms.AverageCreditAmount = row.TotalCredit.Div(decimal.NewFromInt(int64(row.CreditCount)))
+		}
+		result = append(result, ms)
+	}
+	return result, nil
+}
+
+func (r *TransactionRepo) monthExpr() string {
+	if r.db.Dialector.Name() == "sqlite" {
+		return "strftime('%Y-%m', date)"
+	}
+	return "to_char(date AT TIME ZONE 'UTC', 'YYYY-MM')"
+}
+
+func encodeCursor(date time.Time, id uint64) string {
+	raw := date.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(id, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(cursor string) (time.Time, uint64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, 0, domain.ErrInvalidCursor
+	}
+	datePart, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, 0, domain.ErrInvalidCursor
+	}
+	date, err := time.Parse(time.RFC3339Nano, datePart)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("%w: fecha", domain.ErrInvalidCursor)
+	}
+	id, err := strconv.ParseUint(idPart, 10, 64)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("%w: id", domain.ErrInvalidCursor)
+	}
+	return date, id, nil
+}
@@ -0,0 +1,231 @@
+package rds
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func TestTransactionRepo_ListTransactions_PaginatesWithCursor(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	ctx := context.Background()
+
+	day := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-1/a.csv", []domain.Transaction{
+		{Date: day.AddDate(0, 0, 2), Amount: dec("3")},
+		{Date: day, Amount: dec("1")},
+		{Date: day, Amount: dec("2")},
+		{Date: day.AddDate(0, 0, 5), Amount: dec("-4")},
+		{Date: day.AddDate(0, 0, 9), Amount: dec("5")},
+	}); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-2/b.csv", []domain.Transaction{
+		{Date: day, Amount: dec("99")},
+	}); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+
+	var amounts []string
+	q := domain.TransactionQuery{AccountID: "acc-1", Limit: 2}
+	pages := 0
+	for {
+		page, err := repo.ListTransactions(ctx, q)
+		if err != nil {
+			t.Fatalf("ListTransactions returned error: %v", err)
+		}
+		pages++
+		for _, item := range page.Items {
+			if item.AccountID != "acc-1" || item.ObjectKey != "input/acc-1/a.csv" || item.ID == 0 {
+				t.Errorf("unexpected record: %+v", item)
+			}
+			amounts = append(amounts, item.Amount.String())
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		q.Cursor = page.NextCursor
+	}
+
+	want := []string{"1", "2", "3", "-4", "5"}
+	if len(amounts) != len(want) {
+		t.Fatalf("amounts = %v, want %v", amounts, want)
+	}
+	for i := range want {
+		if amounts[i] != want[i] {
+			t.Fatalf("amounts = %v, want %v", amounts, want)
+		}
+	}
+	if pages != 3 {
+		t.Errorf("pages = %d, want 3", pages)
+	}
+}
+
+func TestTransactionRepo_ListTransactions_FiltersRange(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	ctx := context.Background()
+
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-1/a.csv", []domain.Transaction{
+		{Date: time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC), Amount: dec("1")},
+		{Date: time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC), Amount: dec("2")},
+		{Date: time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC), Amount: dec("3")},
+	}); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+
+	page, err := repo.ListTransactions(ctx, domain.TransactionQuery{
+		AccountID: "acc-1",
+		From:      time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC),
+		Limit:     10,
+	})
+	if err != nil {
+		t.Fatalf("ListTransactions returned error: %v", err)
+	}
+	if len(page.Items) != 1 || !page.Items[0].Amount.Equal(dec("2")) || page.NextCursor != "" {
+		t.Errorf("unexpected page: %+v", page)
+	}
+}
+
+func TestTransactionRepo_ListTransactions_InvalidCursor(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+
+	for _, cursor := range []string{"%%%", encodeRaw("no-separator"), encodeRaw("2021-07-01T00:00:00Z|abc")} {
+		_, err := repo.ListTransactions(context.Background(), domain.TransactionQuery{Cursor: cursor, Limit: 1})
+		if !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("cursor %q: expected ErrInvalidCursor, got %v", cursor, err)
+		}
+	}
+}
+
+func TestCursor_RoundTrip(t *testing.T) {
+	date := time.Date(2021, 7, 15, 10, 30, 0, 123, time.UTC)
+
+	gotDate, gotID, err := decodeCursor(encodeCursor(date, 42))
+	if err != nil {
+		t.Fatalf("decodeCursor returned error: %v", err)
+	}
+	if !gotDate.Equal(date) || gotID != 42 {
+		t.Errorf("decoded (%v, %d), want (%v, 42)", gotDate, gotID, date)
+	}
+}
+
+func TestTransactionRepo_GetLatestSummary(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	ctx := context.Background()
+
+	if _, err := repo.GetLatestSummaryByAccount(ctx, "acc-1"); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound on empty table, got %v", err)
+	}
+
+	first := domain.AccountSummary{
+		TotalBalance: dec("1"),
+		ByMonth:      []domain.MonthlySummary{{MonthName: "2021-07", TransactionsCount: 1}},
+	}
+	second := domain.AccountSummary{
+		TotalBalance: dec("2"),
+		ByMonth: []domain.MonthlySummary{
+			{MonthName: "2021-08", TransactionsCount: 3},
+			{
+				MonthName:         "2021-07",
+				TransactionsCount: 2,
+				VsPreviousMonth:   &domain.MonthlyDelta{BaselineMonth: "2021-06", TransactionsCount: 2},
+			},
+		},
+	}
+	if err := repo.SaveSummary(ctx, "bucket", "input/acc-1/a.csv", first); err != nil {
+		t.Fatalf("SaveSummary returned error: %v", err)
+	}
+	if err := repo.SaveSummary(ctx, "bucket", "input/acc-1/b.csv", second); err != nil {
+		t.Fatalf("SaveSummary returned error: %v", err)
+	}
+
+	byAccount, err := repo.GetLatestSummaryByAccount(ctx, "acc-1")
+	if err != nil {
+		t.Fatalf("GetLatestSummaryByAccount returned error: %v", err)
+	}
+	if byAccount.ObjectKey != "input/acc-1/b.csv" || !byAccount.Summary.TotalBalance.Equal(dec("2")) {
+		t.Errorf("by account = %+v, want newest summary", byAccount)
+	}
+	if len(byAccount.Summary.ByMonth) != 2 || byAccount.Summary.ByMonth[0].MonthName != "2021-07" {
+		t.Fatalf("ByMonth = %+v, want two months ordered", byAccount.Summary.ByMonth)
+	}
+	if d := byAccount.Summary.ByMonth[0].VsPreviousMonth; d == nil || d.BaselineMonth != "2021-06" {
+		t.Errorf("VsPreviousMonth = %+v, want preloaded comparison", d)
+	}
+
+	byID, err := repo.GetSummaryByID(ctx, byAccount.ID)
+	if err != nil {
+		t.Fatalf("GetSummaryByID returned error: %v", err)
+	}
+	if byID.ID != byAccount.ID || len(byID.Summary.ByMonth) != 2 {
+		t.Errorf("by id = %+v", byID)
+	}
+	if _, err := repo.GetSummaryByID(ctx, byAccount.ID+100); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for unknown id, got %v", err)
+	}
+
+	byObject, err := repo.GetLatestSummaryByObject(ctx, "bucket", "input/acc-1/a.csv")
+	if err != nil {
+		t.Fatalf("GetLatestSummaryByObject returned error: %v", err)
+	}
+	if !byObject.Summary.TotalBalance.Equal(dec("1")) || byObject.Summary.AccountID != "acc-1" {
+		t.Errorf("by object = %+v", byObject)
+	}
+}
+
+func TestTransactionRepo_AggregateByMonth(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	ctx := context.Background()
+
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-1/a.csv", []domain.Transaction{
+		{Date: time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC), Amount: dec("60.5")},
+		{Date: time.Date(2021, 7, 13, 0, 0, 0, 0, time.UTC), Amount: dec("-10.3")},
+		{Date: time.Date(2021, 7, 20, 0, 0, 0, 0, time.UTC), Amount: dec("-20.46")},
+		{Date: time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC), Amount: dec("10")},
+		{Date: time.Date(2021, 9, 2, 0, 0, 0, 0, time.UTC), Amount: dec("99")},
+	}); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-2/b.csv", []domain.Transaction{
+		{Date: time.Date(2021, 7, 2, 0, 0, 0, 0, time.UTC), Amount: dec("1000")},
+	}); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+
+	got, err := repo.AggregateByMonth(ctx, "acc-1",
+		time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("AggregateByMonth returned error: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 months, got %d (%+v)", len(got), got)
+	}
+	jul, aug := got[0], got[1]
+	if jul.MonthName != "2021-07" || jul.TransactionsCount != 3 {
+		t.Errorf("july = %+v", jul)
+	}
+	if !jul.TotalDebitAmount.Equal(dec("-30.76")) || !jul.AverageDebitAmount.Equal(dec("-15.38")) {
+		t.Errorf("july debits = %v / %v", jul.TotalDebitAmount, jul.AverageDebitAmount)
+	}
+	if !jul.TotalCreditAmount.Equal(dec("60.5")) || !jul.AverageCreditAmount.Equal(dec("60.5")) {
+		t.Errorf("july credits = %v / %v", jul.TotalCreditAmount, jul.AverageCreditAmount)
+	}
+	if aug.MonthName != "2021-08" || aug.TransactionsCount != 1 || !aug.AverageDebitAmount.IsZero() {
+		t.Errorf("august = %+v", aug)
+	}
+}
+
+func encodeRaw(s string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
@@ -5,6 +5,8 @@ import (
 	"stori-challenge/internal/core/domain"
 	"stori-challenge/internal/core/ports/out"
 	"stori-challenge/internal/interfaces/out/rds/mappers"
+	"stori-challenge/internal/interfaces/out/rds/models"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -19,6 +21,38 @@ func NewTransactionRepo(db *gorm.DB) *TransactionRepo {
 	return &TransactionRepo{db: db}
 }
 
+// DeleteObjectData borra en una sola transacción las filas del archivo. Los
+// hijos de los resúmenes se borran a mano: SQLite no aplica ON DELETE CASCADE
+// sin PRAGMA foreign_keys.
+func (r *TransactionRepo) DeleteObjectData(ctx context.Context, bucket, key string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		summaries := tx.Model(&models.AccountSummary{}).
+			Select("id").
+			Where("bucket = ? AND object_key = ?", bucket, key)
+		months := tx.Model(&models.MonthlySummary{}).
+			Select("id").
+			Where("account_summary_id IN (?)", summaries)
+
+		steps := []struct {
+			model any
+			query string
+			args  []any
+		}{
+			{&models.MonthlyComparison{}, "monthly_summary_id IN (?)", []any{months}},
+			{&models.MonthlySummary{}, "account_summary_id IN (?)", []any{summaries}},
+			{&models.AccountSummary{}, "bucket = ? AND object_key = ?", []any{bucket, key}},
+			{&models.RewardEntry{}, "bucket = ? AND object_key = ?", []any{bucket, key}},
+			{&models.Transaction{}, "bucket = ? AND object_key = ?", []any{bucket, key}},
+		}
+		for _, step := range steps {
+			if err := tx.Where(step.query, step.args...).Delete(step.model).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 func (r *TransactionRepo) SaveTransactions(
 	ctx context.Context,
 	bucket, key string,
@@ -36,9 +70,70 @@ func (r *TransactionRepo) SaveSummary(
 	bucket, key string,
 	summary domain.AccountSummary,
 ) error {
-	record, err := mappers.ToAccountSummaryModel(bucket, key, summary)
+	record := mappers.ToAccountSummaryModel(bucket, key, summary)
+	return r.db.WithContext(ctx).Create(&record).Error
+}
+
+func (r *TransactionRepo) ListTransactionsByAccount(
+	ctx context.Context,
+	accountID string,
+	from, to time.Time,
+) ([]domain.Transaction, error) {
+	var records []models.Transaction
+	err := r.db.WithContext(ctx).
+		Where("account_id = ? AND date >= ? AND date < ?", accountID, from, to).
+		Order("date, id").
+		Find(&records).Error
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return r.db.WithContext(ctx).Create(&record).Error
+	return mappers.ToTransactionDomains(records), nil
+}
+
+// ListAccountsWithTransactions devuelve las cuentas con movimientos en [from, to).
+func (r *TransactionRepo) ListAccountsWithTransactions(
+	ctx context.Context,
+	from, to time.Time,
+) ([]string, error) {
+	var accounts []string
+	err := r.db.WithContext(ctx).
+		Model(&models.Transaction{}).
+		Distinct("account_id").
+		Where("account_id <> '' AND date >= ? AND date < ?", from, to).
+		Order("account_id").
+		Pluck("account_id", &accounts).Error
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *TransactionRepo) FindLatestMonthlySummaries(
+	ctx context.Context,
+	accountID string,
+	months []string,
+) (map[string]domain.MonthlySummary, error) {
+	result := map[string]domain.MonthlySummary{}
+	if len(months) == 0 {
+		return result, nil
+	}
+
+	var records []models.MonthlySummary
+	err := r.db.WithContext(ctx).
+		Table("transactions.monthly_summaries AS ms").
+		Select("ms.*").
+		Joins("JOIN transactions.account_summaries AS s ON s.id = ms.account_summary_id").
+		Where("s.account_id = ? AND ms.month IN ?", accountID, months).
+		Order("s.created_at DESC, s.id DESC").
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range records {
+		if _, seen := result[rec.Month]; !seen {
+			result[rec.Month] = mappers.ToMonthlySummaryDomain(rec)
+		}
+	}
+	return result, nil
 }
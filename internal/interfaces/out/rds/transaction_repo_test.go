@@ -2,11 +2,12 @@ package rds
 
 import (
 	"context"
-	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/interfaces/out/rds/mappers"
 	"stori-challenge/internal/interfaces/out/rds/models"
 
 	"github.com/glebarez/sqlite"
@@ -33,10 +34,16 @@ func setupTestDB(t *testing.T) *gorm.DB {
 	if err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS transactions.transactions (
 			id          INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id  TEXT,
 			bucket      TEXT,
 			object_key  TEXT,
 			date        DATETIME,
 			amount      NUMERIC,
+			category    TEXT,
+			reference   TEXT,
+			description TEXT,
+			memo        TEXT,
+			currency    TEXT,
 			created_at  DATETIME
 		);
 	`).Error; err != nil {
@@ -45,15 +52,138 @@ func setupTestDB(t *testing.T) *gorm.DB {
 
 	if err := db.Exec(`
 		CREATE TABLE IF NOT EXISTS transactions.account_summaries (
+			id              INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id      TEXT,
+			bucket          TEXT,
+			object_key      TEXT,
+			total_balance   NUMERIC,
+			opening_balance NUMERIC,
+			closing_balance NUMERIC,
+			created_at      DATETIME
+		);
+	`).Error; err != nil {
+		t.Fatalf("failed to create table transactions.account_summaries: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transactions.monthly_summaries (
+			id                    INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_summary_id    INTEGER NOT NULL REFERENCES account_summaries (id) ON DELETE CASCADE,
+			month                 TEXT NOT NULL,
+			transactions_count    INTEGER NOT NULL,
+			average_debit_amount  NUMERIC NOT NULL,
+			average_credit_amount NUMERIC NOT NULL,
+			total_debit_amount    NUMERIC NOT NULL,
+			total_credit_amount   NUMERIC NOT NULL,
+			UNIQUE (account_summary_id, month)
+		);
+	`).Error; err != nil {
+		t.Fatalf("failed to create table transactions.monthly_summaries: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transactions.monthly_comparisons (
+			id                       INTEGER PRIMARY KEY AUTOINCREMENT,
+			monthly_summary_id       INTEGER NOT NULL REFERENCES monthly_summaries (id) ON DELETE CASCADE,
+			baseline                 TEXT NOT NULL,
+			baseline_month           TEXT NOT NULL,
+			transactions_count_delta INTEGER NOT NULL,
+			average_debit_delta      NUMERIC NOT NULL,
+			average_credit_delta     NUMERIC NOT NULL,
+			total_debit_delta        NUMERIC NOT NULL,
+			total_credit_delta       NUMERIC NOT NULL,
+			UNIQUE (monthly_summary_id, baseline)
+		);
+	`).Error; err != nil {
+		t.Fatalf("failed to create table transactions.monthly_comparisons: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transactions.reward_entries (
+			id               INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id       TEXT,
+			cycle            TEXT,
+			bucket           TEXT,
+			object_key       TEXT,
+			category         TEXT,
+			transaction_date DATETIME,
+			amount           NUMERIC,
+			points           NUMERIC,
+			cashback         NUMERIC,
+			created_at       DATETIME
+		);
+	`).Error; err != nil {
+		t.Fatalf("failed to create table transactions.reward_entries: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transactions.processing_runs (
+			id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id         TEXT,
+			bucket             TEXT,
+			object_key         TEXT,
+			version_id         TEXT,
+			status             TEXT NOT NULL,
+			error              TEXT,
+			transactions_count INTEGER NOT NULL DEFAULT 0,
+			encoding           TEXT,
+			has_bom            BOOLEAN NOT NULL DEFAULT 0,
+			delimiter          TEXT,
+			created_at         DATETIME,
+			started_at         DATETIME,
+			finished_at        DATETIME
+		);
+	`).Error; err != nil {
+		t.Fatalf("failed to create table transactions.processing_runs: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transactions.replay_checkpoints (
+			id         INTEGER PRIMARY KEY AUTOINCREMENT,
+			replay_id  TEXT NOT NULL,
+			bucket     TEXT NOT NULL,
+			object_key TEXT NOT NULL,
+			status     TEXT NOT NULL,
+			error      TEXT,
+			created_at DATETIME,
+			updated_at DATETIME,
+			UNIQUE (replay_id, bucket, object_key)
+		);
+	`).Error; err != nil {
+		t.Fatalf("failed to create table transactions.replay_checkpoints: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transactions.dead_letters (
 			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			source        TEXT NOT NULL,
+			message_id    TEXT,
 			bucket        TEXT,
 			object_key    TEXT,
-			total_balance NUMERIC,
-			raw_summary   TEXT,
+			payload       TEXT,
+			error         TEXT NOT NULL,
+			receive_count INTEGER NOT NULL DEFAULT 0,
 			created_at    DATETIME
 		);
 	`).Error; err != nil {
-		t.Fatalf("failed to create table transactions.account_summaries: %v", err)
+		t.Fatalf("failed to create table transactions.dead_letters: %v", err)
+	}
+
+	if err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS transactions.statements (
+			id                 INTEGER PRIMARY KEY AUTOINCREMENT,
+			account_id         TEXT NOT NULL,
+			period             TEXT NOT NULL,
+			status             TEXT NOT NULL,
+			error              TEXT,
+			transactions_count INTEGER NOT NULL DEFAULT 0,
+			created_at         DATETIME,
+			claimed_at         DATETIME NOT NULL,
+			sent_at            DATETIME,
+			UNIQUE (account_id, period)
+		);
+	`).Error; err != nil {
+		t.Fatalf("failed to create table transactions.statements: %v", err)
 	}
 
 	return db
@@ -91,7 +221,7 @@ func TestTransactionRepo_SaveTransactions_InsertsRecords(t *testing.T) {
 
 	txs := []domain.Transaction{
 		{Date: now, Amount: dec("100.50")},
-		{Date: now.AddDate(0, 0, 1), Amount: dec("-40.25")},
+		{Date: now.AddDate(0, 0, 1), Amount: dec("-40.25"), Reference: "FIT-2", Description: "Market", Memo: "weekly"},
 	}
 
 	if err := repo.SaveTransactions(ctx, bucket, key, txs); err != nil {
@@ -120,6 +250,35 @@ func TestTransactionRepo_SaveTransactions_InsertsRecords(t *testing.T) {
 		if !rec.Amount.Equal(txs[i].Amount) {
 			t.Errorf("record %d Amount = %v, want %v", i, rec.Amount, txs[i].Amount)
 		}
+		if rec.Reference != txs[i].Reference || rec.Description != txs[i].Description || rec.Memo != txs[i].Memo {
+			t.Errorf("record %d statement fields = %q/%q/%q", i, rec.Reference, rec.Description, rec.Memo)
+		}
+	}
+}
+
+func TestTransactionRepo_SaveTransactions_KeepsLongStatementText(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+
+	description := strings.Repeat("Pago de servicios ", 25)
+	memo := strings.Repeat("remesa ñ ", 40)
+	txs := []domain.Transaction{{
+		Date:        time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC),
+		Amount:      dec("5"),
+		Description: description,
+		Memo:        memo,
+	}}
+
+	if err := repo.SaveTransactions(context.Background(), "bucket", "input/stmt.xml", txs); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+
+	var rec models.Transaction
+	if err := db.First(&rec).Error; err != nil {
+		t.Fatalf("failed to query transaction: %v", err)
+	}
+	if rec.Description != description || rec.Memo != memo {
+		t.Errorf("long text was altered: description %d runes, memo %d runes", len([]rune(rec.Description)), len([]rune(rec.Memo)))
 	}
 }
 
@@ -173,29 +332,261 @@ func TestTransactionRepo_SaveSummary_InsertsSummary(t *testing.T) {
 	if !rec.TotalBalance.Equal(summary.TotalBalance) {
 		t.Errorf("TotalBalance = %v, want %v", rec.TotalBalance, summary.TotalBalance)
 	}
+	if rec.OpeningBalance != nil || rec.ClosingBalance != nil {
+		t.Errorf("statement balances = %v / %v, want NULL", rec.OpeningBalance, rec.ClosingBalance)
+	}
 
-	var decoded []domain.MonthlySummary
-	if err := json.Unmarshal([]byte(rec.RawSummary), &decoded); err != nil {
-		t.Fatalf("RawSummary no es JSON válido: %v", err)
+	var months []models.MonthlySummary
+	if err := db.Where("account_summary_id = ?", rec.ID).Order("id").Find(&months).Error; err != nil {
+		t.Fatalf("failed to query monthly summaries: %v", err)
 	}
 
-	if len(decoded) != len(summary.ByMonth) {
-		t.Fatalf("len(decoded ByMonth) = %d, want %d", len(decoded), len(summary.ByMonth))
+	if len(months) != len(summary.ByMonth) {
+		t.Fatalf("len(monthly_summaries) = %d, want %d", len(months), len(summary.ByMonth))
 	}
 
-	for i, d := range decoded {
+	for i, m := range months {
 		want := summary.ByMonth[i]
-		if d.MonthName != want.MonthName {
-			t.Errorf("MonthName[%d] = %q, want %q", i, d.MonthName, want.MonthName)
+		if m.Month != want.MonthName {
+			t.Errorf("Month[%d] = %q, want %q", i, m.Month, want.MonthName)
 		}
-		if d.TransactionsCount != want.TransactionsCount {
-			t.Errorf("TransactionsCount[%d] = %d, want %d", i, d.TransactionsCount, want.TransactionsCount)
+		if m.TransactionsCount != want.TransactionsCount {
+			t.Errorf("TransactionsCount[%d] = %d, want %d", i, m.TransactionsCount, want.TransactionsCount)
 		}
-		if !d.AverageDebitAmount.Equal(want.AverageDebitAmount) {
-			t.Errorf("AverageDebitAmount[%d] = %v, want %v", i, d.AverageDebitAmount, want.AverageDebitAmount)
+		if !m.AverageDebitAmount.Equal(want.AverageDebitAmount) {
+			t.Errorf("AverageDebitAmount[%d] = %v, want %v", i, m.AverageDebitAmount, want.AverageDebitAmount)
 		}
-		if !d.AverageCreditAmount.Equal(want.AverageCreditAmount) {
-			t.Errorf("AverageCreditAmount[%d] = %v, want %v", i, d.AverageCreditAmount, want.AverageCreditAmount)
+		if !m.AverageCreditAmount.Equal(want.AverageCreditAmount) {
+			t.Errorf("AverageCreditAmount[%d] = %v, want %v", i, m.AverageCreditAmount, want.AverageCreditAmount)
 		}
 	}
 }
+
+func TestTransactionRepo_SaveSummary_PersistsStatementBalances(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+
+	opening, closing := dec("1000"), dec("1050.20")
+	summary := domain.AccountSummary{TotalBalance: dec("50.20"), OpeningBalance: &opening, ClosingBalance: &closing}
+	if err := repo.SaveSummary(context.Background(), "bucket", "input/acc-1/statement.xml", summary); err != nil {
+		t.Fatalf("SaveSummary returned error: %v", err)
+	}
+
+	var rec models.AccountSummary
+	if err := db.First(&rec).Error; err != nil {
+		t.Fatalf("failed to query account summary: %v", err)
+	}
+	got := mappers.ToAccountSummaryDomain(rec)
+	if got.OpeningBalance == nil || !got.OpeningBalance.Equal(opening) {
+		t.Errorf("OpeningBalance = %v, want %v", got.OpeningBalance, opening)
+	}
+	if got.ClosingBalance == nil || !got.ClosingBalance.Equal(closing) {
+		t.Errorf("ClosingBalance = %v, want %v", got.ClosingBalance, closing)
+	}
+}
+
+func TestTransactionRepo_SaveSummary_PersistsComparisons(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	ctx := context.Background()
+
+	summary := domain.AccountSummary{
+		AccountID:    "acc-1",
+		TotalBalance: dec("10"),
+		ByMonth: []domain.MonthlySummary{
+			{
+				MonthName:         "2021-08",
+				TransactionsCount: 2,
+				TotalDebitAmount:  dec("-5"),
+				VsPreviousMonth:   &domain.MonthlyDelta{BaselineMonth: "2021-07", TransactionsCount: 1, TotalDebitAmount: dec("-2.5")},
+				VsPreviousYear:    &domain.MonthlyDelta{BaselineMonth: "2020-08", TransactionsCount: -3},
+			},
+		},
+	}
+
+	if err := repo.SaveSummary(ctx, "bucket", "input/acc-1/txns.csv", summary); err != nil {
+		t.Fatalf("SaveSummary returned error: %v", err)
+	}
+
+	var comparisons []models.MonthlyComparison
+	if err := db.Order("baseline").Find(&comparisons).Error; err != nil {
+		t.Fatalf("failed to query monthly comparisons: %v", err)
+	}
+	if len(comparisons) != 2 {
+		t.Fatalf("expected 2 comparisons, got %d", len(comparisons))
+	}
+	if comparisons[0].Baseline != models.BaselinePreviousMonth || comparisons[0].BaselineMonth != "2021-07" ||
+		!comparisons[0].TotalDebitDelta.Equal(dec("-2.5")) {
+		t.Errorf("previous month comparison = %+v", comparisons[0])
+	}
+	if comparisons[1].Baseline != models.BaselinePreviousYear || comparisons[1].TransactionsCountDelta != -3 {
+		t.Errorf("previous year comparison = %+v", comparisons[1])
+	}
+}
+
+func TestTransactionRepo_DeleteObjectData_OnlyMatchingObject(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	rewards := NewRewardsLedgerRepo(db)
+	ctx := context.Background()
+
+	date := time.Date(2021, 8, 2, 0, 0, 0, 0, time.UTC)
+	summary := domain.AccountSummary{
+		AccountID:    "acc-1",
+		TotalBalance: dec("10"),
+		ByMonth: []domain.MonthlySummary{
+			{
+				MonthName:         "2021-08",
+				TransactionsCount: 1,
+				VsPreviousMonth:   &domain.MonthlyDelta{BaselineMonth: "2021-07", TransactionsCount: 1},
+			},
+		},
+	}
+	for _, key := range []string{"input/acc-1/a.csv", "input/acc-1/b.csv"} {
+		if err := repo.SaveTransactions(ctx, "bucket", key, []domain.Transaction{{Date: date, Amount: dec("10")}}); err != nil {
+			t.Fatalf("SaveTransactions returned error: %v", err)
+		}
+		if err := repo.SaveSummary(ctx, "bucket", key, summary); err != nil {
+			t.Fatalf("SaveSummary returned error: %v", err)
+		}
+		entries := []domain.RewardEntry{{AccountID: "acc-1", Cycle: "2021-08", Date: date, Amount: dec("10"), Points: dec("10")}}
+		if err := rewards.SaveRewardEntries(ctx, "bucket", key, entries); err != nil {
+			t.Fatalf("SaveRewardEntries returned error: %v", err)
+		}
+	}
+
+	if err := repo.DeleteObjectData(ctx, "bucket", "input/acc-1/a.csv"); err != nil {
+		t.Fatalf("DeleteObjectData returned error: %v", err)
+	}
+
+	for _, model := range []any{
+		&models.Transaction{},
+		&models.AccountSummary{},
+		&models.MonthlySummary{},
+		&models.MonthlyComparison{},
+		&models.RewardEntry{},
+	} {
+		var count int64
+		if err := db.Model(model).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count %T: %v", model, err)
+		}
+		if count != 1 {
+			t.Errorf("%T: expected 1 row left, got %d", model, count)
+		}
+	}
+
+	var left models.AccountSummary
+	if err := db.Preload("Months.Comparisons").First(&left).Error; err != nil {
+		t.Fatalf("failed to load remaining summary: %v", err)
+	}
+	if left.ObjectKey != "input/acc-1/b.csv" || len(left.Months) != 1 || len(left.Months[0].Comparisons) != 1 {
+		t.Errorf("remaining summary = %+v", left)
+	}
+}
+
+func TestTransactionRepo_ListTransactionsByAccount_FiltersByAccountAndRange(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	ctx := context.Background()
+
+	jun := time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC)
+	jul := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	aug := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-1/a.csv", []domain.Transaction{
+		{Date: jun, Amount: dec("1")},
+		{Date: jul, Amount: dec("-2"), Category: "groceries"},
+		{Date: aug, Amount: dec("3")},
+	}); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+	if err := repo.SaveTransactions(ctx, "bucket", "input/acc-2/b.csv", []domain.Transaction{
+		{Date: jul, Amount: dec("99")},
+	}); err != nil {
+		t.Fatalf("SaveTransactions returned error: %v", err)
+	}
+
+	got, err := repo.ListTransactionsByAccount(ctx, "acc-1",
+		time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), aug)
+	if err != nil {
+		t.Fatalf("ListTransactionsByAccount returned error: %v", err)
+	}
+
+	if len(got) != 1 {
+		t.Fatalf("expected 1 transaction, got %d (%v)", len(got), got)
+	}
+	if !got[0].Date.Equal(jul) || !got[0].Amount.Equal(dec("-2")) || got[0].Category != "groceries" {
+		t.Errorf("unexpected transaction: %+v", got[0])
+	}
+}
+
+func TestTransactionRepo_ListAccountsWithTransactions(t *testing.T) {
+	repo := NewTransactionRepo(setupTestDB(t))
+	ctx := context.Background()
+
+	jul := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+	aug := time.Date(2021, 8, 1, 0, 0, 0, 0, time.UTC)
+
+	for key, date := range map[string]time.Time{
+		"input/acc-2/a.csv": jul,
+		"input/acc-1/b.csv": jul,
+		"input/acc-1/c.csv": jul,
+		"input/acc-3/d.csv": aug,
+	} {
+		if err := repo.SaveTransactions(ctx, "bucket", key, []domain.Transaction{{Date: date, Amount: dec("1")}}); err != nil {
+			t.Fatalf("SaveTransactions returned error: %v", err)
+		}
+	}
+
+	got, err := repo.ListAccountsWithTransactions(ctx, time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC), aug)
+	if err != nil {
+		t.Fatalf("ListAccountsWithTransactions returned error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "acc-1" || got[1] != "acc-2" {
+		t.Errorf("accounts = %v, want [acc-1 acc-2]", got)
+	}
+}
+
+func TestTransactionRepo_FindLatestMonthlySummaries_PicksNewestPerMonth(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewTransactionRepo(db)
+	ctx := context.Background()
+
+	older := domain.AccountSummary{
+		AccountID: "acc-1",
+		ByMonth: []domain.MonthlySummary{
+			{MonthName: "2021-07", TransactionsCount: 1},
+			{MonthName: "2021-06", TransactionsCount: 6},
+		},
+	}
+	newer := domain.AccountSummary{
+		AccountID: "acc-1",
+		ByMonth: []domain.MonthlySummary{
+			{MonthName: "2021-07", TransactionsCount: 7, TotalDebitAmount: dec("-12.5")},
+		},
+	}
+	other := domain.AccountSummary{
+		AccountID: "acc-2",
+		ByMonth:   []domain.MonthlySummary{{MonthName: "2021-07", TransactionsCount: 100}},
+	}
+	for _, s := range []domain.AccountSummary{older, newer, other} {
+		if err := repo.SaveSummary(ctx, "bucket", "key", s); err != nil {
+			t.Fatalf("SaveSummary returned error: %v", err)
+		}
+	}
+
+	got, err := repo.FindLatestMonthlySummaries(ctx, "acc-1", []string{"2021-06", "2021-07", "2021-05"})
+	if err != nil {
+		t.Fatalf("FindLatestMonthlySummaries returned error: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 months, got %d (%v)", len(got), got)
+	}
+	if got["2021-07"].TransactionsCount != 7 || !got["2021-07"].TotalDebitAmount.Equal(dec("-12.5")) {
+		t.Errorf("2021-07 = %+v, want newest summary", got["2021-07"])
+	}
+	if got["2021-06"].TransactionsCount != 6 {
+		t.Errorf("2021-06 = %+v, want TransactionsCount 6", got["2021-06"])
+	}
+}
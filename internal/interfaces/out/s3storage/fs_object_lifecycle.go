@@ -0,0 +1,118 @@
+package s3storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+)
+
+// FSObjectLifecycle mueve los objetos en disco bajo la misma raíz que
+// FSObjectStorage. El disco no tiene tags: el resultado queda en la corrida y
+// en el reporte de error.
+type FSObjectLifecycle struct {
+	root string
+}
+
+var _ out.ObjectLifecycle = (*FSObjectLifecycle)(nil)
+
+func NewFSObjectLifecycle(root string) *FSObjectLifecycle {
+	return &FSObjectLifecycle{root: root}
+}
+
+func (l *FSObjectLifecycle) ObjectExists(_ context.Context, obj domain.ObjectRef) (bool, error) {
+	if obj.VersionID != "" {
+		return false, fmt.Errorf("el disco no soporta versiones: %s", obj)
+	}
+	name, err := fsObjectPath(obj.Bucket, obj.Key)
+	if err != nil {
+		return false, err
+	}
+
+	root, err := os.OpenRoot(l.root)
+	if err != nil {
+		return false, err
+	}
+	defer root.Close()
+
+	if _, err := root.Stat(name); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (l *FSObjectLifecycle) MoveObject(
+	_ context.Context,
+	src domain.ObjectRef,
+	dstKey string,
+	_ map[string]string,
+) error {
+	if dstKey == src.Key {
+		return nil
+	}
+	srcName, err := fsObjectPath(src.Bucket, src.Key)
+	if err != nil {
+		return err
+	}
+	dstName, err := fsObjectPath(src.Bucket, dstKey)
+	if err != nil {
+		return err
+	}
+
+	root, err := os.OpenRoot(l.root)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	if err := root.MkdirAll(filepath.Dir(dstName), 0o755); err != nil {
+		return err
+	}
+	return root.Rename(srcName, dstName)
+}
+
+func (l *FSObjectLifecycle) PutErrorReport(
+	_ context.Context,
+	bucket, key string,
+	report domain.ErrorReport,
+	_ map[string]string,
+) error {
+	name, err := fsObjectPath(bucket, key)
+	if err != nil {
+		return err
+	}
+	body, err := encodeErrorReport(report)
+	if err != nil {
+		return err
+	}
+
+	root, err := os.OpenRoot(l.root)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	if err := root.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := root.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, fs.ErrExist) {
+		return fmt.Errorf("%w: %s/%s", domain.ErrAlreadyExists, bucket, key)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,83 @@
+package s3storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"stori-challenge/internal/core/domain"
+)
+
+func TestFSObjectStorage_PutObject(t *testing.T) {
+	root := t.TempDir()
+	storage := NewFSObjectStorage(root)
+
+	if err := storage.PutObject(context.Background(), "bucket", "input/acc-1/a.csv", []byte("data"), "text/csv"); err != nil {
+		t.Fatalf("PutObject returned error: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "bucket", "input", "acc-1", "a.csv"))
+	if err != nil || string(got) != "data" {
+		t.Fatalf("stored %q (err=%v), want data", got, err)
+	}
+
+	if err := storage.PutObject(context.Background(), "bucket", "../../escape.csv", []byte("x"), "text/csv"); err == nil {
+		t.Errorf("expected an error for a key outside the root")
+	}
+}
+
+func TestFSObjectLifecycle_MoveObject(t *testing.T) {
+	root := t.TempDir()
+	if err := NewFSObjectStorage(root).PutObject(context.Background(), "b", "input/k.csv", []byte("data"), ""); err != nil {
+		t.Fatalf("PutObject returned error: %v", err)
+	}
+	lc := NewFSObjectLifecycle(root)
+	src := domain.ObjectRef{Bucket: "b", Key: "input/k.csv"}
+
+	if exists, err := lc.ObjectExists(context.Background(), src); err != nil || !exists {
+		t.Fatalf("ObjectExists = %v, %v; want true", exists, err)
+	}
+	if err := lc.MoveObject(context.Background(), src, "processed/input/k.csv", lifecycleTags); err != nil {
+		t.Fatalf("MoveObject returned error: %v", err)
+	}
+
+	if exists, err := lc.ObjectExists(context.Background(), src); err != nil || exists {
+		t.Errorf("ObjectExists after the move = %v, %v; want false", exists, err)
+	}
+	got, err := os.ReadFile(filepath.Join(root, "b", "processed", "input", "k.csv"))
+	if err != nil || string(got) != "data" {
+		t.Fatalf("moved object = %q (err=%v)", got, err)
+	}
+
+	if _, err := lc.ObjectExists(context.Background(), domain.ObjectRef{Bucket: "b", Key: "k.csv", VersionID: "v1"}); err == nil {
+		t.Errorf("expected an error for a versioned object")
+	}
+}
+
+func TestFSObjectLifecycle_PutErrorReport_DoesNotOverwrite(t *testing.T) {
+	root := t.TempDir()
+	lc := NewFSObjectLifecycle(root)
+	key := "quarantine/input/k.csv.error.json"
+
+	first := domain.ErrorReport{RunID: 3, Bucket: "b", Key: "input/k.csv", Error: "boom"}
+	if err := lc.PutErrorReport(context.Background(), "b", key, first, lifecycleTags); err != nil {
+		t.Fatalf("PutErrorReport returned error: %v", err)
+	}
+	second := domain.ErrorReport{RunID: 4, Error: "otro"}
+	if err := lc.PutErrorReport(context.Background(), "b", key, second, lifecycleTags); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+
+	body, err := os.ReadFile(filepath.Join(root, "b", filepath.FromSlash(key)))
+	if err != nil {
+		t.Fatalf("report not stored: %v", err)
+	}
+	var got struct {
+		RunID uint64 `json:"run_id"`
+	}
+	if err := json.Unmarshal(body, &got); err != nil || got.RunID != 3 {
+		t.Errorf("report = %s (err=%v), want the first one", body, err)
+	}
+}
@@ -0,0 +1,64 @@
+package s3storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"stori-challenge/internal/core/ports/out"
+)
+
+// FSObjectStorage guarda los objetos en disco como <root>/<bucket>/<key>, la
+// misma ruta que lee csvreader.FSCSVReader (FILE_READER=fs).
+type FSObjectStorage struct {
+	root string
+}
+
+var _ out.ObjectStorage = (*FSObjectStorage)(nil)
+
+func NewFSObjectStorage(root string) *FSObjectStorage {
+	return &FSObjectStorage{root: root}
+}
+
+// PutObject escribe en un temporal y lo renombra, así el lector nunca ve un
+// archivo a medias.
+func (s *FSObjectStorage) PutObject(
+	_ context.Context,
+	bucket, key string,
+	body []byte,
+	_ string,
+) error {
+	name, err := fsObjectPath(bucket, key)
+	if err != nil {
+		return err
+	}
+
+	root, err := os.OpenRoot(s.root)
+	if err != nil {
+		return err
+	}
+	defer root.Close()
+
+	if err := root.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	tmp := name + ".tmp"
+	if err := root.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	if err := root.Rename(tmp, name); err != nil {
+		_ = root.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// fsObjectPath arma la ruta relativa a la raíz. os.Root rechaza después las
+// keys con ".." o symlinks que salgan de ella.
+func fsObjectPath(bucket, key string) (string, error) {
+	if bucket == "" || key == "" {
+		return "", fmt.Errorf("objeto inválido: %s/%s", bucket, key)
+	}
+	return filepath.Join(bucket, filepath.FromSlash(key)), nil
+}
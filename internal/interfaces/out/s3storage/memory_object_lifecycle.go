@@ -0,0 +1,91 @@
+package s3storage
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"sync"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+)
+
+type MemoryObject struct {
+	Body []byte
+	Tags map[string]string
+}
+
+// MemoryObjectLifecycle implementa el ciclo de vida sobre un mapa en memoria,
+// para tests y ejecuciones locales sin S3. No distingue versiones.
+type MemoryObjectLifecycle struct {
+	mu      sync.Mutex
+	objects map[string]MemoryObject
+}
+
+var _ out.ObjectLifecycle = (*MemoryObjectLifecycle)(nil)
+
+func NewMemoryObjectLifecycle() *MemoryObjectLifecycle {
+	return &MemoryObjectLifecycle{objects: map[string]MemoryObject{}}
+}
+
+func memoryKey(bucket, key string) string {
+	return bucket + "/" + key
+}
+
+// Put agrega un objeto, como si hubiera llegado al bucket.
+func (m *MemoryObjectLifecycle) Put(bucket, key string, body []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.objects[memoryKey(bucket, key)] = MemoryObject{Body: body}
+}
+
+func (m *MemoryObjectLifecycle) Object(bucket, key string) (MemoryObject, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.objects[memoryKey(bucket, key)]
+	return obj, ok
+}
+
+func (m *MemoryObjectLifecycle) ObjectExists(_ context.Context, obj domain.ObjectRef) (bool, error) {
+	_, ok := m.Object(obj.Bucket, obj.Key)
+	return ok, nil
+}
+
+func (m *MemoryObjectLifecycle) MoveObject(
+	_ context.Context,
+	src domain.ObjectRef,
+	dstKey string,
+	tags map[string]string,
+) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[memoryKey(src.Bucket, src.Key)]
+	if !ok {
+		return fmt.Errorf("objeto inexistente: %s", src)
+	}
+	delete(m.objects, memoryKey(src.Bucket, src.Key))
+	obj.Tags = maps.Clone(tags)
+	m.objects[memoryKey(src.Bucket, dstKey)] = obj
+	return nil
+}
+
+func (m *MemoryObjectLifecycle) PutErrorReport(
+	_ context.Context,
+	bucket, key string,
+	report domain.ErrorReport,
+	tags map[string]string,
+) error {
+	body, err := encodeErrorReport(report)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.objects[memoryKey(bucket, key)]; ok {
+		return fmt.Errorf("%w: %s/%s", domain.ErrAlreadyExists, bucket, key)
+	}
+	m.objects[memoryKey(bucket, key)] = MemoryObject{Body: body, Tags: maps.Clone(tags)}
+	return nil
+}
@@ -0,0 +1,190 @@
+package s3storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+type s3LifecycleAPI interface {
+	s3PutObjectAPI
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	PutObjectTagging(ctx context.Context, params *s3.PutObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.PutObjectTaggingOutput, error)
+	HeadObject(ctx context.Context, params *s3.HeadObjectInput, optFns ...func(*s3.Options)) (*s3.HeadObjectOutput, error)
+}
+
+type S3ObjectLifecycle struct {
+	s3Client s3LifecycleAPI
+}
+
+var _ out.ObjectLifecycle = (*S3ObjectLifecycle)(nil)
+
+func NewS3ObjectLifecycle(s3Client s3LifecycleAPI) *S3ObjectLifecycle {
+	return &S3ObjectLifecycle{s3Client: s3Client}
+}
+
+func (l *S3ObjectLifecycle) ObjectExists(ctx context.Context, obj domain.ObjectRef) (bool, error) {
+	input := &s3.HeadObjectInput{Bucket: &obj.Bucket, Key: &obj.Key}
+	if obj.VersionID != "" {
+		input.VersionId = &obj.VersionID
+	}
+	_, err := l.s3Client.HeadObject(ctx, input)
+	if err == nil {
+		return true, nil
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "NotFound", "NoSuchKey", "NoSuchVersion":
+			return false, nil
+		}
+	}
+	return false, err
+}
+
+// MoveObject copia la versión leída y luego borra esa misma versión, de modo
+// que una versión más nueva subida entretanto no se pierde.
+func (l *S3ObjectLifecycle) MoveObject(
+	ctx context.Context,
+	src domain.ObjectRef,
+	dstKey string,
+	tags map[string]string,
+) error {
+	if dstKey == src.Key {
+		input := &s3.PutObjectTaggingInput{
+			Bucket:  &src.Bucket,
+			Key:     &src.Key,
+			Tagging: &types.Tagging{TagSet: tagSet(tags)},
+		}
+		if src.VersionID != "" {
+			input.VersionId = &src.VersionID
+		}
+		_, err := l.s3Client.PutObjectTagging(ctx, input)
+		return err
+	}
+
+	copySource := copySource(src)
+	tagging := encodeTags(tags)
+	_, err := l.s3Client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:           &src.Bucket,
+		Key:              &dstKey,
+		CopySource:       &copySource,
+		Tagging:          &tagging,
+		TaggingDirective: types.TaggingDirectiveReplace,
+	})
+	if err != nil {
+		return err
+	}
+
+	del := &s3.DeleteObjectInput{Bucket: &src.Bucket, Key: &src.Key}
+	if src.VersionID != "" {
+		del.VersionId = &src.VersionID
+	}
+	_, err = l.s3Client.DeleteObject(ctx, del)
+	return err
+}
+
+type errorReportDTO struct {
+	RunID     uint64    `json:"run_id"`
+	Bucket    string    `json:"bucket"`
+	Key       string    `json:"key"`
+	VersionID string    `json:"version_id,omitempty"`
+	Error     string    `json:"error"`
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+func (l *S3ObjectLifecycle) PutErrorReport(
+	ctx context.Context,
+	bucket, key string,
+	report domain.ErrorReport,
+	tags map[string]string,
+) error {
+	body, err := encodeErrorReport(report)
+	if err != nil {
+		return err
+	}
+
+	size := int64(len(body))
+	contentType := "application/json"
+	tagging := encodeTags(tags)
+	_, err = l.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        &bucket,
+		Key:           &key,
+		Body:          bytes.NewReader(body),
+		ContentLength: &size,
+		ContentType:   &contentType,
+		Tagging:       &tagging,
+		IfNoneMatch:   aws.String("*"),
+	})
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ErrorCode() {
+		case "PreconditionFailed", "ConditionalRequestConflict":
+			return fmt.Errorf("%w: %s/%s", domain.ErrAlreadyExists, bucket, key)
+		}
+	}
+	return err
+}
+
+func encodeErrorReport(r domain.ErrorReport) ([]byte, error) {
+	return json.MarshalIndent(errorReportDTO{
+		RunID:     r.RunID,
+		Bucket:    r.Bucket,
+		Key:       r.Key,
+		VersionID: r.VersionID,
+		Error:     r.Error,
+		FailedAt:  r.FailedAt,
+	}, "", "  ")
+}
+
+// copySource arma "bucket/key[?versionId=...]" con cada segmento de la key
+// codificado, como exige CopyObject.
+func copySource(obj domain.ObjectRef) string {
+	segments := strings.Split(obj.Key, "/")
+	for i, seg := range segments {
+		segments[i] = url.PathEscape(seg)
+	}
+	s := obj.Bucket + "/" + strings.Join(segments, "/")
+	if obj.VersionID != "" {
+		s += "?versionId=" + url.QueryEscape(obj.VersionID)
+	}
+	return s
+}
+
+// encodeTags usa el formato de query string que esperan PutObject y CopyObject.
+func encodeTags(tags map[string]string) string {
+	values := url.Values{}
+	for k, v := range tags {
+		values.Set(k, v)
+	}
+	return values.Encode()
+}
+
+func tagSet(tags map[string]string) []types.Tag {
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	set := make([]types.Tag, 0, len(keys))
+	for _, k := range keys {
+		set = append(set, types.Tag{Key: aws.String(k), Value: aws.String(tags[k])})
+	}
+	return set
+}
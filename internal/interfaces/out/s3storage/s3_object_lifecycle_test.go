@@ -0,0 +1,254 @@
+package s3storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"stori-challenge/internal/core/domain"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+)
+
+type fakeLifecycleClient struct {
+	fakeS3Client
+
+	copyErr error
+	headErr error
+
+	gotHead    *s3.HeadObjectInput
+	gotCopy    *s3.CopyObjectInput
+	gotDelete  *s3.DeleteObjectInput
+	gotTagging *s3.PutObjectTaggingInput
+}
+
+func (f *fakeLifecycleClient) CopyObject(
+	_ context.Context,
+	in *s3.CopyObjectInput,
+	_ ...func(*s3.Options),
+) (*s3.CopyObjectOutput, error) {
+	f.gotCopy = in
+	return &s3.CopyObjectOutput{}, f.copyErr
+}
+
+func (f *fakeLifecycleClient) DeleteObject(
+	_ context.Context,
+	in *s3.DeleteObjectInput,
+	_ ...func(*s3.Options),
+) (*s3.DeleteObjectOutput, error) {
+	f.gotDelete = in
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeLifecycleClient) PutObjectTagging(
+	_ context.Context,
+	in *s3.PutObjectTaggingInput,
+	_ ...func(*s3.Options),
+) (*s3.PutObjectTaggingOutput, error) {
+	f.gotTagging = in
+	return &s3.PutObjectTaggingOutput{}, nil
+}
+
+func (f *fakeLifecycleClient) HeadObject(
+	_ context.Context,
+	in *s3.HeadObjectInput,
+	_ ...func(*s3.Options),
+) (*s3.HeadObjectOutput, error) {
+	f.gotHead = in
+	return &s3.HeadObjectOutput{}, f.headErr
+}
+
+var lifecycleTags = map[string]string{
+	domain.ObjectTagRunID:  "7",
+	domain.ObjectTagStatus: "succeeded",
+}
+
+func TestS3ObjectLifecycle_MoveObject_CopiesThenDeletesVersion(t *testing.T) {
+	client := &fakeLifecycleClient{}
+	lc := NewS3ObjectLifecycle(client)
+
+	src := domain.ObjectRef{Bucket: "bucket", Key: "input/acc-1/estado de cuenta.csv", VersionID: "v 1"}
+	if err := lc.MoveObject(context.Background(), src, "processed/input/acc-1/estado de cuenta.csv", lifecycleTags); err != nil {
+		t.Fatalf("MoveObject returned error: %v", err)
+	}
+
+	if client.gotCopy == nil {
+		t.Fatalf("CopyObject was not called")
+	}
+	if got, want := *client.gotCopy.CopySource, "bucket/input/acc-1/estado%20de%20cuenta.csv?versionId=v+1"; got != want {
+		t.Errorf("CopySource = %q, want %q", got, want)
+	}
+	if *client.gotCopy.Key != "processed/input/acc-1/estado de cuenta.csv" {
+		t.Errorf("destination key = %q", *client.gotCopy.Key)
+	}
+	if *client.gotCopy.Tagging != "stori-run-id=7&stori-status=succeeded" ||
+		client.gotCopy.TaggingDirective != types.TaggingDirectiveReplace {
+		t.Errorf("tagging = %q (%s)", *client.gotCopy.Tagging, client.gotCopy.TaggingDirective)
+	}
+
+	if client.gotDelete == nil {
+		t.Fatalf("DeleteObject was not called")
+	}
+	if *client.gotDelete.Key != src.Key || client.gotDelete.VersionId == nil || *client.gotDelete.VersionId != "v 1" {
+		t.Errorf("delete = %s@%v, want the version that was read", *client.gotDelete.Key, client.gotDelete.VersionId)
+	}
+}
+
+func TestS3ObjectLifecycle_MoveObject_CopyErrorKeepsSource(t *testing.T) {
+	wantErr := errors.New("access denied")
+	client := &fakeLifecycleClient{copyErr: wantErr}
+	lc := NewS3ObjectLifecycle(client)
+
+	err := lc.MoveObject(context.Background(), domain.ObjectRef{Bucket: "b", Key: "k.csv"}, "processed/k.csv", lifecycleTags)
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+	if client.gotDelete != nil {
+		t.Errorf("source must not be deleted when the copy fails")
+	}
+}
+
+func TestS3ObjectLifecycle_MoveObject_SameKeyOnlyRetags(t *testing.T) {
+	client := &fakeLifecycleClient{}
+	lc := NewS3ObjectLifecycle(client)
+
+	src := domain.ObjectRef{Bucket: "b", Key: "processed/k.csv"}
+	if err := lc.MoveObject(context.Background(), src, src.Key, lifecycleTags); err != nil {
+		t.Fatalf("MoveObject returned error: %v", err)
+	}
+	if client.gotCopy != nil || client.gotDelete != nil {
+		t.Fatalf("an object already in place must not be copied or deleted")
+	}
+	if client.gotTagging == nil || len(client.gotTagging.Tagging.TagSet) != 2 {
+		t.Fatalf("expected tags to be replaced, got %+v", client.gotTagging)
+	}
+	if *client.gotTagging.Tagging.TagSet[0].Key != domain.ObjectTagRunID {
+		t.Errorf("tags should be sorted by key, got %s first", *client.gotTagging.Tagging.TagSet[0].Key)
+	}
+}
+
+func TestS3ObjectLifecycle_ObjectExists(t *testing.T) {
+	client := &fakeLifecycleClient{}
+	lc := NewS3ObjectLifecycle(client)
+
+	obj := domain.ObjectRef{Bucket: "b", Key: "input/k.csv", VersionID: "v1"}
+	exists, err := lc.ObjectExists(context.Background(), obj)
+	if err != nil || !exists {
+		t.Fatalf("ObjectExists = %v, %v; want true", exists, err)
+	}
+	if client.gotHead.VersionId == nil || *client.gotHead.VersionId != "v1" {
+		t.Errorf("HeadObject should ask for the version that was read, got %+v", client.gotHead)
+	}
+
+	client.headErr = &types.NotFound{}
+	if exists, err := lc.ObjectExists(context.Background(), obj); err != nil || exists {
+		t.Errorf("ObjectExists on a missing object = %v, %v; want false, nil", exists, err)
+	}
+
+	wantErr := errors.New("access denied")
+	client.headErr = wantErr
+	if _, err := lc.ObjectExists(context.Background(), obj); !errors.Is(err, wantErr) {
+		t.Errorf("expected %v, got %v", wantErr, err)
+	}
+}
+
+func TestS3ObjectLifecycle_PutErrorReport_DoesNotOverwrite(t *testing.T) {
+	client := &fakeLifecycleClient{}
+	client.err = &smithy.GenericAPIError{Code: "PreconditionFailed"}
+	lc := NewS3ObjectLifecycle(client)
+
+	err := lc.PutErrorReport(context.Background(), "b", "quarantine/k.csv.error.json", domain.ErrorReport{}, lifecycleTags)
+	if !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Fatalf("expected ErrAlreadyExists, got %v", err)
+	}
+	if client.got.IfNoneMatch == nil || *client.got.IfNoneMatch != "*" {
+		t.Errorf("the report must be written with If-None-Match: *")
+	}
+}
+
+func TestS3ObjectLifecycle_PutErrorReport(t *testing.T) {
+	client := &fakeLifecycleClient{}
+	lc := NewS3ObjectLifecycle(client)
+
+	report := domain.ErrorReport{
+		RunID:    7,
+		Bucket:   "b",
+		Key:      "input/k.csv",
+		Error:    "fila 3: monto inválido",
+		FailedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+	}
+	if err := lc.PutErrorReport(context.Background(), "b", "quarantine/input/k.csv.error.json", report, lifecycleTags); err != nil {
+		t.Fatalf("PutErrorReport returned error: %v", err)
+	}
+
+	if *client.got.Key != "quarantine/input/k.csv.error.json" || *client.got.ContentType != "application/json" {
+		t.Errorf("unexpected input: key=%q type=%q", *client.got.Key, *client.got.ContentType)
+	}
+	if *client.got.Tagging != "stori-run-id=7&stori-status=succeeded" {
+		t.Errorf("tagging = %q", *client.got.Tagging)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal([]byte(client.body), &got); err != nil {
+		t.Fatalf("report is not JSON: %v", err)
+	}
+	if got["run_id"] != float64(7) || got["error"] != report.Error || got["failed_at"] != "2024-05-01T10:00:00Z" {
+		t.Errorf("unexpected report: %v", got)
+	}
+	if _, ok := got["version_id"]; ok {
+		t.Errorf("version_id should be omitted when empty")
+	}
+}
+
+func TestMemoryObjectLifecycle_MoveObject(t *testing.T) {
+	lc := NewMemoryObjectLifecycle()
+	lc.Put("b", "input/k.csv", []byte("data"))
+
+	src := domain.ObjectRef{Bucket: "b", Key: "input/k.csv"}
+	if err := lc.MoveObject(context.Background(), src, "processed/input/k.csv", lifecycleTags); err != nil {
+		t.Fatalf("MoveObject returned error: %v", err)
+	}
+
+	if _, ok := lc.Object("b", "input/k.csv"); ok {
+		t.Errorf("source should be gone after the move")
+	}
+	if exists, _ := lc.ObjectExists(context.Background(), src); exists {
+		t.Errorf("ObjectExists should be false after the move")
+	}
+	obj, ok := lc.Object("b", "processed/input/k.csv")
+	if !ok || string(obj.Body) != "data" || obj.Tags[domain.ObjectTagStatus] != "succeeded" {
+		t.Fatalf("unexpected destination: %+v (found=%v)", obj, ok)
+	}
+
+	if err := lc.MoveObject(context.Background(), src, "processed/input/k.csv", lifecycleTags); err == nil {
+		t.Fatalf("expected an error moving a missing object")
+	}
+}
+
+func TestMemoryObjectLifecycle_PutErrorReport(t *testing.T) {
+	lc := NewMemoryObjectLifecycle()
+
+	report := domain.ErrorReport{RunID: 3, Bucket: "b", Key: "k.csv", Error: "boom"}
+	if err := lc.PutErrorReport(context.Background(), "b", "quarantine/k.csv.error.json", report, lifecycleTags); err != nil {
+		t.Fatalf("PutErrorReport returned error: %v", err)
+	}
+
+	obj, ok := lc.Object("b", "quarantine/k.csv.error.json")
+	if !ok {
+		t.Fatalf("report not stored")
+	}
+	if err := lc.PutErrorReport(context.Background(), "b", "quarantine/k.csv.error.json", report, lifecycleTags); !errors.Is(err, domain.ErrAlreadyExists) {
+		t.Errorf("expected ErrAlreadyExists writing the report twice, got %v", err)
+	}
+	var got struct {
+		RunID uint64 `json:"run_id"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(obj.Body, &got); err != nil || got.RunID != 3 || got.Error != "boom" {
+		t.Errorf("unexpected report %s (err=%v)", obj.Body, err)
+	}
+}
@@ -0,0 +1,47 @@
+package s3storage
+
+import (
+	"context"
+
+	"stori-challenge/internal/core/domain"
+	"stori-challenge/internal/core/ports/out"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type S3ObjectLister struct {
+	s3Client s3.ListObjectsV2APIClient
+}
+
+var _ out.ObjectLister = (*S3ObjectLister)(nil)
+
+func NewS3ObjectLister(s3Client s3.ListObjectsV2APIClient) *S3ObjectLister {
+	return &S3ObjectLister{s3Client: s3Client}
+}
+
+// ListObjects recorre todas las páginas de ListObjectsV2 bajo el prefijo.
+func (l *S3ObjectLister) ListObjects(ctx context.Context, bucket, prefix string) ([]domain.ObjectInfo, error) {
+	input := &s3.ListObjectsV2Input{Bucket: &bucket}
+	if prefix != "" {
+		input.Prefix = &prefix
+	}
+
+	var objects []domain.ObjectInfo
+	paginator := s3.NewListObjectsV2Paginator(l.s3Client, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			objects = append(objects, domain.ObjectInfo{
+				Bucket:       bucket,
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				LastModified: aws.ToTime(obj.LastModified),
+			})
+		}
+	}
+	return objects, nil
+}
@@ -0,0 +1,71 @@
+package s3storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+type fakeListClient struct {
+	pages []*s3.ListObjectsV2Output
+	err   error
+
+	calls []*s3.ListObjectsV2Input
+}
+
+func (f *fakeListClient) ListObjectsV2(
+	_ context.Context,
+	in *s3.ListObjectsV2Input,
+	_ ...func(*s3.Options),
+) (*s3.ListObjectsV2Output, error) {
+	f.calls = append(f.calls, in)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.pages[len(f.calls)-1], nil
+}
+
+func TestS3ObjectLister_ListObjects_FollowsPages(t *testing.T) {
+	modified := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	client := &fakeListClient{pages: []*s3.ListObjectsV2Output{
+		{
+			Contents:              []types.Object{{Key: aws.String("input/a.csv"), Size: aws.Int64(10), LastModified: &modified}},
+			IsTruncated:           aws.Bool(true),
+			NextContinuationToken: aws.String("next"),
+		},
+		{
+			Contents: []types.Object{{Key: aws.String("input/b.csv"), Size: aws.Int64(20)}},
+		},
+	}}
+
+	objects, err := NewS3ObjectLister(client).ListObjects(context.Background(), "bucket", "input/")
+	if err != nil {
+		t.Fatalf("ListObjects returned error: %v", err)
+	}
+
+	if len(objects) != 2 || objects[0].Key != "input/a.csv" || objects[1].Key != "input/b.csv" {
+		t.Fatalf("unexpected objects: %+v", objects)
+	}
+	if objects[0].Bucket != "bucket" || objects[0].Size != 10 || !objects[0].LastModified.Equal(modified) {
+		t.Errorf("unexpected first object: %+v", objects[0])
+	}
+	if len(client.calls) != 2 || aws.ToString(client.calls[0].Prefix) != "input/" {
+		t.Errorf("unexpected calls: %d, prefix %q", len(client.calls), aws.ToString(client.calls[0].Prefix))
+	}
+	if aws.ToString(client.calls[1].ContinuationToken) != "next" {
+		t.Errorf("second call token = %q, want next", aws.ToString(client.calls[1].ContinuationToken))
+	}
+}
+
+func TestS3ObjectLister_ListObjects_Error(t *testing.T) {
+	wantErr := errors.New("access denied")
+	_, err := NewS3ObjectLister(&fakeListClient{err: wantErr}).ListObjects(context.Background(), "bucket", "")
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
@@ -0,0 +1,45 @@
+package s3storage
+
+import (
+	"bytes"
+	"context"
+
+	"stori-challenge/internal/core/ports/out"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type s3PutObjectAPI interface {
+	PutObject(
+		ctx context.Context,
+		params *s3.PutObjectInput,
+		optFns ...func(*s3.Options),
+	) (*s3.PutObjectOutput, error)
+}
+
+type S3ObjectStorage struct {
+	s3Client s3PutObjectAPI
+}
+
+var _ out.ObjectStorage = (*S3ObjectStorage)(nil)
+
+func NewS3ObjectStorage(s3Client s3PutObjectAPI) *S3ObjectStorage {
+	return &S3ObjectStorage{s3Client: s3Client}
+}
+
+func (s *S3ObjectStorage) PutObject(
+	ctx context.Context,
+	bucket, key string,
+	body []byte,
+	contentType string,
+) error {
+	size := int64(len(body))
+	_, err := s.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        &bucket,
+		Key:           &key,
+		Body:          bytes.NewReader(body),
+		ContentLength: &size,
+		ContentType:   &contentType,
+	})
+	return err
+}
@@ -0,0 +1,53 @@
+package s3storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+type fakeS3Client struct {
+	err error
+
+	got  *s3.PutObjectInput
+	body string
+}
+
+func (f *fakeS3Client) PutObject(
+	_ context.Context,
+	in *s3.PutObjectInput,
+	_ ...func(*s3.Options),
+) (*s3.PutObjectOutput, error) {
+	f.got = in
+	b, _ := io.ReadAll(in.Body)
+	f.body = string(b)
+	return &s3.PutObjectOutput{}, f.err
+}
+
+func TestS3ObjectStorage_PutObject(t *testing.T) {
+	client := &fakeS3Client{}
+	storage := NewS3ObjectStorage(client)
+
+	if err := storage.PutObject(context.Background(), "bucket", "input/a.csv", []byte("data"), "text/csv"); err != nil {
+		t.Fatalf("PutObject returned error: %v", err)
+	}
+
+	if *client.got.Bucket != "bucket" || *client.got.Key != "input/a.csv" {
+		t.Errorf("bucket/key = %s/%s", *client.got.Bucket, *client.got.Key)
+	}
+	if client.body != "data" || *client.got.ContentLength != 4 || *client.got.ContentType != "text/csv" {
+		t.Errorf("unexpected input: body=%q length=%d type=%q", client.body, *client.got.ContentLength, *client.got.ContentType)
+	}
+}
+
+func TestS3ObjectStorage_PutObject_Error(t *testing.T) {
+	wantErr := errors.New("access denied")
+	storage := NewS3ObjectStorage(&fakeS3Client{err: wantErr})
+
+	if err := storage.PutObject(context.Background(), "b", "k", nil, "text/csv"); !errors.Is(err, wantErr) {
+		t.Fatalf("expected %v, got %v", wantErr, err)
+	}
+}
@@ -0,0 +1,2 @@
+ALTER TABLE transactions.transactions
+    DROP COLUMN IF EXISTS category;
@@ -0,0 +1,2 @@
+ALTER TABLE transactions.transactions
+    ADD COLUMN IF NOT EXISTS category varchar(64);
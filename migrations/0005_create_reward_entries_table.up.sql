@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS transactions.reward_entries
+(
+    id               bigserial PRIMARY KEY,
+    account_id       varchar(255) NOT NULL,
+    cycle            varchar(7)   NOT NULL,
+    bucket           varchar(255),
+    object_key       varchar(512),
+    category         varchar(64),
+    transaction_date timestamptz,
+    amount           numeric(15, 2),
+    points           numeric(15, 2),
+    cashback         numeric(15, 2),
+    created_at       timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_reward_entries_account_cycle
+    ON transactions.reward_entries (account_id, cycle);
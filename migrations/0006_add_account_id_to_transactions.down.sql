@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS transactions.idx_transactions_account_id_date;
+
+ALTER TABLE transactions.transactions
+    DROP COLUMN IF EXISTS account_id;
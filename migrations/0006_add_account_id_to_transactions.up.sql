@@ -0,0 +1,5 @@
+ALTER TABLE transactions.transactions
+    ADD COLUMN IF NOT EXISTS account_id varchar(255);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_id_date
+    ON transactions.transactions (account_id, date);
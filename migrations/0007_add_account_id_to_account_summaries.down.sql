@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS transactions.idx_account_summaries_account_id;
+
+ALTER TABLE transactions.account_summaries
+    DROP COLUMN IF EXISTS account_id;
@@ -0,0 +1,5 @@
+ALTER TABLE transactions.account_summaries
+    ADD COLUMN IF NOT EXISTS account_id varchar(255);
+
+CREATE INDEX IF NOT EXISTS idx_account_summaries_account_id
+    ON transactions.account_summaries (account_id);
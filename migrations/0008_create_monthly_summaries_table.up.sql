@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS transactions.monthly_summaries
+(
+    id                    bigserial PRIMARY KEY,
+    account_summary_id    bigint     NOT NULL
+        REFERENCES transactions.account_summaries (id) ON DELETE CASCADE,
+    month                 varchar(7) NOT NULL,
+    transactions_count    integer    NOT NULL DEFAULT 0,
+    average_debit_amount  numeric    NOT NULL DEFAULT 0,
+    average_credit_amount numeric    NOT NULL DEFAULT 0,
+    total_debit_amount    numeric    NOT NULL DEFAULT 0,
+    total_credit_amount   numeric    NOT NULL DEFAULT 0,
+    CONSTRAINT uq_monthly_summaries_summary_month UNIQUE (account_summary_id, month)
+);
+
+CREATE INDEX IF NOT EXISTS idx_monthly_summaries_month
+    ON transactions.monthly_summaries (month);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS transactions.monthly_comparisons
+(
+    id                       bigserial PRIMARY KEY,
+    monthly_summary_id       bigint      NOT NULL
+        REFERENCES transactions.monthly_summaries (id) ON DELETE CASCADE,
+    baseline                 varchar(16) NOT NULL,
+    baseline_month           varchar(7)  NOT NULL,
+    transactions_count_delta integer     NOT NULL DEFAULT 0,
+    average_debit_delta      numeric     NOT NULL DEFAULT 0,
+    average_credit_delta     numeric     NOT NULL DEFAULT 0,
+    total_debit_delta        numeric     NOT NULL DEFAULT 0,
+    total_credit_delta       numeric     NOT NULL DEFAULT 0,
+    CONSTRAINT uq_monthly_comparisons_summary_baseline UNIQUE (monthly_summary_id, baseline)
+);
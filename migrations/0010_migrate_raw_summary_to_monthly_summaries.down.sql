@@ -0,0 +1,17 @@
+ALTER TABLE transactions.account_summaries
+    ADD COLUMN IF NOT EXISTS raw_summary text;
+
+UPDATE transactions.account_summaries s
+SET raw_summary = (SELECT COALESCE(json_agg(json_build_object(
+                                                    'MonthName', ms.month,
+                                                    'TransactionsCount', ms.transactions_count,
+                                                    'AverageDebitAmount', ms.average_debit_amount::text,
+                                                    'AverageCreditAmount', ms.average_credit_amount::text,
+                                                    'TotalDebitAmount', ms.total_debit_amount::text,
+                                                    'TotalCreditAmount', ms.total_credit_amount::text
+                                                ) ORDER BY ms.id), '[]'::json)::text
+                   FROM transactions.monthly_summaries ms
+                   WHERE ms.account_summary_id = s.id);
+
+ALTER TABLE transactions.account_summaries
+    ALTER COLUMN total_balance TYPE text USING total_balance::text;
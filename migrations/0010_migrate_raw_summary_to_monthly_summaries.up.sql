@@ -0,0 +1,44 @@
+ALTER TABLE transactions.account_summaries
+    ALTER COLUMN total_balance TYPE numeric USING NULLIF(total_balance, '')::numeric;
+
+INSERT INTO transactions.monthly_summaries (account_summary_id, month, transactions_count,
+                                            average_debit_amount, average_credit_amount,
+                                            total_debit_amount, total_credit_amount)
+SELECT s.id,
+       m ->> 'MonthName',
+       COALESCE((m ->> 'TransactionsCount')::integer, 0),
+       COALESCE((m ->> 'AverageDebitAmount')::numeric, 0),
+       COALESCE((m ->> 'AverageCreditAmount')::numeric, 0),
+       COALESCE((m ->> 'TotalDebitAmount')::numeric, 0),
+       COALESCE((m ->> 'TotalCreditAmount')::numeric, 0)
+FROM transactions.account_summaries s
+         CROSS JOIN LATERAL jsonb_array_elements(s.raw_summary::jsonb) AS m
+WHERE s.raw_summary IS NOT NULL
+  AND jsonb_typeof(s.raw_summary::jsonb) = 'array'
+ON CONFLICT (account_summary_id, month) DO NOTHING;
+
+INSERT INTO transactions.monthly_comparisons (monthly_summary_id, baseline, baseline_month,
+                                              transactions_count_delta,
+                                              average_debit_delta, average_credit_delta,
+                                              total_debit_delta, total_credit_delta)
+SELECT ms.id,
+       b.baseline,
+       b.delta ->> 'BaselineMonth',
+       COALESCE((b.delta ->> 'TransactionsCount')::integer, 0),
+       COALESCE((b.delta ->> 'AverageDebitAmount')::numeric, 0),
+       COALESCE((b.delta ->> 'AverageCreditAmount')::numeric, 0),
+       COALESCE((b.delta ->> 'TotalDebitAmount')::numeric, 0),
+       COALESCE((b.delta ->> 'TotalCreditAmount')::numeric, 0)
+FROM transactions.account_summaries s
+         CROSS JOIN LATERAL jsonb_array_elements(s.raw_summary::jsonb) AS m
+         JOIN transactions.monthly_summaries ms
+              ON ms.account_summary_id = s.id AND ms.month = m ->> 'MonthName'
+         CROSS JOIN LATERAL (VALUES ('previous_month', m -> 'VsPreviousMonth'),
+                                    ('previous_year', m -> 'VsPreviousYear')) AS b (baseline, delta)
+WHERE s.raw_summary IS NOT NULL
+  AND jsonb_typeof(s.raw_summary::jsonb) = 'array'
+  AND jsonb_typeof(b.delta) = 'object'
+ON CONFLICT (monthly_summary_id, baseline) DO NOTHING;
+
+ALTER TABLE transactions.account_summaries
+    DROP COLUMN IF EXISTS raw_summary;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS transactions.idx_transactions_account_date_id;
+
+DROP TABLE IF EXISTS transactions.processing_runs;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS transactions.processing_runs
+(
+    id                 bigserial PRIMARY KEY,
+    account_id         varchar(255),
+    bucket             varchar(255),
+    object_key         varchar(512),
+    status             varchar(16) NOT NULL,
+    error              text,
+    transactions_count integer     NOT NULL DEFAULT 0,
+    created_at         timestamptz DEFAULT now(),
+    started_at         timestamptz,
+    finished_at        timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS idx_processing_runs_account_id
+    ON transactions.processing_runs (account_id);
+
+CREATE INDEX IF NOT EXISTS idx_processing_runs_object
+    ON transactions.processing_runs (bucket, object_key);
+
+CREATE INDEX IF NOT EXISTS idx_processing_runs_status
+    ON transactions.processing_runs (status);
+
+CREATE INDEX IF NOT EXISTS idx_transactions_account_date_id
+    ON transactions.transactions (account_id, date, id);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS transactions.replay_checkpoints
+(
+    id         bigserial PRIMARY KEY,
+    replay_id  varchar(64)  NOT NULL,
+    bucket     varchar(255) NOT NULL,
+    object_key varchar(512) NOT NULL,
+    status     varchar(16)  NOT NULL,
+    error      text,
+    created_at timestamptz DEFAULT now(),
+    updated_at timestamptz DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_replay_checkpoints_object
+    ON transactions.replay_checkpoints (replay_id, bucket, object_key);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS transactions.dead_letters
+(
+    id            bigserial PRIMARY KEY,
+    source        varchar(32) NOT NULL,
+    message_id    varchar(255),
+    bucket        varchar(255),
+    object_key    varchar(512),
+    payload       text,
+    error         text        NOT NULL,
+    receive_count integer     NOT NULL DEFAULT 0,
+    created_at    timestamptz DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_message_id
+    ON transactions.dead_letters (message_id);
+
+CREATE INDEX IF NOT EXISTS idx_dead_letters_object
+    ON transactions.dead_letters (bucket, object_key);
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS transactions.statements
+(
+    id                 bigserial PRIMARY KEY,
+    account_id         varchar(255) NOT NULL,
+    period             varchar(7)   NOT NULL,
+    status             varchar(16)  NOT NULL,
+    error              text,
+    transactions_count integer      NOT NULL DEFAULT 0,
+    created_at         timestamptz DEFAULT now(),
+    claimed_at         timestamptz NOT NULL,
+    sent_at            timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_statements_account_period
+    ON transactions.statements (account_id, period);
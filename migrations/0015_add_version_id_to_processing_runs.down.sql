@@ -0,0 +1,2 @@
+ALTER TABLE transactions.processing_runs
+    DROP COLUMN IF EXISTS version_id;
@@ -0,0 +1,2 @@
+ALTER TABLE transactions.processing_runs
+    ADD COLUMN IF NOT EXISTS version_id varchar(1024);
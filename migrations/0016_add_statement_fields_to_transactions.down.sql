@@ -0,0 +1,4 @@
+ALTER TABLE transactions.transactions
+    DROP COLUMN IF EXISTS memo,
+    DROP COLUMN IF EXISTS description,
+    DROP COLUMN IF EXISTS reference;
@@ -0,0 +1,4 @@
+ALTER TABLE transactions.transactions
+    ADD COLUMN IF NOT EXISTS reference varchar(255),
+    ADD COLUMN IF NOT EXISTS description varchar(255),
+    ADD COLUMN IF NOT EXISTS memo varchar(255);
@@ -0,0 +1,6 @@
+ALTER TABLE transactions.account_summaries
+    DROP COLUMN IF EXISTS closing_balance,
+    DROP COLUMN IF EXISTS opening_balance;
+
+ALTER TABLE transactions.transactions
+    DROP COLUMN IF EXISTS currency;
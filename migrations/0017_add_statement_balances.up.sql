@@ -0,0 +1,6 @@
+ALTER TABLE transactions.transactions
+    ADD COLUMN IF NOT EXISTS currency varchar(3);
+
+ALTER TABLE transactions.account_summaries
+    ADD COLUMN IF NOT EXISTS opening_balance numeric,
+    ADD COLUMN IF NOT EXISTS closing_balance numeric;
@@ -0,0 +1,4 @@
+ALTER TABLE transactions.processing_runs
+    DROP COLUMN IF EXISTS delimiter,
+    DROP COLUMN IF EXISTS has_bom,
+    DROP COLUMN IF EXISTS encoding;
@@ -0,0 +1,4 @@
+ALTER TABLE transactions.processing_runs
+    ADD COLUMN IF NOT EXISTS encoding varchar(32),
+    ADD COLUMN IF NOT EXISTS has_bom boolean NOT NULL DEFAULT false,
+    ADD COLUMN IF NOT EXISTS delimiter varchar(4);
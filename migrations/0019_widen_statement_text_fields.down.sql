@@ -0,0 +1,3 @@
+ALTER TABLE transactions.transactions
+    ALTER COLUMN memo TYPE varchar(255) USING left(memo, 255),
+    ALTER COLUMN description TYPE varchar(255) USING left(description, 255);
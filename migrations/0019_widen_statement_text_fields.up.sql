@@ -0,0 +1,3 @@
+ALTER TABLE transactions.transactions
+    ALTER COLUMN description TYPE text,
+    ALTER COLUMN memo TYPE text;